@@ -2,7 +2,6 @@ package acmregister
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"time"
 
@@ -21,19 +20,53 @@ var ErrMemberAlreadyExists = errors.New("a member with your information already
 // check its error to ensure that it is never invalid.
 var ErrUnknownPronouns = errors.New("unknown pronouns")
 
+// ErrAlreadyCheckedIn is returned if a member checks in to an event twice.
+var ErrAlreadyCheckedIn = errors.New("already checked in")
+
 type KnownGuild struct {
 	GuildID           discord.GuildID
 	ChannelID         discord.ChannelID
 	RoleID            discord.RoleID
 	InitUserID        discord.UserID
 	RegisteredMessage string
-	AdminRoleID       discord.RoleID // optional
+	AdminRoleID       discord.RoleID    // optional
+	RegisterMessageID discord.MessageID // optional, unknown for older guilds
+	Messages          GuildMessages
+	FormFields        []FormField // extra fields in the registration form
+	Nickname          NicknameSettings
+	ApprovalChannelID discord.ChannelID // optional, registrations need approval if set
+	AuditChannelID    discord.ChannelID // optional, registration changes are logged if set
+}
+
+// GuildMessages contains the messages that a guild can customize. Empty
+// messages mean the defaults are used.
+type GuildMessages struct {
+	// RegisterButtonLabel is the label of the Register button.
+	RegisterButtonLabel string `json:"register_button_label,omitempty"`
+	// VerifyPINMessage is the message asking the user to check their email for
+	// a PIN.
+	VerifyPINMessage string `json:"verify_pin_message,omitempty"`
+	// VerifyPINButtonLabel is the label of the button that opens the PIN
+	// modal.
+	VerifyPINButtonLabel string `json:"verify_pin_button_label,omitempty"`
+	// VerifyPINModalTitle is the title of the PIN modal.
+	VerifyPINModalTitle string `json:"verify_pin_modal_title,omitempty"`
 }
 
 type Member struct {
 	GuildID  discord.GuildID
 	UserID   discord.UserID
 	Metadata MemberMetadata
+	// RegisteredAt is when the member registered. It is zero for members that
+	// registered before this was recorded. RegisterMember uses the current
+	// time if it is zero.
+	RegisteredAt time.Time
+	// RegisteredBy is the admin that registered the member on their behalf.
+	// It is zero if the member registered themselves.
+	RegisteredBy discord.UserID
+	// DepartedAt is when the member left the guild. It is zero if the member
+	// is still in the guild.
+	DepartedAt time.Time
 }
 
 type MemberMetadata struct {
@@ -41,6 +74,14 @@ type MemberMetadata struct {
 	FirstName string   `json:"first_name"`
 	LastName  string   `json:"last_name"`
 	Pronouns  Pronouns `json:"pronouns"`
+	// Extra holds the values of the guild's custom form fields, keyed by the
+	// field names. See FormField.
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// IsZero returns true if the metadata has nothing in it.
+func (m MemberMetadata) IsZero() bool {
+	return m.Email == "" && m.FirstName == "" && m.LastName == "" && m.Pronouns == "" && len(m.Extra) == 0
 }
 
 // Name returns the first name and last if any.
@@ -53,20 +94,10 @@ func (m MemberMetadata) Name() string {
 	return name
 }
 
-// Nickname returns the nickname for the given member using their metadata.
+// Nickname returns the nickname for the given member using
+// DefaultNicknameTemplate.
 func (m MemberMetadata) Nickname() string {
-	nick := m.Name()
-
-	switch m.Pronouns {
-	case HiddenPronouns:
-		// ok
-	case AnyPronouns:
-		nick += " (any pronouns)"
-	default:
-		nick += fmt.Sprintf(" (%s)", m.Pronouns)
-	}
-
-	return nick
+	return defaultNicknameTemplate.Format(m)
 }
 
 // Pronouns describes a pronouns string in the format (they/them).
@@ -120,6 +151,10 @@ type Store interface {
 	KnownGuildStore
 	MemberStore
 	SubmissionStore
+	ApprovalStore
+	AttendanceStore
+	EventStore
+	StatsStore
 }
 
 // KnownGuildStore stores all known guilds, or guilds that are using the
@@ -133,8 +168,28 @@ type KnownGuildStore interface {
 	// GuildSetAdminRole sets the admin role for the given guild.
 	// This is a field in KnownGuild that is optional.
 	GuildSetAdminRole(discord.GuildID, discord.RoleID) error
+	// GuildSetRole sets the role that is given to registered members of the
+	// given guild.
+	GuildSetRole(discord.GuildID, discord.RoleID) error
+	// GuildSetRegisterMessage sets the channel and ID of the message with the
+	// Register button for the given guild.
+	GuildSetRegisterMessage(discord.GuildID, discord.ChannelID, discord.MessageID) error
+	// GuildSetMessages sets the registered message and the other messages of
+	// the given guild.
+	GuildSetMessages(discord.GuildID, string, GuildMessages) error
+	// GuildSetFormFields sets the custom form fields of the given guild.
+	GuildSetFormFields(discord.GuildID, []FormField) error
+	// GuildSetNickname sets how the given guild nicknames its registered
+	// members.
+	GuildSetNickname(discord.GuildID, NicknameSettings) error
+	// GuildSetApprovalChannel sets the channel that registrations are sent to
+	// for approval. A zero channel ID turns off approvals.
+	GuildSetApprovalChannel(discord.GuildID, discord.ChannelID) error
+	// GuildSetAuditChannel sets the channel that changes to the registration
+	// of the given guild are logged to. A zero channel ID turns off logging.
+	GuildSetAuditChannel(discord.GuildID, discord.ChannelID) error
 	// DeleteGuild deletes the guild with the given ID from the registered
-	// database.
+	// database. The member history is kept; see MemberEvents.
 	DeleteGuild(discord.GuildID) error
 }
 
@@ -143,10 +198,73 @@ type MemberStore interface {
 	ContainsContext
 	// MemberInfo returns the member's info for the given user.
 	MemberInfo(discord.GuildID, discord.UserID) (*MemberMetadata, error)
-	// RegisterMember registers the given member into the store.
+	// RegisterMember registers the given member into the store. A
+	// MemberRegistered event is recorded along with it.
 	RegisterMember(Member) error
-	// UnregisterMember unregisters the given member from the store.
-	UnregisterMember(discord.GuildID, discord.UserID) error
+	// UpdateMemberMetadata replaces the metadata of a registered member and
+	// deletes their saved submission. ErrMemberAlreadyExists is returned if
+	// the new email belongs to another member. A MemberEdited event is
+	// recorded along with it.
+	UpdateMemberMetadata(discord.GuildID, discord.UserID, MemberMetadata) error
+	// SetMemberDeparted marks the member as having left the guild at the given
+	// time. A zero time marks the member as being in the guild again.
+	SetMemberDeparted(discord.GuildID, discord.UserID, time.Time) error
+	// UnregisterMember unregisters the given member from the store. A
+	// MemberUnregistered event is recorded along with it, crediting the given
+	// actor, which is zero if nobody unregistered the member.
+	UnregisterMember(guildID discord.GuildID, userID, actorID discord.UserID) error
+	// ListMembers lists at most limit members starting from the given cursor.
+	// Members are always sorted by their user IDs in ascending order. An
+	// empty list is returned if there are no more members.
+	ListMembers(discord.GuildID, MemberCursor, int) ([]Member, error)
+	// SearchMembers returns at most limit members whose email or name contains
+	// the given query, ignoring case. Members are sorted by their user IDs.
+	SearchMembers(discord.GuildID, string, int) ([]Member, error)
+	// AddMemberEvent records an event in the member's history. Events that
+	// come with a change to the member are recorded by that change, so this
+	// is only for events that don't, such as MemberVerified. The current time
+	// is used if the event's time is zero.
+	AddMemberEvent(MemberEvent) error
+	// MemberEvents returns the history of the given user, oldest first. The
+	// history is kept after the user is unregistered and after the guild is
+	// deleted.
+	MemberEvents(discord.GuildID, discord.UserID) ([]MemberEvent, error)
+}
+
+// MemberEventType is the type of a MemberEvent.
+type MemberEventType string
+
+const (
+	// MemberRegistered is recorded when the member is registered.
+	MemberRegistered MemberEventType = "registered"
+	// MemberUnregistered is recorded when the member is unregistered.
+	MemberUnregistered MemberEventType = "unregistered"
+	// MemberEdited is recorded when the member changes their registration.
+	MemberEdited MemberEventType = "edited"
+	// MemberVerified is recorded when the member verifies their email.
+	MemberVerified MemberEventType = "verified"
+)
+
+// MemberEvent is an entry in the registration history of a member.
+type MemberEvent struct {
+	GuildID discord.GuildID
+	UserID  discord.UserID
+	Type    MemberEventType
+	// ActorID is the user that caused the event, which may be the member
+	// themselves. It is zero if nobody did, such as when the member is
+	// unregistered for leaving the guild.
+	ActorID discord.UserID
+	Time    time.Time
+}
+
+// MemberCursor points to a position in a list of members sorted by their user
+// IDs. The zero value points to the start of the list.
+type MemberCursor struct {
+	// UserID is the user ID to list from. The member with this ID is excluded
+	// from the list.
+	UserID discord.UserID
+	// Before, if true, lists the members before UserID instead of after it.
+	Before bool
 }
 
 // SubmissionStore stores submissions for a short while so that forms can be
@@ -159,3 +277,141 @@ type SubmissionStore interface {
 	// RestoreSubmission returns a saved submission.
 	RestoreSubmission(discord.GuildID, discord.UserID) (*MemberMetadata, error)
 }
+
+// Approval is a registration that is waiting for an admin's approval, or one
+// that has been denied.
+type Approval struct {
+	GuildID  discord.GuildID
+	UserID   discord.UserID
+	Metadata MemberMetadata
+	// SubmittedAt is when the registration was submitted. SubmitApproval uses
+	// the current time if it is zero.
+	SubmittedAt time.Time
+	// DeniedAt is when the registration was denied. It is zero if the
+	// registration is still pending.
+	DeniedAt time.Time
+	// DeniedBy is the admin that denied the registration.
+	DeniedBy discord.UserID
+	// DenyReason is the reason given for denying the registration.
+	DenyReason string
+}
+
+// IsPending returns true if the approval is still waiting for an admin.
+func (a Approval) IsPending() bool {
+	return a.DeniedAt.IsZero()
+}
+
+// ApprovalStore stores registrations that need an admin's approval.
+type ApprovalStore interface {
+	ContainsContext
+	// SubmitApproval queues the registration for approval and deletes the
+	// member's saved submission. Any earlier approval of the member is
+	// replaced. The Denied fields are ignored.
+	SubmitApproval(Approval) error
+	// ApprovalInfo returns the approval of the given member.
+	ApprovalInfo(discord.GuildID, discord.UserID) (*Approval, error)
+	// ApproveMember registers the member of a pending approval and deletes
+	// the approval. The admin that approved it is recorded in the
+	// MemberRegistered event. ErrNotFound is returned if there is no pending
+	// approval, and ErrMemberAlreadyExists is returned if the member can't be
+	// registered.
+	ApproveMember(guildID discord.GuildID, userID, approvedBy discord.UserID) (*MemberMetadata, error)
+	// DenyMember denies a pending approval. The admin that denied it and the
+	// reason are recorded. ErrNotFound is returned if there is no pending
+	// approval.
+	DenyMember(guildID discord.GuildID, userID, deniedBy discord.UserID, reason string) error
+}
+
+// Attendance is a member checking in to a scheduled event.
+type Attendance struct {
+	GuildID discord.GuildID
+	EventID discord.EventID
+	UserID  discord.UserID
+	// CheckedInAt is when the member checked in. CheckIn uses the current
+	// time if it is zero.
+	CheckedInAt time.Time
+}
+
+// AttendanceStore stores the attendance of scheduled events. Discord forgets
+// who was interested in an event once it's over, so this is kept instead.
+type AttendanceStore interface {
+	ContainsContext
+	// CheckIn records the member's attendance. ErrAlreadyCheckedIn is
+	// returned if the member has already checked in to the event.
+	CheckIn(Attendance) error
+	// EventAttendees returns everyone that checked in to the event, in the
+	// order that they checked in. The attendance of unregistered members is
+	// kept.
+	EventAttendees(discord.GuildID, discord.EventID) ([]Attendance, error)
+}
+
+// ScheduledEvent is a snapshot of a guild's scheduled event.
+type ScheduledEvent struct {
+	GuildID   discord.GuildID
+	EventID   discord.EventID
+	Name      string
+	StartTime time.Time
+	// Ended is true once the event is completed, cancelled or deleted. The
+	// participants of ended events are no longer changed.
+	Ended bool
+}
+
+// EventStore keeps snapshots of scheduled events and the users interested in
+// them, since Discord forgets both some time after the event.
+type EventStore interface {
+	ContainsContext
+	// SaveEvent saves the event, replacing any earlier snapshot of it.
+	SaveEvent(ScheduledEvent) error
+	// EventInfo returns the snapshot of the given event.
+	EventInfo(discord.GuildID, discord.EventID) (*ScheduledEvent, error)
+	// SearchEvents returns up to the given number of events whose names
+	// contain the given string, ignoring case. The latest events are returned
+	// first.
+	SearchEvents(guildID discord.GuildID, query string, limit int) ([]ScheduledEvent, error)
+	// EventsBetween returns the events that start at or after from and
+	// before to. The earliest events are returned first.
+	EventsBetween(guildID discord.GuildID, from, to time.Time) ([]ScheduledEvent, error)
+	// AddEventParticipants adds the users to the event's participants. Users
+	// that are already participants are ignored.
+	AddEventParticipants(discord.GuildID, discord.EventID, ...discord.UserID) error
+	// RemoveEventParticipant removes the user from the event's participants.
+	// Nothing happens if the user isn't one.
+	RemoveEventParticipant(discord.GuildID, discord.EventID, discord.UserID) error
+	// EventParticipants returns the IDs of the event's participants, sorted
+	// by ID.
+	EventParticipants(discord.GuildID, discord.EventID) ([]discord.UserID, error)
+}
+
+// StatsStore computes statistics of the registrations of a guild.
+type StatsStore interface {
+	ContainsContext
+	// RegistrationStats returns the statistics of the guild's registrations.
+	// Weekly registrations and PIN attempts are only counted from the given
+	// time onwards.
+	RegistrationStats(guildID discord.GuildID, since time.Time) (*RegistrationStats, error)
+}
+
+// RegistrationStats is the statistics of the registrations of a guild.
+type RegistrationStats struct {
+	// Members is the number of registered members.
+	Members int
+	// Weekly is the number of members registered in each week, where the
+	// first week starts at the requested time and the last week is the
+	// current one. Members registered before registration times were recorded
+	// are not counted.
+	Weekly []int
+	// EmailHosts is the number of members by the host of their email.
+	EmailHosts map[string]int
+	// Pronouns is the number of members by their pronouns. Members who didn't
+	// give any are counted under the empty string.
+	Pronouns map[Pronouns]int
+	// PendingSubmissions is the number of saved submissions that haven't
+	// expired, which includes registrations waiting for email verification.
+	PendingSubmissions int
+	// PendingApprovals is the number of registrations waiting for approval.
+	PendingApprovals int
+	// PINAttempts is the number of times a PIN was entered into ValidatePIN,
+	// and PINSuccesses is the number of times it was correct.
+	PINAttempts  int
+	PINSuccesses int
+}
@@ -0,0 +1,106 @@
+package bot
+
+import (
+	"fmt"
+
+	"github.com/diamondburned/acmregister/acmregister"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/pkg/errors"
+)
+
+// Colors of the audit log embeds.
+const (
+	auditColorAdded   discord.Color = 0x2ECC71
+	auditColorChanged discord.Color = 0x3498DB
+	auditColorRemoved discord.Color = 0xE74C3C
+)
+
+// audit posts the embed to the guild's audit channel, if it has one. The
+// embed is timestamped and, if actorID is valid, credited to that user.
+// Failing to post is only logged, since the change has already been made.
+func (h *Handler) audit(guild *acmregister.KnownGuild, actorID discord.UserID, embed discord.Embed) {
+	if !guild.AuditChannelID.IsValid() {
+		return
+	}
+
+	embed.Timestamp = discord.NowTimestamp()
+	if actorID.IsValid() {
+		embed.Fields = append(embed.Fields, discord.EmbedField{
+			Name:  "By",
+			Value: actorID.Mention(),
+		})
+	}
+
+	if _, err := h.s.SendMessageComplex(guild.AuditChannelID, api.SendMessageData{
+		Embeds:          []discord.Embed{embed},
+		AllowedMentions: &api.AllowedMentions{},
+	}); err != nil {
+		h.LogErr(guild.GuildID, errors.Wrap(err, "cannot post to audit channel"))
+	}
+}
+
+// auditRegistered logs the member being registered. actorID is whoever
+// registered or approved them, which may be the member themselves.
+func (h *Handler) auditRegistered(guild *acmregister.KnownGuild, actorID, userID discord.UserID, metadata acmregister.MemberMetadata) {
+	h.audit(guild, actorID, discord.Embed{
+		Title:       "Member Registered",
+		Description: userID.Mention() + " has been registered.",
+		Color:       auditColorAdded,
+		Fields:      memberEmbedFields(guild, metadata),
+	})
+}
+
+// auditEdited logs the member changing their registration.
+func (h *Handler) auditEdited(guild *acmregister.KnownGuild, userID discord.UserID, metadata acmregister.MemberMetadata) {
+	h.audit(guild, userID, discord.Embed{
+		Title:       "Registration Edited",
+		Description: userID.Mention() + " has changed their registration.",
+		Color:       auditColorChanged,
+		Fields:      memberEmbedFields(guild, metadata),
+	})
+}
+
+// auditUnregistered logs the member being unregistered for the given reason.
+// actorID is zero if nobody unregistered them, such as when they left the
+// guild.
+func (h *Handler) auditUnregistered(guild *acmregister.KnownGuild, actorID, userID discord.UserID, reason string) {
+	h.audit(guild, actorID, discord.Embed{
+		Title:       "Member Unregistered",
+		Description: userID.Mention() + " " + reason,
+		Color:       auditColorRemoved,
+	})
+}
+
+// auditDenied logs the member's registration being denied.
+func (h *Handler) auditDenied(guild *acmregister.KnownGuild, actorID, userID discord.UserID, reason string) {
+	h.audit(guild, actorID, discord.Embed{
+		Title:       "Registration Denied",
+		Description: userID.Mention() + "'s registration has been denied.",
+		Color:       auditColorRemoved,
+		Fields: []discord.EmbedField{
+			{Name: "Reason", Value: truncate(reason, 1024)},
+		},
+	})
+}
+
+// auditNicknameReset logs the member's nickname being reset.
+func (h *Handler) auditNicknameReset(guild *acmregister.KnownGuild, actorID, userID discord.UserID, metadata acmregister.MemberMetadata) {
+	nick, _ := guild.Nickname.Nickname(metadata)
+	h.audit(guild, actorID, discord.Embed{
+		Title:       "Nickname Reset",
+		Description: fmt.Sprintf("%s has been nicknamed **%s**.", userID.Mention(), nick),
+		Color:       auditColorChanged,
+	})
+}
+
+// auditSettings logs a change to the guild's settings. The description says
+// what has changed.
+func (h *Handler) auditSettings(guild *acmregister.KnownGuild, actorID discord.UserID, command, description string) {
+	h.audit(guild, actorID, discord.Embed{
+		Title:       "Settings Changed",
+		Description: truncate(description, 4096),
+		Color:       auditColorChanged,
+		Footer:      &discord.EmbedFooter{Text: command},
+	})
+}
@@ -19,8 +19,8 @@ import (
 type ConfirmationEmailScheduler interface {
 	// ScheduleConfirmationEmail asynchronously schedules an email to be sent in
 	// the background. It has no error reporting; the implementation is expected
-	// to use the InteractionEvent to send a reply.
-	ScheduleConfirmationEmail(c *Client, ev *discord.InteractionEvent, m acmregister.Member) error
+	// to use the InteractionEvent to send a reply using the guild's messages.
+	ScheduleConfirmationEmail(c *Client, ev *discord.InteractionEvent, m acmregister.Member, msgs acmregister.GuildMessages) error
 	// Close cancels any scheduled jobs, if any.
 	Close() error
 }
@@ -49,10 +49,10 @@ func (s *asyncConfirmationEmailSender) Close() error {
 	return nil
 }
 
-func (s *asyncConfirmationEmailSender) ScheduleConfirmationEmail(c *Client, ev *discord.InteractionEvent, m acmregister.Member) error {
+func (s *asyncConfirmationEmailSender) ScheduleConfirmationEmail(c *Client, ev *discord.InteractionEvent, m acmregister.Member, msgs acmregister.GuildMessages) error {
 	s.wg.Add(1)
 	go func() {
-		SendConfirmationEmail(s.ctx, s.smtp, c, ev, m)
+		SendConfirmationEmail(s.ctx, s.smtp, c, ev, m, msgs)
 		s.wg.Done()
 	}()
 	return nil
@@ -62,7 +62,7 @@ func (s *asyncConfirmationEmailSender) ScheduleConfirmationEmail(c *Client, ev *
 // interaction event.
 func SendConfirmationEmail(
 	ctx context.Context, smtpVerifier *verifyemail.SMTPVerifier,
-	c *Client, ev *discord.InteractionEvent, m acmregister.Member) {
+	c *Client, ev *discord.InteractionEvent, m acmregister.Member, msgs acmregister.GuildMessages) {
 
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
@@ -72,21 +72,23 @@ func SendConfirmationEmail(
 		return
 	}
 
-	c.FollowUp(ev, EmailSentFollowupData())
+	c.FollowUp(ev, EmailSentFollowupData(msgs))
 }
 
 // EmailSentFollowupData creates an *api.InteractionResponseData to be used as a
-// reply to notify the user that the email has been delivered.
-func EmailSentFollowupData() *api.InteractionResponseData {
+// reply to notify the user that the email has been delivered. Empty messages in
+// msgs use the defaults.
+func EmailSentFollowupData(msgs acmregister.GuildMessages) *api.InteractionResponseData {
+	msgs = messagesWithDefaults(msgs)
 	return &api.InteractionResponseData{
 		Flags:   discord.EphemeralMessage,
-		Content: option.NewNullableString(verifyPINMessage),
+		Content: option.NewNullableString(msgs.VerifyPINMessage),
 		Components: &discord.ContainerComponents{
 			&discord.ActionRowComponent{
 				&discord.ButtonComponent{
 					Style:    discord.PrimaryButtonStyle(),
 					CustomID: "verify-pin",
-					Label:    verifyPINButtonLabel,
+					Label:    msgs.VerifyPINButtonLabel,
 				},
 			},
 		},
@@ -0,0 +1,262 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diamondburned/acmregister/acmregister"
+	"github.com/diamondburned/acmregister/acmregister/logger"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/pkg/errors"
+)
+
+// maxDenyReason is the maximum length of the reason for denying a
+// registration.
+const maxDenyReason = 500
+
+// submitForApproval queues the registration for approval and posts it to the
+// guild's approval channel.
+func (h *Handler) submitForApproval(ev *discord.InteractionEvent, guild *acmregister.KnownGuild, metadata acmregister.MemberMetadata) *api.InteractionResponse {
+	if err := h.store.SubmitApproval(acmregister.Approval{
+		GuildID:  ev.GuildID,
+		UserID:   ev.SenderID(),
+		Metadata: metadata,
+	}); err != nil {
+		h.PrivateWarning(ev, errors.Wrap(err, "cannot save registration for approval"))
+		return InternalErrorResponse()
+	}
+
+	userID := ev.SenderID()
+
+	if _, err := h.s.SendMessageComplex(guild.ApprovalChannelID, api.SendMessageData{
+		Embeds: []discord.Embed{{
+			Title:       "Registration Awaiting Approval",
+			Description: userID.Mention() + " (" + ev.Sender().Tag() + ") wants to register.",
+			Fields:      memberEmbedFields(guild, metadata),
+		}},
+		Components: discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.ButtonComponent{
+					Style:    discord.SuccessButtonStyle(),
+					CustomID: discord.ComponentID("approve-registration:" + userID.String()),
+					Label:    "Approve",
+				},
+				&discord.ButtonComponent{
+					Style:    discord.DangerButtonStyle(),
+					CustomID: discord.ComponentID("deny-registration:" + userID.String()),
+					Label:    "Deny",
+				},
+			},
+		},
+		AllowedMentions: &api.AllowedMentions{},
+	}); err != nil {
+		h.PrivateWarning(ev, errors.Wrap(err, "cannot post registration to the approval channel"))
+		return InternalErrorResponse()
+	}
+
+	return msgResponse(&api.InteractionResponseData{
+		Flags: discord.EphemeralMessage,
+		Content: option.NewNullableString("" +
+			"Your registration has been sent to the moderators for approval. " +
+			"You'll get a DM once it has been reviewed."),
+	})
+}
+
+// memberEmbedFields returns the embed fields describing the member's
+// registration, including the guild's custom form fields.
+func memberEmbedFields(guild *acmregister.KnownGuild, metadata acmregister.MemberMetadata) []discord.EmbedField {
+	orNotSet := func(v string) string {
+		if v == "" {
+			return "*not set*"
+		}
+		return v
+	}
+
+	fields := []discord.EmbedField{
+		{Name: "Name", Value: metadata.Name(), Inline: true},
+		{Name: "Email", Value: string(metadata.Email), Inline: true},
+		{Name: "Pronouns", Value: orNotSet(pronounsText(metadata.Pronouns)), Inline: true},
+	}
+	for _, field := range guild.FormFields {
+		fields = append(fields, discord.EmbedField{
+			Name:  field.Label,
+			Value: orNotSet(truncate(metadata.Extra[field.Name], 1024)),
+		})
+	}
+
+	return fields
+}
+
+// parseApprovalUserID parses the user ID in the custom ID of an approval
+// button or modal.
+func parseApprovalUserID(arg string) (discord.UserID, error) {
+	id, err := discord.ParseSnowflake(arg)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid user")
+	}
+	return discord.UserID(id), nil
+}
+
+// approvalHandled replaces the buttons of the approval message with the
+// outcome. The embed of the message is kept.
+func approvalHandled(content string) *api.InteractionResponse {
+	return &api.InteractionResponse{
+		Type: api.UpdateMessage,
+		Data: &api.InteractionResponseData{
+			Content:         option.NewNullableString(content),
+			Components:      &discord.ContainerComponents{},
+			AllowedMentions: &api.AllowedMentions{},
+		},
+	}
+}
+
+func (h *Handler) buttonApproveRegistration(ev *discord.InteractionEvent, arg string) *api.InteractionResponse {
+	if err := h.authorizeAdmin(ev); err != nil {
+		return ErrorResponse(err)
+	}
+
+	userID, err := parseApprovalUserID(arg)
+	if err != nil {
+		return ErrorResponse(err)
+	}
+
+	guild, err := h.store.GuildInfo(ev.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", ev.GuildID, "reason:", err)
+		return nil
+	}
+
+	metadata, err := h.store.ApproveMember(guild.GuildID, userID, ev.SenderID())
+	if err != nil {
+		switch {
+		case errors.Is(err, acmregister.ErrNotFound):
+			return ErrorResponse(errors.New("this registration has already been handled"))
+		case errors.Is(err, acmregister.ErrMemberAlreadyExists):
+			return ErrorResponse(errors.New("a member with the same information is already registered"))
+		default:
+			h.PrivateWarning(ev, errors.Wrap(err, "cannot approve member"))
+			return InternalErrorResponse()
+		}
+	}
+
+	h.auditRegistered(guild, ev.SenderID(), userID, *metadata)
+
+	content := "✅ Approved by " + ev.SenderID().Mention() + "."
+
+	if err := h.assignRegistered(guild, userID, *metadata); err != nil {
+		h.PrivateWarning(ev, err)
+		content += " The member is registered, but the role couldn't be given to them."
+	}
+
+	if err := h.DirectMessage(userID, fmt.Sprintf(
+		"Your registration in **%s** has been approved! %s",
+		h.guildName(guild.GuildID), guildRegisteredMessage(guild),
+	)); err != nil {
+		h.PrivateWarning(ev, errors.Wrap(err, "cannot DM approved member (not important)"))
+		content += " They couldn't be notified by DM."
+	}
+
+	return approvalHandled(content)
+}
+
+func (h *Handler) buttonDenyRegistration(ev *discord.InteractionEvent, arg string) *api.InteractionResponse {
+	if err := h.authorizeAdmin(ev); err != nil {
+		return ErrorResponse(err)
+	}
+
+	userID, err := parseApprovalUserID(arg)
+	if err != nil {
+		return ErrorResponse(err)
+	}
+
+	approval, err := h.store.ApprovalInfo(ev.GuildID, userID)
+	if err != nil || !approval.IsPending() {
+		return ErrorResponse(errors.New("this registration has already been handled"))
+	}
+
+	return &api.InteractionResponse{
+		Type: api.ModalResponse,
+		Data: &api.InteractionResponseData{
+			CustomID: option.NewNullableString("deny-registration:" + userID.String()),
+			Title:    option.NewNullableString("Deny Registration"),
+			Components: &discord.ContainerComponents{
+				&discord.ActionRowComponent{
+					&discord.TextInputComponent{
+						CustomID:     "reason",
+						Label:        "Reason (sent to the member)",
+						Style:        discord.TextInputParagraphStyle,
+						Required:     true,
+						LengthLimits: [2]int{1, maxDenyReason},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (h *Handler) modalDenyRegistration(ev *discord.InteractionEvent, modal *discord.ModalInteraction, arg string) *api.InteractionResponse {
+	if err := h.authorizeAdmin(ev); err != nil {
+		return ErrorResponse(err)
+	}
+
+	userID, err := parseApprovalUserID(arg)
+	if err != nil {
+		return ErrorResponse(err)
+	}
+
+	var data struct {
+		Reason string `discord:"reason"`
+	}
+
+	if err := modal.Components.Unmarshal(&data); err != nil {
+		return ErrorResponse(err)
+	}
+
+	reason := strings.TrimSpace(data.Reason)
+	if reason == "" {
+		return ErrorResponse(errors.New("a reason is required"))
+	}
+
+	guild, err := h.store.GuildInfo(ev.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", ev.GuildID, "reason:", err)
+		return nil
+	}
+
+	if err := h.store.DenyMember(guild.GuildID, userID, ev.SenderID(), reason); err != nil {
+		if errors.Is(err, acmregister.ErrNotFound) {
+			return ErrorResponse(errors.New("this registration has already been handled"))
+		}
+		h.PrivateWarning(ev, errors.Wrap(err, "cannot deny member"))
+		return InternalErrorResponse()
+	}
+
+	h.auditDenied(guild, ev.SenderID(), userID, reason)
+
+	content := "❌ Denied by " + ev.SenderID().Mention() + ": " + reason
+
+	if err := h.DirectMessage(userID, fmt.Sprintf(
+		"Your registration in **%s** has been denied: %s\n"+
+			"You can fix your registration and register again.",
+		h.guildName(guild.GuildID), reason,
+	)); err != nil {
+		h.PrivateWarning(ev, errors.Wrap(err, "cannot DM denied member (not important)"))
+		content += "\nThey couldn't be notified by DM."
+	}
+
+	return approvalHandled(content)
+}
+
+// guildName returns the name of the guild, or a generic name if it can't be
+// fetched.
+func (h *Handler) guildName(guildID discord.GuildID) string {
+	guild, err := h.s.Guild(guildID)
+	if err != nil {
+		return "the server"
+	}
+	return guild.Name
+}
@@ -0,0 +1,127 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/diamondburned/acmregister/acmregister"
+	"github.com/diamondburned/acmregister/acmregister/logger"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/api/cmdroute"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/pkg/errors"
+)
+
+// parseEventID parses the event ID given to an event option or carried in a
+// button's custom ID.
+func parseEventID(s string) (discord.EventID, error) {
+	id, err := discord.ParseSnowflake(s)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid event ID")
+	}
+	return discord.EventID(id), nil
+}
+
+// eventEnded returns true if members can no longer check in to the event.
+func eventEnded(event *discord.GuildScheduledEvent) bool {
+	return event.Status == discord.CompletedEvent || event.Status == discord.CancelledEvent
+}
+
+// cmdEventCheckIn posts a message with a Check In button for the event.
+func (h *Handler) cmdEventCheckIn(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	if err := h.authorizeAdmin(cmdData.Event); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	var data struct {
+		Event string `discord:"event"`
+	}
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	eventID, err := parseEventID(data.Event)
+	if err != nil {
+		return ErrorResponseData(err)
+	}
+
+	event, err := h.s.ScheduledEvent(guild.GuildID, eventID, false)
+	if err != nil {
+		return ErrorResponseData(errors.Wrap(err, "cannot find event"))
+	}
+
+	if eventEnded(event) {
+		return ErrorResponseData(errors.New("this event has already ended"))
+	}
+
+	return &api.InteractionResponseData{
+		Embeds: &[]discord.Embed{{
+			Title: "Check In: " + event.Name,
+			Description: fmt.Sprintf(""+
+				"Registered members can press **Check In** to record their attendance. "+
+				"The event starts <t:%d:R>.",
+				event.StartTime.Time().Unix(),
+			),
+		}},
+		Components: &discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.ButtonComponent{
+					Style:    discord.SuccessButtonStyle(),
+					CustomID: discord.ComponentID("check-in:" + eventID.String()),
+					Label:    "Check In",
+				},
+			},
+		},
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+func (h *Handler) buttonCheckIn(ev *discord.InteractionEvent, arg string) *api.InteractionResponse {
+	eventID, err := parseEventID(arg)
+	if err != nil {
+		return ErrorResponse(err)
+	}
+
+	guild, err := h.store.GuildInfo(ev.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", ev.GuildID, "reason:", err)
+		return nil
+	}
+
+	if _, err := h.store.MemberInfo(guild.GuildID, ev.SenderID()); err != nil {
+		return ErrorResponse(errors.New("only registered members can check in, press Register first"))
+	}
+
+	// Discord deletes events some time after they end.
+	event, err := h.s.ScheduledEvent(guild.GuildID, eventID, false)
+	if err != nil || eventEnded(event) {
+		return ErrorResponse(errors.New("this event has already ended"))
+	}
+
+	if err := h.store.CheckIn(acmregister.Attendance{
+		GuildID: guild.GuildID,
+		EventID: eventID,
+		UserID:  ev.SenderID(),
+	}); err != nil {
+		if errors.Is(err, acmregister.ErrAlreadyCheckedIn) {
+			return ErrorResponse(errors.New("you've already checked in to this event"))
+		}
+		h.PrivateWarning(ev, errors.Wrap(err, "cannot check in"))
+		return InternalErrorResponse()
+	}
+
+	return msgResponse(&api.InteractionResponseData{
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString("You've checked in to **" + event.Name + "**. Enjoy!"),
+		AllowedMentions: &api.AllowedMentions{},
+	})
+}
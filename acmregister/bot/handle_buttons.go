@@ -1,63 +1,207 @@
 package bot
 
 import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/diamondburned/acmregister/acmregister"
 	"github.com/diamondburned/acmregister/acmregister/logger"
 	"github.com/diamondburned/acmregister/acmregister/verifyemail"
 	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/api/cmdroute"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/utils/json/option"
 	"github.com/pkg/errors"
 )
 
-func (h *Handler) makeRegisterModal(data acmregister.MemberMetadata) *api.InteractionResponseData {
-	return &api.InteractionResponseData{
-		CustomID: option.NewNullableString("register-response"),
-		Title:    option.NewNullableString("Register"),
-		Components: &discord.ContainerComponents{
-			&discord.ActionRowComponent{
-				&discord.TextInputComponent{
-					CustomID:     "email",
-					Label:        "Email",
-					Value:        string(data.Email),
-					Placeholder:  h.opts.EmailHosts.String() + " only",
-					Style:        discord.TextInputShortStyle,
-					Required:     true,
-					LengthLimits: [2]int{0, 150},
-				},
+// registerForm describes a flow that uses the register form. Its custom IDs
+// are prefixed with the form's name.
+type registerForm struct {
+	name    string
+	title   string
+	restart string // how to start the form over
+}
+
+var (
+	// newRegistrationForm is filled in by members that are registering.
+	newRegistrationForm = registerForm{
+		name:    "register",
+		title:   "Register",
+		restart: "press Register again",
+	}
+	// editRegistrationForm is filled in by registered members that are
+	// editing their registration.
+	editRegistrationForm = registerForm{
+		name:    "edit",
+		title:   "Edit Registration",
+		restart: "use /my-registration edit again",
+	}
+)
+
+func (f registerForm) pageTitle(fields []acmregister.FormField, page int) string {
+	if pages := formFieldPages(fields); pages > 1 {
+		return fmt.Sprintf("%s (%d/%d)", f.title, page+1, pages)
+	}
+	return f.title
+}
+
+func (h *Handler) makeRegisterModal(form registerForm, data acmregister.MemberMetadata, fields []acmregister.FormField) *api.InteractionResponseData {
+	components := discord.ContainerComponents{
+		&discord.ActionRowComponent{
+			&discord.TextInputComponent{
+				CustomID:     "email",
+				Label:        "Email",
+				Value:        string(data.Email),
+				Placeholder:  h.opts.EmailHosts.String() + " only",
+				Style:        discord.TextInputShortStyle,
+				Required:     true,
+				LengthLimits: [2]int{0, 150},
 			},
-			&discord.ActionRowComponent{
-				&discord.TextInputComponent{
-					CustomID:     "first",
-					Label:        "First Name",
-					Value:        data.FirstName,
-					Style:        discord.TextInputShortStyle,
-					Required:     true,
-					LengthLimits: [2]int{0, 45},
-				},
+		},
+		&discord.ActionRowComponent{
+			&discord.TextInputComponent{
+				CustomID:     "first",
+				Label:        "First Name",
+				Value:        data.FirstName,
+				Style:        discord.TextInputShortStyle,
+				Required:     true,
+				LengthLimits: [2]int{0, 45},
 			},
-			&discord.ActionRowComponent{
-				&discord.TextInputComponent{
-					CustomID:     "last",
-					Label:        "Last Name (optional)",
-					Value:        data.LastName,
-					Style:        discord.TextInputShortStyle,
-					LengthLimits: [2]int{0, 45},
-				},
+		},
+		&discord.ActionRowComponent{
+			&discord.TextInputComponent{
+				CustomID:     "last",
+				Label:        "Last Name (optional)",
+				Value:        data.LastName,
+				Style:        discord.TextInputShortStyle,
+				LengthLimits: [2]int{0, 45},
 			},
+		},
+		&discord.ActionRowComponent{
+			&discord.TextInputComponent{
+				CustomID:     "pronouns",
+				Label:        "Pronouns (optional)",
+				Style:        discord.TextInputShortStyle,
+				Required:     false,
+				LengthLimits: [2]int{0, 45},
+				Value:        string(data.Pronouns),
+				Placeholder:  "he/him, she/her, they/them, or any",
+			},
+		},
+	}
+
+	return &api.InteractionResponseData{
+		CustomID:   option.NewNullableString(form.name + "-response"),
+		Title:      option.NewNullableString(form.pageTitle(fields, 0)),
+		Components: appendFormFieldInputs(&components, formFieldPage(fields, 0), data.Extra),
+	}
+}
+
+// maxModalInputs is the maximum number of inputs that Discord allows in a
+// modal. firstPageFormFields is the number of custom form fields that fit in the
+// register modal after its 4 built-in inputs.
+const (
+	maxModalInputs      = 5
+	firstPageFormFields = maxModalInputs - 4
+)
+
+// formFieldPages returns the number of modal pages that the register form
+// needs for the given custom fields. The first page is the register modal.
+func formFieldPages(fields []acmregister.FormField) int {
+	if len(fields) <= firstPageFormFields {
+		return 1
+	}
+	rest := len(fields) - firstPageFormFields
+	return 1 + (rest+maxModalInputs-1)/maxModalInputs
+}
+
+// formFieldPage returns the custom fields on the given page of the register
+// form.
+func formFieldPage(fields []acmregister.FormField, page int) []acmregister.FormField {
+	start, end := 0, firstPageFormFields
+	if page > 0 {
+		start = firstPageFormFields + (page-1)*maxModalInputs
+		end = start + maxModalInputs
+	}
+	if start > len(fields) {
+		return nil
+	}
+	if end > len(fields) {
+		end = len(fields)
+	}
+	return fields[start:end]
+}
+
+// formFieldInputID returns the custom ID of the text input for the given
+// field.
+func formFieldInputID(field acmregister.FormField) discord.ComponentID {
+	return discord.ComponentID("field-" + field.Name)
+}
+
+// appendFormFieldInputs appends a text input for each of the given fields,
+// pre-filled from extra.
+func appendFormFieldInputs(components *discord.ContainerComponents, fields []acmregister.FormField, extra map[string]string) *discord.ContainerComponents {
+	for _, field := range fields {
+		min, max := field.LengthLimits()
+		*components = append(*components, &discord.ActionRowComponent{
+			&discord.TextInputComponent{
+				CustomID:     formFieldInputID(field),
+				Label:        field.Label,
+				Value:        extra[field.Name],
+				Style:        discord.TextInputShortStyle,
+				Required:     field.Required,
+				LengthLimits: [2]int{min, max},
+			},
+		})
+	}
+	return components
+}
+
+// readFormFieldInputs sets the values of the given fields in extra from the
+// submitted modal.
+func readFormFieldInputs(modal *discord.ModalInteraction, fields []acmregister.FormField, extra map[string]string) {
+	for _, field := range fields {
+		input, ok := modal.Components.Find(formFieldInputID(field)).(*discord.TextInputComponent)
+		if !ok {
+			continue
+		}
+		if value := strings.TrimSpace(input.Value); value != "" {
+			extra[field.Name] = value
+		} else {
+			delete(extra, field.Name)
+		}
+	}
+}
+
+// makeFormFieldsModal creates the modal for the given page of the register
+// form after the first one.
+func makeFormFieldsModal(form registerForm, fields []acmregister.FormField, page int, extra map[string]string) *api.InteractionResponseData {
+	return &api.InteractionResponseData{
+		CustomID:   option.NewNullableString(fmt.Sprintf("%s-fields:%d", form.name, page)),
+		Title:      option.NewNullableString(form.pageTitle(fields, page)),
+		Components: appendFormFieldInputs(&discord.ContainerComponents{}, formFieldPage(fields, page), extra),
+	}
+}
+
+// registerContinueResponse asks the user to continue to the given page of the
+// register form. Modals can't be opened from modals, so a button is needed.
+func registerContinueResponse(form registerForm, fields []acmregister.FormField, page int) *api.InteractionResponse {
+	return msgResponse(&api.InteractionResponseData{
+		Flags: discord.EphemeralMessage,
+		Content: option.NewNullableString(fmt.Sprintf(
+			"Almost done! Press Continue to fill in page %d of %d.", page+1, formFieldPages(fields))),
+		Components: &discord.ContainerComponents{
 			&discord.ActionRowComponent{
-				&discord.TextInputComponent{
-					CustomID:     "pronouns",
-					Label:        "Pronouns (optional)",
-					Style:        discord.TextInputShortStyle,
-					Required:     false,
-					LengthLimits: [2]int{0, 45},
-					Value:        string(data.Pronouns),
-					Placeholder:  "he/him, she/her, they/them, or any",
+				&discord.ButtonComponent{
+					Style:    discord.PrimaryButtonStyle(),
+					CustomID: discord.ComponentID(fmt.Sprintf("%s-continue:%d", form.name, page)),
+					Label:    "Continue",
 				},
 			},
 		},
-	}
+	})
 }
 
 func (h *Handler) buttonRegister(ev *discord.InteractionEvent) *api.InteractionResponse {
@@ -73,6 +217,10 @@ func (h *Handler) buttonRegister(ev *discord.InteractionEvent) *api.InteractionR
 		return h.assignThenRespond(ev, guild, *metadata)
 	}
 
+	if approval, err := h.store.ApprovalInfo(ev.GuildID, ev.SenderID()); err == nil && approval.IsPending() {
+		return ErrorResponse(errors.New("your registration is still waiting for approval"))
+	}
+
 	metadata, err := h.store.RestoreSubmission(ev.GuildID, ev.SenderID())
 	if err != nil {
 		if !errors.Is(err, acmregister.ErrNotFound) {
@@ -83,29 +231,31 @@ func (h *Handler) buttonRegister(ev *discord.InteractionEvent) *api.InteractionR
 
 	return &api.InteractionResponse{
 		Type: api.ModalResponse,
-		Data: h.makeRegisterModal(*metadata),
+		Data: h.makeRegisterModal(newRegistrationForm, *metadata, guild.FormFields),
 	}
 }
 
-var verifyPINModal = &api.InteractionResponseData{
-	CustomID: option.NewNullableString("verify-pin"),
-	Title:    option.NewNullableString("Verify your PIN code"),
-	Components: &discord.ContainerComponents{
-		&discord.ActionRowComponent{
-			&discord.TextInputComponent{
-				CustomID:     "pin",
-				Label:        "PIN code",
-				Placeholder:  verifyemail.InvalidPIN.Format(),
-				Style:        discord.TextInputShortStyle,
-				Required:     true,
-				LengthLimits: [2]int{verifyemail.PINDigits, verifyemail.PINDigits},
+func makeVerifyPINModal(msgs acmregister.GuildMessages) *api.InteractionResponseData {
+	return &api.InteractionResponseData{
+		CustomID: option.NewNullableString("verify-pin"),
+		Title:    option.NewNullableString(messagesWithDefaults(msgs).VerifyPINModalTitle),
+		Components: &discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.TextInputComponent{
+					CustomID:     "pin",
+					Label:        "PIN code",
+					Placeholder:  verifyemail.InvalidPIN.Format(),
+					Style:        discord.TextInputShortStyle,
+					Required:     true,
+					LengthLimits: [2]int{verifyemail.PINDigits, verifyemail.PINDigits},
+				},
 			},
 		},
-	},
+	}
 }
 
 func (h *Handler) buttonVerifyPIN(ev *discord.InteractionEvent) *api.InteractionResponse {
-	_, err := h.store.GuildInfo(ev.GuildID)
+	guild, err := h.store.GuildInfo(ev.GuildID)
 	if err != nil {
 		logger := logger.FromContext(h.ctx)
 		logger.Println("ignoring guild", ev.GuildID, "reason:", err)
@@ -119,6 +269,89 @@ func (h *Handler) buttonVerifyPIN(ev *discord.InteractionEvent) *api.Interaction
 
 	return &api.InteractionResponse{
 		Type: api.ModalResponse,
-		Data: verifyPINModal,
+		Data: makeVerifyPINModal(guild.Messages),
+	}
+}
+
+func (h *Handler) buttonListMembers(ev *discord.InteractionEvent, arg string) *api.InteractionResponse {
+	if err := h.authorizeAdmin(ev); err != nil {
+		return ErrorResponse(err)
+	}
+
+	direction, userID, _ := strings.Cut(arg, ":")
+
+	id, err := discord.ParseSnowflake(userID)
+	if err != nil {
+		return ErrorResponse(errors.Wrap(err, "invalid page"))
+	}
+
+	cursor := acmregister.MemberCursor{
+		UserID: discord.UserID(id),
+		Before: direction == "before",
+	}
+
+	data, err := h.memberListPage(ev.GuildID, cursor)
+	if err != nil {
+		h.LogErr(ev.GuildID, err)
+		return InternalErrorResponse()
+	}
+
+	return &api.InteractionResponse{
+		Type: api.UpdateMessage,
+		Data: data,
+	}
+}
+
+func (h *Handler) buttonClearRegistration(ev *discord.InteractionEvent, arg string) *api.InteractionResponse {
+	if err := h.authorizeAdmin(ev); err != nil {
+		return ErrorResponse(err)
+	}
+
+	if arg == "cancel" {
+		return &api.InteractionResponse{
+			Type: api.UpdateMessage,
+			Data: &api.InteractionResponseData{
+				Content:    option.NewNullableString("Cancelled. Nothing has been cleared."),
+				Components: &discord.ContainerComponents{},
+			},
+		}
+	}
+
+	removeRole := arg == "remove-role"
+
+	deferrable := cmdroute.Deferrable(h.s, cmdroute.DeferOpts{Flags: discord.EphemeralMessage})
+	handler := deferrable(cmdroute.InteractionHandlerFunc(
+		func(ctx context.Context, ev *discord.InteractionEvent) *api.InteractionResponse {
+			return &api.InteractionResponse{
+				Type: api.MessageInteractionWithSource,
+				Data: h.clearRegistration(ctx, ev, removeRole),
+			}
+		},
+	))
+
+	return handler.HandleInteraction(h.ctx, ev)
+}
+
+func (h *Handler) buttonRegisterContinue(ev *discord.InteractionEvent, form registerForm, arg string) *api.InteractionResponse {
+	guild, err := h.store.GuildInfo(ev.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", ev.GuildID, "reason:", err)
+		return nil
+	}
+
+	page, err := strconv.Atoi(arg)
+	if err != nil || page < 1 || page >= formFieldPages(guild.FormFields) {
+		return ErrorResponse(errors.New("the registration form has changed, " + form.restart))
+	}
+
+	metadata, err := h.store.RestoreSubmission(ev.GuildID, ev.SenderID())
+	if err != nil {
+		return ErrorResponse(errors.New("your submission has expired, " + form.restart))
+	}
+
+	return &api.InteractionResponse{
+		Type: api.ModalResponse,
+		Data: makeFormFieldsModal(form, guild.FormFields, page, metadata.Extra),
 	}
 }
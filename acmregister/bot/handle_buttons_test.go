@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/diamondburned/acmregister/acmregister"
+)
+
+func TestFormFieldPages(t *testing.T) {
+	fields := func(n int) []acmregister.FormField {
+		fields := make([]acmregister.FormField, n)
+		for i := range fields {
+			fields[i] = acmregister.FormField{Name: fmt.Sprintf("field%d", i)}
+		}
+		return fields
+	}
+
+	names := func(fields []acmregister.FormField) []string {
+		names := make([]string, len(fields))
+		for i, field := range fields {
+			names[i] = field.Name
+		}
+		return names
+	}
+
+	tests := []struct {
+		fields int
+		// pages are the field names of each page.
+		pages [][]string
+	}{
+		{
+			fields: 0,
+			pages:  [][]string{{}},
+		},
+		{
+			fields: 1,
+			pages:  [][]string{{"field0"}},
+		},
+		{
+			fields: 2,
+			pages:  [][]string{{"field0"}, {"field1"}},
+		},
+		{
+			fields: 6,
+			pages: [][]string{
+				{"field0"},
+				{"field1", "field2", "field3", "field4", "field5"},
+			},
+		},
+		{
+			fields: 7,
+			pages: [][]string{
+				{"field0"},
+				{"field1", "field2", "field3", "field4", "field5"},
+				{"field6"},
+			},
+		},
+		{
+			fields: acmregister.MaxFormFields,
+			pages: [][]string{
+				{"field0"},
+				{"field1", "field2", "field3", "field4", "field5"},
+				{"field6", "field7", "field8", "field9"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d fields", test.fields), func(t *testing.T) {
+			fields := fields(test.fields)
+
+			if pages := formFieldPages(fields); pages != len(test.pages) {
+				t.Fatalf("expected %d pages, got %d", len(test.pages), pages)
+			}
+
+			for page, expected := range test.pages {
+				got := names(formFieldPage(fields, page))
+				if !reflect.DeepEqual(got, expected) {
+					t.Errorf("unexpected fields on page %d\n"+
+						"expected: %q\n"+
+						"actual:   %q",
+						page, expected, got)
+				}
+			}
+
+			if rest := formFieldPage(fields, len(test.pages)); len(rest) != 0 {
+				t.Errorf("unexpected fields after the last page: %q", names(rest))
+			}
+		})
+	}
+}
@@ -6,6 +6,10 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,6 +18,7 @@ import (
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/api/cmdroute"
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
 	"github.com/diamondburned/arikawa/v3/utils/json/option"
 	"github.com/diamondburned/arikawa/v3/utils/sendpart"
 	"github.com/jellydator/ttlcache/v3"
@@ -61,7 +66,7 @@ var globalCommands = []api.CreateCommandData{
 		Options: []discord.CommandOption{
 			&discord.SubcommandOption{
 				OptionName:  "query",
-				Description: "query the info of a known user or error if not registered",
+				Description: "query the info and registration history of a user",
 				Options: []discord.CommandOptionValue{
 					&discord.UserOption{
 						OptionName:  "who",
@@ -70,6 +75,90 @@ var globalCommands = []api.CreateCommandData{
 					},
 				},
 			},
+			&discord.SubcommandOption{
+				OptionName:  "list",
+				Description: "list all registered members",
+			},
+			&discord.SubcommandOption{
+				OptionName:  "search",
+				Description: "search registered members by email or name",
+				Options: []discord.CommandOptionValue{
+					&discord.StringOption{
+						OptionName:   "query",
+						Description:  "part of the email or name to search for",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "export",
+				Description: "export all registered members to a file",
+				Options: []discord.CommandOptionValue{
+					&discord.StringOption{
+						OptionName:  "format",
+						Description: "the file format, default CSV",
+						Choices: []discord.StringChoice{
+							{Name: "CSV", Value: "csv"},
+							{Name: "JSON", Value: "json"},
+						},
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "import",
+				Description: "register members from a CSV file in the same format as export",
+				Options: []discord.CommandOptionValue{
+					&discord.AttachmentOption{
+						OptionName:  "file",
+						Description: "the CSV file to import",
+						Required:    true,
+					},
+					&discord.BooleanOption{
+						OptionName:  "assign-role",
+						Description: "give imported members the registered role, default false",
+					},
+					&discord.BooleanOption{
+						OptionName:  "set-nickname",
+						Description: "change the nickname of imported members, default false",
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "register",
+				Description: "register a user directly without verifying their email",
+				Options: []discord.CommandOptionValue{
+					&discord.UserOption{
+						OptionName:  "who",
+						Description: "the user to register",
+						Required:    true,
+					},
+					&discord.StringOption{
+						OptionName:  "first",
+						Description: "the user's first name",
+						Required:    true,
+					},
+					&discord.StringOption{
+						OptionName:  "email",
+						Description: "the user's school email",
+						Required:    true,
+					},
+					&discord.StringOption{
+						OptionName:  "last",
+						Description: "the user's last name",
+					},
+					&discord.StringOption{
+						OptionName:  "pronouns",
+						Description: "the user's pronouns, default hidden",
+						Choices: []discord.StringChoice{
+							{Name: string(acmregister.HeHim), Value: string(acmregister.HeHim)},
+							{Name: string(acmregister.SheHer), Value: string(acmregister.SheHer)},
+							{Name: string(acmregister.TheyThem), Value: string(acmregister.TheyThem)},
+							{Name: string(acmregister.AnyPronouns), Value: string(acmregister.AnyPronouns)},
+						},
+					},
+				},
+			},
 			&discord.SubcommandOption{
 				OptionName:  "unregister",
 				Description: "unregister a user and remove their role",
@@ -92,6 +181,31 @@ var globalCommands = []api.CreateCommandData{
 					},
 				},
 			},
+			&discord.SubcommandOption{
+				OptionName:  "migrate-role",
+				Description: "change the role given to registered members and give it to all of them",
+				Options: []discord.CommandOptionValue{
+					&discord.RoleOption{
+						OptionName:  "new-role",
+						Description: "the new role to give when the user is registered",
+						Required:    true,
+					},
+					&discord.BooleanOption{
+						OptionName:  "remove-old",
+						Description: "remove the old role from registered members, default false",
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "reconcile",
+				Description: "find members whose registered role doesn't match the database",
+				Options: []discord.CommandOptionValue{
+					&discord.BooleanOption{
+						OptionName:  "fix",
+						Description: "add or remove the registered role to match, default false (dry run)",
+					},
+				},
+			},
 			&discord.SubcommandOption{
 				OptionName:  "set-allowed-role",
 				Description: "set the role that can use this command group; all roles above it can use it as well",
@@ -105,13 +219,203 @@ var globalCommands = []api.CreateCommandData{
 			},
 		},
 	},
+	{
+		Name:        "registration-settings",
+		Description: "Group of commands that change how registration works in this guild.",
+		Options: []discord.CommandOption{
+			&discord.SubcommandOption{
+				OptionName:  "messages",
+				Description: "show or change the messages that the bot uses",
+				Options: []discord.CommandOptionValue{
+					&discord.StringOption{
+						OptionName:  "register-button-label",
+						Description: "the text for the Register button",
+						MaxLength:   option.NewInt(maxButtonLabel),
+					},
+					&discord.StringOption{
+						OptionName:  "registered-message",
+						Description: "the message to reply once registered successfully",
+						MaxLength:   option.NewInt(maxContent),
+					},
+					&discord.StringOption{
+						OptionName:  "verify-pin-message",
+						Description: "the message asking the user to check their email for a PIN",
+						MaxLength:   option.NewInt(maxContent),
+					},
+					&discord.StringOption{
+						OptionName:  "verify-pin-button-label",
+						Description: "the text for the button to enter the PIN",
+						MaxLength:   option.NewInt(maxButtonLabel),
+					},
+					&discord.StringOption{
+						OptionName:  "verify-pin-modal-title",
+						Description: "the title of the dialog to enter the PIN",
+						MaxLength:   option.NewInt(maxModalTitle),
+					},
+					&discord.BooleanOption{
+						OptionName:  "reset",
+						Description: "reset all messages to their defaults before changing the given ones",
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "panel",
+				Description: "change the register message without touching registered members",
+				Options: []discord.CommandOptionValue{
+					&discord.StringOption{
+						OptionName:  "message",
+						Description: "the new body for the register message",
+						MaxLength:   option.NewInt(maxContent),
+					},
+					&discord.StringOption{
+						OptionName:  "button-label",
+						Description: "the new text for the Register button",
+						MaxLength:   option.NewInt(maxButtonLabel),
+					},
+					&discord.ChannelOption{
+						OptionName:  "channel",
+						Description: "the channel to move the register message to",
+						ChannelTypes: []discord.ChannelType{
+							discord.GuildText,
+						},
+					},
+					&discord.RoleOption{
+						OptionName:  "registered-role",
+						Description: "the role to give to newly registered members",
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "nickname",
+				Description: "show or change how registered members are nicknamed",
+				Options: []discord.CommandOptionValue{
+					&discord.StringOption{
+						OptionName:  "template",
+						Description: "the nickname template, like {first}[ {last_initial}.][ ({pronouns})]",
+						MaxLength:   option.NewInt(100),
+					},
+					&discord.BooleanOption{
+						OptionName:  "disable",
+						Description: "whether to leave the nicknames of registered members alone",
+					},
+					&discord.BooleanOption{
+						OptionName:  "reset",
+						Description: "reset to the default template before changing anything else",
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "approval",
+				Description: "require a moderator to approve registrations, or stop requiring it",
+				Options: []discord.CommandOptionValue{
+					&discord.ChannelOption{
+						OptionName:  "channel",
+						Description: "the channel to send registrations to, or none to stop requiring approval",
+						ChannelTypes: []discord.ChannelType{
+							discord.GuildText,
+						},
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "audit",
+				Description: "log changes to the registration in a channel, or stop logging them",
+				Options: []discord.CommandOptionValue{
+					&discord.ChannelOption{
+						OptionName:  "channel",
+						Description: "the channel to log to, or none to stop logging",
+						ChannelTypes: []discord.ChannelType{
+							discord.GuildText,
+						},
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "fields",
+				Description: "list the extra fields in the registration form",
+			},
+			&discord.SubcommandOption{
+				OptionName:  "add-field",
+				Description: "add an extra field to the registration form, or change an existing one",
+				Options: []discord.CommandOptionValue{
+					&discord.StringOption{
+						OptionName:  "name",
+						Description: "the name of the field in exports, like major or grad_year",
+						Required:    true,
+						MaxLength:   option.NewInt(32),
+					},
+					&discord.StringOption{
+						OptionName:  "label",
+						Description: "the label shown in the registration form",
+						Required:    true,
+						MaxLength:   option.NewInt(maxInputLabel),
+					},
+					&discord.BooleanOption{
+						OptionName:  "required",
+						Description: "whether the field must be filled in, default false",
+					},
+					&discord.IntegerOption{
+						OptionName:  "min-length",
+						Description: "the minimum length of the answer",
+						Min:         option.NewInt(0),
+						Max:         option.NewInt(acmregister.FormFieldMaxLength),
+					},
+					&discord.IntegerOption{
+						OptionName:  "max-length",
+						Description: "the maximum length of the answer",
+						Min:         option.NewInt(1),
+						Max:         option.NewInt(acmregister.FormFieldMaxLength),
+					},
+					&discord.StringOption{
+						OptionName:  "pattern",
+						Description: "a regular expression that the whole answer must match",
+						MaxLength:   option.NewInt(200),
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "remove-field",
+				Description: "remove an extra field from the registration form",
+				Options: []discord.CommandOptionValue{
+					&discord.StringOption{
+						OptionName:   "name",
+						Description:  "the name of the field to remove",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+		},
+	},
+	{
+		Name:        "my-registration",
+		Description: "View or change your registration in this server.",
+		Options: []discord.CommandOption{
+			&discord.SubcommandOption{
+				OptionName:  "view",
+				Description: "view your registration",
+			},
+			&discord.SubcommandOption{
+				OptionName:  "edit",
+				Description: "edit your registration",
+			},
+			&discord.SubcommandOption{
+				OptionName:  "reset-nickname",
+				Description: "reset your nickname to the one from your registration",
+			},
+			&discord.SubcommandOption{
+				OptionName:  "leave",
+				Description: "delete your registration and remove your registered role",
+			},
+		},
+	},
 	{
 		Name:        "event-registration",
 		Description: "Commands for relating Discord events to the registration database.",
 		Options: []discord.CommandOption{
 			&discord.SubcommandOption{
 				OptionName:  "export-members",
-				Description: "export all members participating in an event to a CSV file",
+				Description: "export all members participating in an event to a file",
 				Options: []discord.CommandOptionValue{
 					&discord.StringOption{
 						OptionName:   "event",
@@ -119,13 +423,105 @@ var globalCommands = []api.CreateCommandData{
 						Required:     true,
 						Autocomplete: true,
 					},
+					&discord.StringOption{
+						OptionName:  "participants",
+						Description: "who to export, default everyone interested in the event",
+						Choices: []discord.StringChoice{
+							{Name: "Interested", Value: "interested"},
+							{Name: "Checked in", Value: "attendees"},
+						},
+					},
+					&discord.StringOption{
+						OptionName:  "format",
+						Description: "the file format, default CSV",
+						Choices: []discord.StringChoice{
+							{Name: "CSV", Value: "csv"},
+							{Name: "TSV", Value: "tsv"},
+							{Name: "JSON", Value: "json"},
+							{Name: "JSON Lines", Value: "jsonl"},
+						},
+					},
+					&discord.BooleanOption{
+						OptionName:  "include-pronouns",
+						Description: "add the pronouns of registered participants, default false",
+					},
+					&discord.BooleanOption{
+						OptionName:  "include-nickname",
+						Description: "add the nickname of participants in this server, default false",
+					},
+					&discord.BooleanOption{
+						OptionName:  "include-registered",
+						Description: "add whether each participant is registered, default false",
+					},
+					&discord.BooleanOption{
+						OptionName:  "include-fields",
+						Description: "add the custom form fields of registered participants, default false",
+					},
+					&discord.BooleanOption{
+						OptionName:  "registered-only",
+						Description: "leave out participants that aren't registered, default false",
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "export-range",
+				Description: "export how many events in a date range each registered member attended",
+				Options: []discord.CommandOptionValue{
+					&discord.StringOption{
+						OptionName:  "from",
+						Description: "the first day of the range in UTC, like 2006-01-02",
+						Required:    true,
+					},
+					&discord.StringOption{
+						OptionName:  "to",
+						Description: "the last day of the range in UTC, like 2006-05-20",
+						Required:    true,
+					},
+					&discord.StringOption{
+						OptionName:  "participants",
+						Description: "who counts as attending, default everyone interested in the event",
+						Choices: []discord.StringChoice{
+							{Name: "Interested", Value: "interested"},
+							{Name: "Checked in", Value: "attendees"},
+						},
+					},
+				},
+			},
+			&discord.SubcommandOption{
+				OptionName:  "check-in",
+				Description: "post a Check In button that records who attends an event",
+				Options: []discord.CommandOptionValue{
+					&discord.StringOption{
+						OptionName:   "event",
+						Description:  "the event to check in to",
+						Required:     true,
+						Autocomplete: true,
+					},
 				},
 			},
 		},
 	},
+	{
+		Name:        "registration-stats",
+		Description: "Show statistics of the registrations in this server.",
+		Options: []discord.CommandOption{
+			&discord.IntegerOption{
+				OptionName:  "weeks",
+				Description: "the number of weeks to show registrations of, default 8",
+				Min:         option.NewInt(1),
+				Max:         option.NewInt(statsMaxWeeks),
+			},
+		},
+	},
 	{
 		Name:        "clear-registration",
-		Description: "Clear the Register message.",
+		Description: "Clear the Register message and all registered members.",
+		Options: []discord.CommandOption{
+			&discord.BooleanOption{
+				OptionName:  "remove-role",
+				Description: "also remove the registered role from all registered members",
+			},
+		},
 	},
 }
 
@@ -162,25 +558,17 @@ func (h *Handler) cmdInitRegister(ctx context.Context, cmdData cmdroute.CommandD
 		return ErrorResponseData(err)
 	}
 
-	if data.RegisteredButtonLabel == "" {
-		data.RegisteredButtonLabel = registeredButtonLabel
-	}
-
 	if data.RegisteredMessage == "" {
 		data.RegisteredMessage = registeredMessage
 	}
 
+	messages := acmregister.GuildMessages{
+		RegisterButtonLabel: data.RegisteredButtonLabel,
+	}
+
 	registerMsg, err := h.s.SendMessageComplex(data.ChannelID, api.SendMessageData{
-		Content: data.Message,
-		Components: []discord.ContainerComponent{
-			&discord.ActionRowComponent{
-				&discord.ButtonComponent{
-					Style:    discord.PrimaryButtonStyle(),
-					CustomID: "register",
-					Label:    data.RegisteredButtonLabel,
-				},
-			},
-		},
+		Content:    data.Message,
+		Components: registerPanelComponents(messages),
 	})
 	if err != nil {
 		return ErrorResponseData(errors.Wrap(err, "cannot send register message"))
@@ -192,6 +580,8 @@ func (h *Handler) cmdInitRegister(ctx context.Context, cmdData cmdroute.CommandD
 		RoleID:            data.RegisteredRole,
 		InitUserID:        cmdData.Event.SenderID(),
 		RegisteredMessage: data.RegisteredMessage,
+		RegisterMessageID: registerMsg.ID,
+		Messages:          messages,
 	}); err != nil {
 		h.s.DeleteMessage(data.ChannelID, registerMsg.ID, "cannot init guild, check error")
 		return ErrorResponseData(errors.Wrap(err, "cannot init guild"))
@@ -203,6 +593,20 @@ func (h *Handler) cmdInitRegister(ctx context.Context, cmdData cmdroute.CommandD
 	}
 }
 
+// registerPanelComponents returns the components of the register message, which
+// is the message with the Register button.
+func registerPanelComponents(msgs acmregister.GuildMessages) discord.ContainerComponents {
+	return discord.ContainerComponents{
+		&discord.ActionRowComponent{
+			&discord.ButtonComponent{
+				Style:    discord.PrimaryButtonStyle(),
+				CustomID: "register",
+				Label:    messagesWithDefaults(msgs).RegisterButtonLabel,
+			},
+		},
+	}
+}
+
 func (h *Handler) cmdMemberQuery(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
 	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
 	if err != nil {
@@ -219,23 +623,56 @@ func (h *Handler) cmdMemberQuery(ctx context.Context, cmdData cmdroute.CommandDa
 		return ErrorResponseData(err)
 	}
 
-	metadata, err := h.store.MemberInfo(guild.GuildID, data.Who)
+	events, err := h.store.MemberEvents(guild.GuildID, data.Who)
 	if err != nil {
-		return ErrorResponseData(err)
+		h.PrivateWarning(cmdData.Event, errors.Wrap(err, "cannot get member history"))
+		return InternalErrorResponseData()
 	}
 
-	b, err := json.MarshalIndent(metadata, "", "  ")
+	var content strings.Builder
+
+	metadata, err := h.store.MemberInfo(guild.GuildID, data.Who)
 	if err != nil {
-		return ErrorResponseData(errors.Wrap(err, "cannot encode metadata as JSON"))
+		// Members that are no longer registered still have a history.
+		if !errors.Is(err, acmregister.ErrNotFound) || len(events) == 0 {
+			return ErrorResponseData(err)
+		}
+		content.WriteString(data.Who.Mention() + " is not registered.\n")
+	} else {
+		b, err := json.MarshalIndent(metadata, "", "  ")
+		if err != nil {
+			return ErrorResponseData(errors.Wrap(err, "cannot encode metadata as JSON"))
+		}
+		content.WriteString("```json\n" + string(b) + "\n```\n")
+	}
+
+	if len(events) > 0 {
+		content.WriteString("**History:**\n")
+		if len(events) > memberHistoryLimit {
+			fmt.Fprintf(&content, "*%d older events not shown*\n", len(events)-memberHistoryLimit)
+			events = events[len(events)-memberHistoryLimit:]
+		}
+		for _, ev := range events {
+			fmt.Fprintf(&content, "- <t:%d:f> **%s**", ev.Time.Unix(), ev.Type)
+			if ev.ActorID.IsValid() && ev.ActorID != data.Who {
+				content.WriteString(" by " + ev.ActorID.Mention())
+			}
+			content.WriteByte('\n')
+		}
 	}
 
 	return &api.InteractionResponseData{
-		Flags:   discord.EphemeralMessage,
-		Content: option.NewNullableString("```json\n" + string(b) + "\n```"),
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString(truncate(content.String(), maxContent)),
+		AllowedMentions: &api.AllowedMentions{},
 	}
 }
 
-func (h *Handler) cmdMemberUnregister(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+// memberHistoryLimit is the maximum number of events shown in the history of
+// /registered-member query.
+const memberHistoryLimit = 15
+
+func (h *Handler) cmdMemberList(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
 	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
 	if err != nil {
 		logger := logger.FromContext(h.ctx)
@@ -243,44 +680,88 @@ func (h *Handler) cmdMemberUnregister(ctx context.Context, cmdData cmdroute.Comm
 		return nil
 	}
 
-	var data struct {
-		Who discord.UserID `discord:"who"`
+	data, err := h.memberListPage(guild.GuildID, acmregister.MemberCursor{})
+	if err != nil {
+		h.LogErr(guild.GuildID, err)
+		return InternalErrorResponseData()
 	}
 
-	if err := cmdData.Options.Unmarshal(&data); err != nil {
-		return ErrorResponseData(err)
-	}
+	return data
+}
 
-	target, err := h.s.Member(cmdData.Event.GuildID, data.Who)
+// memberListPageSize is the number of members shown per page of
+// /registered-member list.
+const memberListPageSize = 15
+
+// memberListPage renders the page of registered members at the given cursor.
+// The page has buttons to go to the previous and next pages.
+func (h *Handler) memberListPage(guildID discord.GuildID, cursor acmregister.MemberCursor) (*api.InteractionResponseData, error) {
+	// Fetch one extra member to know whether there's another page.
+	members, err := h.store.ListMembers(guildID, cursor, memberListPageSize+1)
 	if err != nil {
-		return ErrorResponseData(errors.Wrap(err, "invalid member for 'who'"))
+		return nil, errors.Wrap(err, "cannot list members")
 	}
 
-	if err := h.store.UnregisterMember(cmdData.Event.GuildID, data.Who); err != nil {
-		if errors.Is(err, acmregister.ErrNotFound) {
-			err = errors.New("user is not registered")
+	var hasPrev, hasNext bool
+	if cursor.Before {
+		hasPrev = len(members) > memberListPageSize
+		hasNext = true
+		if hasPrev {
+			members = members[1:]
+		}
+	} else {
+		hasPrev = cursor.UserID.IsValid()
+		hasNext = len(members) > memberListPageSize
+		if hasNext {
+			members = members[:memberListPageSize]
 		}
-		return ErrorResponseData(err)
 	}
 
-	if err := h.s.RemoveRole(
-		cmdData.Event.GuildID, data.Who, guild.RoleID,
-		api.AuditLogReason(fmt.Sprintf(
-			"%s requested for %s (%v) to be unregistered",
-			cmdData.Event.Sender().Tag(), target.User.Tag(), data.Who,
-		)),
-	); err != nil {
-		return ErrorResponseData(errors.Wrap(err, "cannot remove role, but member is registered"))
+	var desc strings.Builder
+	for _, member := range members {
+		desc.WriteString(memberLine(member))
+	}
+	if len(members) == 0 {
+		desc.WriteString("No registered members.")
+	}
+
+	var first, last discord.UserID
+	if len(members) > 0 {
+		first = members[0].UserID
+		last = members[len(members)-1].UserID
 	}
 
 	return &api.InteractionResponseData{
-		Flags:           discord.EphemeralMessage,
-		Content:         option.NewNullableString("User " + data.Who.Mention() + " has been unregistered."),
+		Flags: discord.EphemeralMessage,
+		Embeds: &[]discord.Embed{{
+			Title:       "Registered Members",
+			Description: desc.String(),
+		}},
+		Components: &discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.ButtonComponent{
+					Style:    discord.SecondaryButtonStyle(),
+					CustomID: discord.ComponentID("list-members:before:" + first.String()),
+					Label:    "Previous",
+					Disabled: !hasPrev || len(members) == 0,
+				},
+				&discord.ButtonComponent{
+					Style:    discord.SecondaryButtonStyle(),
+					CustomID: discord.ComponentID("list-members:after:" + last.String()),
+					Label:    "Next",
+					Disabled: !hasNext || len(members) == 0,
+				},
+			},
+		},
 		AllowedMentions: &api.AllowedMentions{},
-	}
+	}, nil
 }
 
-func (h *Handler) cmdMemberResetName(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+// memberSearchLimit is the maximum number of members that a search returns.
+// It is also the maximum number of autocomplete choices.
+const memberSearchLimit = 25
+
+func (h *Handler) cmdMemberSearch(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
 	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
 	if err != nil {
 		logger := logger.FromContext(h.ctx)
@@ -289,51 +770,1238 @@ func (h *Handler) cmdMemberResetName(ctx context.Context, cmdData cmdroute.Comma
 	}
 
 	var data struct {
-		Who discord.UserID `discord:"who"`
+		Query string `discord:"query"`
 	}
 
 	if err := cmdData.Options.Unmarshal(&data); err != nil {
 		return ErrorResponseData(err)
 	}
 
-	metadata, err := h.store.MemberInfo(guild.GuildID, data.Who)
-	if err != nil {
-		return ErrorResponseData(err)
-	}
+	data.Query = strings.TrimSpace(data.Query)
 
-	if err := h.s.ModifyMember(cmdData.Event.GuildID, data.Who, api.ModifyMemberData{
-		Nick: option.NewString(metadata.Nickname()),
-	}); err != nil {
-		return ErrorResponseData(err)
+	var members []acmregister.Member
+
+	// Autocompletion gives us the user ID of the chosen member.
+	if id, err := discord.ParseSnowflake(data.Query); err == nil {
+		if metadata, err := h.store.MemberInfo(guild.GuildID, discord.UserID(id)); err == nil {
+			members = append(members, acmregister.Member{
+				GuildID:  guild.GuildID,
+				UserID:   discord.UserID(id),
+				Metadata: *metadata,
+			})
+		}
+	}
+
+	if len(members) == 0 {
+		members, err = h.store.SearchMembers(guild.GuildID, data.Query, memberSearchLimit)
+		if err != nil {
+			h.LogErr(guild.GuildID, errors.Wrap(err, "cannot search members"))
+			return InternalErrorResponseData()
+		}
+	}
+
+	if len(members) == 0 {
+		return ErrorResponseData(fmt.Errorf("no registered members matching %q", data.Query))
+	}
+
+	var desc strings.Builder
+	for _, member := range members {
+		desc.WriteString(memberLine(member))
+	}
+
+	return &api.InteractionResponseData{
+		Flags: discord.EphemeralMessage,
+		Embeds: &[]discord.Embed{{
+			Title:       fmt.Sprintf("Members matching %q", data.Query),
+			Description: desc.String(),
+		}},
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+func (h *Handler) acMemberSearch(ctx context.Context, acData cmdroute.AutocompleteData) api.AutocompleteChoices {
+	switch option := acData.Options.Focused(); option.Name {
+	case "query":
+		members, err := h.store.SearchMembers(acData.Event.GuildID, option.String(), memberSearchLimit)
+		if err != nil {
+			h.LogErr(acData.Event.GuildID, errors.Wrap(err, "cannot search members"))
+			return nil
+		}
+
+		choices := make(api.AutocompleteStringChoices, len(members))
+		for i, member := range members {
+			choices[i] = discord.StringChoice{
+				Name:  truncate(fmt.Sprintf("%s (%s)", member.Metadata.Name(), member.Metadata.Email), 100),
+				Value: member.UserID.String(),
+			}
+		}
+
+		return choices
+	default:
+		return nil
+	}
+}
+
+// memberLine formats the member as a Markdown list item.
+func memberLine(member acmregister.Member) string {
+	return fmt.Sprintf("- %s %s (`%s`)\n",
+		member.UserID.Mention(), member.Metadata.Name(), member.Metadata.Email)
+}
+
+// truncate truncates the string to at most max runes.
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+func (h *Handler) cmdMemberExport(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	var data struct {
+		Format string `discord:"format?"`
+	}
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	var encode rosterEncoder
+	switch data.Format {
+	case "", "csv":
+		data.Format = "csv"
+		encode = encodeRosterCSV(guild.FormFields)
+	case "json":
+		encode = encodeRosterJSON
+	default:
+		return ErrorResponseData(fmt.Errorf("unknown format %q", data.Format))
+	}
+
+	// Looking up every member takes a while, so defer right away instead of
+	// waiting for the timeout.
+	cmdroute.DeferTicketFromContext(ctx).Defer()
+
+	// Everyone is looked up before anything is uploaded, since the upload
+	// times out long before that's done.
+	out := limitedBuffer{max: maxExportSize}
+	if err := h.writeRoster(guild.GuildID, &out, encode); err != nil {
+		if errors.Is(err, errExportTooLarge) {
+			return ErrorResponseData(err)
+		}
+		h.LogErr(guild.GuildID, errors.Wrap(err, "cannot export members"))
+		return InternalErrorResponseData()
+	}
+
+	return &api.InteractionResponseData{
+		Content: option.NewNullableString("Here are all registered members."),
+		Files: []sendpart.File{
+			{
+				Name:   "members." + data.Format,
+				Reader: bytes.NewReader(out.Bytes()),
+			},
+		},
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+// maxExportSize is the size limit of exported files. It is Discord's limit
+// for attachments.
+const maxExportSize = 25 << 20 // 25 MiB
+
+var errExportTooLarge = errors.New("the export is too large to upload to Discord")
+
+// limitedBuffer is a bytes.Buffer that can't grow over max bytes.
+type limitedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.max {
+		return 0, errExportTooLarge
+	}
+	return b.Buffer.Write(p)
+}
+
+// rosterRecord is a row of /registered-member export.
+type rosterRecord struct {
+	UserID       string `json:"user_id"`
+	Username     string `json:"username"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	Pronouns     string `json:"pronouns"`
+	Email        string `json:"email"`
+	RegisteredAt string `json:"registered_at,omitempty"` // RFC 3339, empty if unknown
+	// Extra holds the answers to the guild's custom form fields.
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// rosterColumns are the CSV columns of /registered-member export before the
+// custom form fields.
+var rosterColumns = []string{
+	"user_id", "username", "first_name", "last_name", "pronouns", "email", "registered_at",
+}
+
+// rosterEncoder writes records to w. It is called once per page of members;
+// records is nil once there are no more members.
+type rosterEncoder func(w io.Writer, records []rosterRecord, first bool) error
+
+// rosterPageSize is the number of members fetched from the store at a time
+// when exporting.
+const rosterPageSize = 100
+
+// writeRoster writes all registered members of the guild to w page by page.
+func (h *Handler) writeRoster(guildID discord.GuildID, w io.Writer, encode rosterEncoder) error {
+	client := h.s.WithContext(h.ctx)
+
+	var cursor acmregister.MemberCursor
+	for first := true; ; first = false {
+		members, err := h.store.ListMembers(guildID, cursor, rosterPageSize)
+		if err != nil {
+			return errors.Wrap(err, "cannot list members")
+		}
+
+		if len(members) == 0 {
+			return encode(w, nil, first)
+		}
+
+		records := make([]rosterRecord, len(members))
+		for i, member := range members {
+			records[i] = rosterRecord{
+				UserID:    member.UserID.String(),
+				FirstName: member.Metadata.FirstName,
+				LastName:  member.Metadata.LastName,
+				Pronouns:  string(member.Metadata.Pronouns),
+				Email:     string(member.Metadata.Email),
+				Extra:     member.Metadata.Extra,
+			}
+
+			if !member.RegisteredAt.IsZero() {
+				records[i].RegisteredAt = member.RegisteredAt.UTC().Format(time.RFC3339)
+			}
+
+			// Members that left the guild are still exported, so fall back to
+			// fetching the user.
+			if m, err := h.s.Member(guildID, member.UserID); err == nil {
+				records[i].Username = m.User.Username
+			} else if u, err := client.User(member.UserID); err == nil {
+				records[i].Username = u.Username
+			}
+		}
+
+		if err := encode(w, records, first); err != nil {
+			return err
+		}
+
+		cursor.UserID = members[len(members)-1].UserID
+	}
+}
+
+// encodeRosterCSV returns an encoder that writes CSV rows with a column for
+// each of the given custom form fields after the rosterColumns.
+func encodeRosterCSV(fields []acmregister.FormField) rosterEncoder {
+	return func(w io.Writer, records []rosterRecord, first bool) error {
+		csvw := csv.NewWriter(w)
+		if first {
+			header := append([]string(nil), rosterColumns...)
+			for _, field := range fields {
+				header = append(header, field.Name)
+			}
+			csvw.Write(header)
+		}
+		for _, record := range records {
+			row := []string{
+				record.UserID,
+				record.Username,
+				record.FirstName,
+				record.LastName,
+				record.Pronouns,
+				record.Email,
+				record.RegisteredAt,
+			}
+			for _, field := range fields {
+				row = append(row, record.Extra[field.Name])
+			}
+			csvw.Write(row)
+		}
+		csvw.Flush()
+		return errors.Wrap(csvw.Error(), "cannot write CSV")
+	}
+}
+
+// encodeRosterJSON writes a JSON array of all records.
+func encodeRosterJSON(w io.Writer, records []rosterRecord, first bool) error {
+	var buf bytes.Buffer
+	if first {
+		buf.WriteString("[")
+	}
+	for i, record := range records {
+		if !first || i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  ")
+		b, err := json.Marshal(record)
+		if err != nil {
+			return errors.Wrap(err, "cannot encode member as JSON")
+		}
+		buf.Write(b)
+	}
+	if records == nil {
+		buf.WriteString("\n]\n")
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// memberImportMaxSize is the maximum size of the file given to
+// /registered-member import.
+const memberImportMaxSize = 4 << 20 // 4 MiB
+
+func (h *Handler) cmdMemberImport(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	var data struct {
+		AssignRole  bool `discord:"assign-role?"`
+		SetNickname bool `discord:"set-nickname?"`
+	}
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	fileID, err := cmdData.Options.Find("file").SnowflakeValue()
+	if err != nil {
+		return ErrorResponseData(errors.Wrap(err, "invalid file"))
+	}
+
+	file, ok := cmdData.Data.Resolved.Attachments[discord.AttachmentID(fileID)]
+	if !ok {
+		return ErrorResponseData(errors.New("file not found"))
+	}
+	if file.Size > memberImportMaxSize {
+		return ErrorResponseData(fmt.Errorf("file is too large, must be at most %d MiB", memberImportMaxSize>>20))
+	}
+
+	// Registering every member takes a while, so defer right away instead of
+	// waiting for the timeout.
+	cmdroute.DeferTicketFromContext(ctx).Defer()
+
+	rows, err := h.fetchMemberImport(guild, file.URL)
+	if err != nil {
+		return ErrorResponseData(err)
+	}
+
+	type reportRecord struct {
+		Line    int
+		UserID  string
+		Status  string
+		Message string
+	}
+
+	report := make([]reportRecord, len(rows))
+	var registered, duplicates, failed int
+
+	for i, row := range rows {
+		report[i] = reportRecord{
+			Line:   row.Line,
+			UserID: row.Member.UserID.String(),
+		}
+
+		if row.Err != nil {
+			report[i].Status = "error"
+			report[i].Message = row.Err.Error()
+			failed++
+			continue
+		}
+
+		row.Member.RegisteredBy = cmdData.Event.SenderID()
+
+		if err := h.store.RegisterMember(row.Member); err != nil {
+			if errors.Is(err, acmregister.ErrMemberAlreadyExists) {
+				report[i].Status = "duplicate"
+				report[i].Message = "user or email is already registered"
+				duplicates++
+				continue
+			}
+
+			h.LogErr(guild.GuildID, errors.Wrapf(err, "cannot import member %v", row.Member.UserID))
+			report[i].Status = "error"
+			report[i].Message = "cannot save into database"
+			failed++
+			continue
+		}
+
+		report[i].Status = "registered"
+		registered++
+
+		// The member is registered either way, so only note these errors.
+		var warnings []string
+
+		if data.AssignRole {
+			if err := h.s.AddRole(guild.GuildID, row.Member.UserID, guild.RoleID, api.AddRoleData{
+				AuditLogReason: "member imported, added by acmRegister",
+			}); err != nil {
+				warnings = append(warnings, "cannot add role: "+err.Error())
+			}
+		}
+
+		if data.SetNickname {
+			if err := h.setNickname(guild, row.Member.UserID, row.Member.Metadata); err != nil {
+				warnings = append(warnings, "cannot set nickname: "+err.Error())
+			}
+		}
+
+		report[i].Message = strings.Join(warnings, "; ")
+	}
+
+	if registered > 0 {
+		h.audit(guild, cmdData.Event.SenderID(), discord.Embed{
+			Title: "Members Imported",
+			Description: fmt.Sprintf(
+				"%d member(s) have been registered from `%s`.", registered, file.Filename),
+			Color: auditColorAdded,
+		})
+	}
+
+	var csvOut bytes.Buffer
+	csvw := csv.NewWriter(&csvOut)
+	csvw.Write([]string{"line", "user_id", "status", "message"})
+	if err := xcsv.Marshal(csvw, report); err != nil {
+		return ErrorResponseData(errors.Wrap(err, "cannot write CSV"))
+	}
+
+	return &api.InteractionResponseData{
+		Content: option.NewNullableString(fmt.Sprintf(""+
+			"Imported %d row(s) from `%s`:\n"+
+			"- **%d** registered\n"+
+			"- **%d** already registered\n"+
+			"- **%d** failed\n"+
+			"See the attached report for details.",
+			len(rows), file.Filename, registered, duplicates, failed,
+		)),
+		Files: []sendpart.File{
+			{
+				Name:   "import-report.csv",
+				Reader: bytes.NewReader(csvOut.Bytes()),
+			},
+		},
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+// memberImportRow is a row of the file given to /registered-member import.
+// Err is non-nil if the row is invalid.
+type memberImportRow struct {
+	Line   int
+	Member acmregister.Member
+	Err    error
+}
+
+// fetchMemberImport downloads and parses the CSV file at the given URL.
+func (h *Handler) fetchMemberImport(guild *acmregister.KnownGuild, url discord.URL) ([]memberImportRow, error) {
+	req, err := http.NewRequestWithContext(h.ctx, "GET", url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create request")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot download file")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot download file: unexpected status %s", resp.Status)
+	}
+
+	return h.parseMemberImport(guild, io.LimitReader(resp.Body, memberImportMaxSize))
+}
+
+// parseMemberImport parses a CSV file with a header of the same columns as
+// /registered-member export, including the guild's custom form fields. Only
+// the user_id, email and first_name columns are required; unknown columns are
+// ignored.
+func (h *Handler) parseMemberImport(guild *acmregister.KnownGuild, r io.Reader) ([]memberImportRow, error) {
+	csvr := csv.NewReader(r)
+	csvr.FieldsPerRecord = -1
+
+	header, err := csvr.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read CSV header")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff") // Excel likes to add a BOM
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range []string{"user_id", "email", "first_name"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV is missing the %s column", name)
+		}
+	}
+
+	var rows []memberImportRow
+	for {
+		record, err := csvr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return rows, nil
+			}
+			return nil, errors.Wrap(err, "cannot read CSV")
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		line, _ := csvr.FieldPos(0)
+		row := memberImportRow{Line: line}
+		row.Member, row.Err = h.parseMemberImportRecord(guild, field)
+
+		rows = append(rows, row)
+	}
+}
+
+func (h *Handler) parseMemberImportRecord(guild *acmregister.KnownGuild, field func(string) string) (acmregister.Member, error) {
+	member := acmregister.Member{
+		GuildID: guild.GuildID,
+		Metadata: acmregister.MemberMetadata{
+			Email:     acmregister.Email(field("email")),
+			FirstName: field("first_name"),
+			LastName:  field("last_name"),
+			Pronouns:  acmregister.Pronouns(field("pronouns")),
+		},
+	}
+
+	userID, err := discord.ParseSnowflake(field("user_id"))
+	if err != nil || !userID.IsValid() {
+		return member, fmt.Errorf("invalid user_id %q", field("user_id"))
+	}
+	member.UserID = discord.UserID(userID)
+
+	if member.Metadata.FirstName == "" {
+		return member, errors.New("missing first_name")
+	}
+
+	if err := member.Metadata.Pronouns.Validate(); err != nil {
+		return member, fmt.Errorf("invalid pronouns %q", member.Metadata.Pronouns)
+	}
+
+	if err := h.opts.EmailHosts.VerifyEmail(member.Metadata.Email); err != nil {
+		return member, err
+	}
+
+	for _, formField := range guild.FormFields {
+		if v := field(formField.Name); v != "" {
+			if member.Metadata.Extra == nil {
+				member.Metadata.Extra = make(map[string]string, len(guild.FormFields))
+			}
+			member.Metadata.Extra[formField.Name] = v
+		}
+	}
+
+	if err := acmregister.ValidateFormFields(guild.FormFields, member.Metadata.Extra); err != nil {
+		return member, err
+	}
+
+	if v := field("registered_at"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return member, fmt.Errorf("invalid registered_at %q", v)
+		}
+		member.RegisteredAt = t
+	}
+
+	return member, nil
+}
+
+func (h *Handler) cmdMemberRegister(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	var data struct {
+		Who       discord.UserID       `discord:"who"`
+		FirstName string               `discord:"first"`
+		LastName  string               `discord:"last?"`
+		Email     acmregister.Email    `discord:"email"`
+		Pronouns  acmregister.Pronouns `discord:"pronouns?"`
+	}
+
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	metadata := acmregister.MemberMetadata{
+		Email:     acmregister.Email(strings.TrimSpace(string(data.Email))),
+		FirstName: strings.TrimSpace(data.FirstName),
+		LastName:  strings.TrimSpace(data.LastName),
+		Pronouns:  data.Pronouns,
+	}
+
+	if err := metadata.Pronouns.Validate(); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	// Admins vouch for the email, so only check that it's a school one.
+	if err := h.opts.EmailHosts.VerifyEmail(metadata.Email); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	if err := h.store.RegisterMember(acmregister.Member{
+		GuildID:      guild.GuildID,
+		UserID:       data.Who,
+		Metadata:     metadata,
+		RegisteredBy: cmdData.Event.SenderID(),
+	}); err != nil {
+		if errors.Is(err, acmregister.ErrMemberAlreadyExists) {
+			return ErrorResponseData(errors.New("user or email is already registered"))
+		}
+		h.LogErr(guild.GuildID, errors.Wrap(err, "cannot save into database"))
+		return InternalErrorResponseData()
+	}
+
+	h.auditRegistered(guild, cmdData.Event.SenderID(), data.Who, metadata)
+
+	if err := h.s.AddRole(guild.GuildID, data.Who, guild.RoleID, api.AddRoleData{
+		AuditLogReason: api.AuditLogReason(fmt.Sprintf(
+			"%s registered %v, added by acmRegister",
+			cmdData.Event.Sender().Tag(), data.Who,
+		)),
+	}); err != nil {
+		return ErrorResponseData(errors.Wrap(err, "cannot add role, but member is registered"))
+	}
+
+	msg := "User " + data.Who.Mention() + " has been registered as **" + metadata.Name() + "**."
+
+	if err := h.setNickname(guild, data.Who, metadata); err != nil {
+		msg += "\n⚠️ Cannot set their nickname: " + err.Error()
+	}
+
+	return &api.InteractionResponseData{
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString(msg),
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+func (h *Handler) cmdMemberUnregister(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	var data struct {
+		Who discord.UserID `discord:"who"`
+	}
+
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	// Members that left the guild can't be fetched anymore, but they may still
+	// be registered.
+	target, err := h.s.Member(cmdData.Event.GuildID, data.Who)
+	if err != nil {
+		if _, infoErr := h.store.MemberInfo(cmdData.Event.GuildID, data.Who); infoErr != nil {
+			return ErrorResponseData(errors.Wrap(err, "invalid member for 'who'"))
+		}
+		target = nil
+	}
+
+	if err := h.store.UnregisterMember(cmdData.Event.GuildID, data.Who, cmdData.Event.SenderID()); err != nil {
+		if errors.Is(err, acmregister.ErrNotFound) {
+			err = errors.New("user is not registered")
+		}
+		return ErrorResponseData(err)
+	}
+
+	h.auditUnregistered(guild, cmdData.Event.SenderID(), data.Who, "has been unregistered by an admin.")
+
+	if target == nil {
+		return &api.InteractionResponseData{
+			Flags: discord.EphemeralMessage,
+			Content: option.NewNullableString("" +
+				"User " + data.Who.Mention() + " has been unregistered. " +
+				"They already left the server, so they have no role to remove."),
+			AllowedMentions: &api.AllowedMentions{},
+		}
+	}
+
+	if err := h.s.RemoveRole(
+		cmdData.Event.GuildID, data.Who, guild.RoleID,
+		api.AuditLogReason(fmt.Sprintf(
+			"%s requested for %s (%v) to be unregistered",
+			cmdData.Event.Sender().Tag(), target.User.Tag(), data.Who,
+		)),
+	); err != nil {
+		return ErrorResponseData(errors.Wrap(err, "cannot remove role, but member is registered"))
+	}
+
+	return &api.InteractionResponseData{
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString("User " + data.Who.Mention() + " has been unregistered."),
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+func (h *Handler) cmdMemberResetName(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	var data struct {
+		Who discord.UserID `discord:"who"`
+	}
+
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	metadata, err := h.store.MemberInfo(guild.GuildID, data.Who)
+	if err != nil {
+		return ErrorResponseData(err)
+	}
+
+	if guild.Nickname.Disabled {
+		return ErrorResponseData(errors.New("nicknames are disabled in this server"))
+	}
+
+	if err := h.setNickname(guild, data.Who, *metadata); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	h.auditNicknameReset(guild, cmdData.Event.SenderID(), data.Who, *metadata)
+
+	return &api.InteractionResponseData{
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString("User " + data.Who.Mention() + "'s nickname has been reset."),
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+func (h *Handler) cmdClearRegistration(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	if err := h.authorizeAdmin(cmdData.Event); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	var data struct {
+		RemoveRole bool `discord:"remove-role?"`
+	}
+
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	msg := "" +
+		"⚠️ This deletes the register message and removes all registered members, " +
+		"PINs and pending submissions from the database. " +
+		"An archive of the members is attached once it's done."
+	confirmID := "clear-registration:keep-role"
+	if data.RemoveRole {
+		msg += "\n\n" + guild.RoleID.Mention() + " is also removed from all registered members."
+		confirmID = "clear-registration:remove-role"
+	}
+
+	return &api.InteractionResponseData{
+		Flags:   discord.EphemeralMessage,
+		Content: option.NewNullableString(msg),
+		Components: &discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.ButtonComponent{
+					Style:    discord.DangerButtonStyle(),
+					CustomID: discord.ComponentID(confirmID),
+					Label:    "Clear Registration",
+				},
+				&discord.ButtonComponent{
+					Style:    discord.SecondaryButtonStyle(),
+					CustomID: "clear-registration:cancel",
+					Label:    "Cancel",
+				},
+			},
+		},
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+// registrationArchive is the JSON file attached once /clear-registration is
+// confirmed.
+type registrationArchive struct {
+	GuildID   discord.GuildID  `json:"guild_id"`
+	ClearedBy discord.UserID   `json:"cleared_by"`
+	ClearedAt string           `json:"cleared_at"` // RFC 3339
+	Members   []archivedMember `json:"members"`
+}
+
+type archivedMember struct {
+	UserID discord.UserID `json:"user_id"`
+	acmregister.MemberMetadata
+	RegisteredAt string         `json:"registered_at,omitempty"` // RFC 3339, empty if unknown
+	RegisteredBy discord.UserID `json:"registered_by,omitempty"`
+	DepartedAt   string         `json:"departed_at,omitempty"` // RFC 3339, empty if still in the guild
+}
+
+// archiveRegistration returns the archive of all registered members of the
+// guild. Only the database is used, so this is fast enough to do before
+// responding.
+func (h *Handler) archiveRegistration(guildID discord.GuildID, clearedBy discord.UserID) (*registrationArchive, error) {
+	archive := registrationArchive{
+		GuildID:   guildID,
+		ClearedBy: clearedBy,
+		ClearedAt: time.Now().UTC().Format(time.RFC3339),
+		Members:   []archivedMember{},
+	}
+
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	var cursor acmregister.MemberCursor
+	for {
+		members, err := h.store.ListMembers(guildID, cursor, rosterPageSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot list members")
+		}
+
+		if len(members) == 0 {
+			return &archive, nil
+		}
+
+		for _, member := range members {
+			archive.Members = append(archive.Members, archivedMember{
+				UserID:         member.UserID,
+				MemberMetadata: member.Metadata,
+				RegisteredAt:   formatTime(member.RegisteredAt),
+				RegisteredBy:   member.RegisteredBy,
+				DepartedAt:     formatTime(member.DepartedAt),
+			})
+		}
+
+		cursor.UserID = members[len(members)-1].UserID
+	}
+}
+
+// clearRegistration archives then deletes all registration data of the guild,
+// as confirmed through /clear-registration.
+func (h *Handler) clearRegistration(ctx context.Context, ev *discord.InteractionEvent, removeRole bool) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(ev.GuildID)
+	if err != nil {
+		if errors.Is(err, acmregister.ErrNotFound) {
+			return ErrorResponseData(errors.New("registration has already been cleared"))
+		}
+		h.LogErr(ev.GuildID, errors.Wrap(err, "cannot get guild"))
+		return InternalErrorResponseData()
+	}
+
+	// The archive is sent in its own message, so defer to be able to follow
+	// up with it.
+	cmdroute.DeferTicketFromContext(ctx).Defer()
+
+	// Nothing is deleted unless the archive could be made and sent.
+	archive, err := h.archiveRegistration(guild.GuildID, ev.SenderID())
+	if err != nil {
+		h.LogErr(guild.GuildID, err)
+		return InternalErrorResponseData()
+	}
+
+	archiveJSON, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		h.LogErr(guild.GuildID, errors.Wrap(err, "cannot encode archive as JSON"))
+		return InternalErrorResponseData()
+	}
+
+	if err := h.FollowUp(ev, &api.InteractionResponseData{
+		Flags: discord.EphemeralMessage,
+		Content: option.NewNullableString(fmt.Sprintf(
+			"Here's the archive of all %d registered member(s). Clearing the registration...",
+			len(archive.Members))),
+		Files: []sendpart.File{
+			{
+				Name:   fmt.Sprintf("registration-archive-%d.json", guild.GuildID),
+				Reader: bytes.NewReader(archiveJSON),
+			},
+		},
+		AllowedMentions: &api.AllowedMentions{},
+	}); err != nil {
+		return ErrorResponseData(errors.New("the archive could not be sent, so nothing has been cleared"))
+	}
+
+	if err := h.store.DeleteGuild(guild.GuildID); err != nil {
+		h.LogErr(guild.GuildID, errors.Wrap(err, "cannot delete guild"))
+		return InternalErrorResponseData()
+	}
+
+	h.audit(guild, ev.SenderID(), discord.Embed{
+		Title: "Registration Cleared",
+		Description: fmt.Sprintf(
+			"The registration has been cleared and all %d registered member(s) have been unregistered. "+
+				"Nothing will be logged here anymore.",
+			len(archive.Members)),
+		Color: auditColorRemoved,
+	})
+
+	var msg strings.Builder
+	msg.WriteString("Registration has been cleared.\n")
+
+	if guild.RegisterMessageID.IsValid() {
+		err := h.s.DeleteMessage(guild.ChannelID, guild.RegisterMessageID, "registration cleared")
+		if err != nil && !isNotFound(err) {
+			h.LogErr(guild.GuildID, errors.Wrap(err, "cannot delete register message"))
+			msg.WriteString("The register message could not be deleted, so delete it yourself.\n")
+		}
+	} else {
+		fmt.Fprintf(&msg, "Delete the register message in %s yourself if it's still there.\n", guild.ChannelID.Mention())
+	}
+
+	if !removeRole {
+		fmt.Fprintf(&msg, "Registered members keep %s.", guild.RoleID.Mention())
+	} else {
+		auditReason := api.AuditLogReason(fmt.Sprintf(
+			"%s cleared registration, changed by acmRegister",
+			ev.Sender().Tag(),
+		))
+
+		var removed, left, failed int
+		for _, member := range archive.Members {
+			err := h.s.RemoveRole(guild.GuildID, member.UserID, guild.RoleID, auditReason)
+			switch {
+			case err == nil:
+				removed++
+			case isNotFound(err):
+				left++
+			default:
+				h.LogErr(guild.GuildID, errors.Wrapf(err, "cannot remove role from %v", member.UserID))
+				failed++
+			}
+		}
+
+		fmt.Fprintf(&msg, ""+
+			"%s has been removed from **%d** member(s). "+
+			"**%d** member(s) are no longer in the server, and **%d** member(s) failed.",
+			guild.RoleID.Mention(), removed, left, failed)
+	}
+
+	return &api.InteractionResponseData{
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString(msg.String()),
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+// roleMigrationProgressEvery is the number of members between each progress
+// report of /registered-member migrate-role.
+const roleMigrationProgressEvery = 100
+
+func (h *Handler) cmdMemberMigrateRole(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	var data struct {
+		NewRole   discord.RoleID `discord:"new-role"`
+		RemoveOld bool           `discord:"remove-old?"`
+	}
+
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	if discord.Snowflake(data.NewRole) == discord.Snowflake(guild.GuildID) {
+		return ErrorResponseData(errors.New("cannot use @everyone as the registered role"))
+	}
+
+	oldRole := guild.RoleID
+	// Running this again with the same role gives it to members that were
+	// missed, so don't remove it from them.
+	removeOld := data.RemoveOld && oldRole != data.NewRole
+
+	// Adding roles to every member takes a while, so defer right away instead
+	// of waiting for the timeout.
+	cmdroute.DeferTicketFromContext(ctx).Defer()
+
+	// Switch the role first so that new members get the new role while the
+	// old ones are being migrated.
+	if err := h.store.GuildSetRole(guild.GuildID, data.NewRole); err != nil {
+		h.LogErr(guild.GuildID, errors.Wrap(err, "cannot set registered role"))
+		return InternalErrorResponseData()
+	}
+
+	auditReason := api.AuditLogReason(fmt.Sprintf(
+		"%s migrated the registered role, changed by acmRegister",
+		cmdData.Event.Sender().Tag(),
+	))
+
+	var migrated, left, failed int
+	var cursor acmregister.MemberCursor
+
+	for {
+		members, err := h.store.ListMembers(guild.GuildID, cursor, roleMigrationProgressEvery)
+		if err != nil {
+			h.LogErr(guild.GuildID, errors.Wrap(err, "cannot list members"))
+			return InternalErrorResponseData()
+		}
+
+		if len(members) == 0 {
+			break
+		}
+
+		// The API client waits out rate limits on its own, so these calls just
+		// get slower when we hit them.
+		for _, member := range members {
+			err := h.s.AddRole(guild.GuildID, member.UserID, data.NewRole, api.AddRoleData{
+				AuditLogReason: auditReason,
+			})
+			if err == nil && removeOld {
+				err = h.s.RemoveRole(guild.GuildID, member.UserID, oldRole, auditReason)
+			}
+
+			switch {
+			case err == nil:
+				migrated++
+			case isNotFound(err):
+				left++
+			default:
+				h.LogErr(guild.GuildID, errors.Wrapf(err, "cannot migrate role of %v", member.UserID))
+				failed++
+			}
+		}
+
+		cursor.UserID = members[len(members)-1].UserID
+
+		if len(members) == roleMigrationProgressEvery {
+			h.FollowUp(cmdData.Event, &api.InteractionResponseData{
+				Flags: discord.EphemeralMessage,
+				Content: option.NewNullableString(fmt.Sprintf(
+					"⏳ Migrated %d member(s) so far...", migrated+left+failed,
+				)),
+			})
+		}
+	}
+
+	h.audit(guild, cmdData.Event.SenderID(), discord.Embed{
+		Title:       "Registered Role Migrated",
+		Description: fmt.Sprintf("Registered members now get %s instead of %s.", data.NewRole.Mention(), oldRole.Mention()),
+		Color:       auditColorChanged,
+		Fields: []discord.EmbedField{
+			{Name: "Migrated", Value: fmt.Sprint(migrated), Inline: true},
+			{Name: "Not in Server", Value: fmt.Sprint(left), Inline: true},
+			{Name: "Failed", Value: fmt.Sprint(failed), Inline: true},
+			{Name: "Old Role Removed", Value: fmt.Sprint(removeOld), Inline: true},
+		},
+	})
+
+	msg := fmt.Sprintf(""+
+		"Registered members now get %s.\n"+
+		"- **%d** member(s) migrated\n"+
+		"- **%d** member(s) are no longer in the server\n"+
+		"- **%d** member(s) failed, run this command again to retry",
+		data.NewRole.Mention(), migrated, left, failed,
+	)
+	if removeOld {
+		msg += "\n" + oldRole.Mention() + " has been removed from migrated members."
+	}
+
+	return &api.InteractionResponseData{
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString(msg),
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+// isNotFound returns true if the error is a 404 from Discord, e.g. when the
+// member has left the server.
+func isNotFound(err error) bool {
+	var httpErr *httputil.HTTPError
+	return errors.As(err, &httpErr) && httpErr.Status == http.StatusNotFound
+}
+
+// reconcileListMax is the maximum number of users listed for each kind of
+// mismatch in /registered-member reconcile.
+const reconcileListMax = 20
+
+func (h *Handler) cmdMemberReconcile(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	var data struct {
+		Fix bool `discord:"fix?"`
+	}
+
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	// Going through every member takes a while, so defer right away instead
+	// of waiting for the timeout.
+	cmdroute.DeferTicketFromContext(ctx).Defer()
+
+	// This needs the Server Members intent to be enabled for the bot.
+	guildMembers, err := h.s.WithContext(h.ctx).MembersAfter(guild.GuildID, 0, 0)
+	if err != nil {
+		return ErrorResponseData(errors.Wrap(err, "cannot list server members"))
+	}
+
+	// roleHolders is the set of users holding the registered role. Users are
+	// removed from it as their registrations are found.
+	roleHolders := make(map[discord.UserID]bool)
+	inGuild := make(map[discord.UserID]bool, len(guildMembers))
+
+	for _, member := range guildMembers {
+		if member.User.Bot {
+			continue
+		}
+		inGuild[member.User.ID] = true
+		for _, roleID := range member.RoleIDs {
+			if roleID == guild.RoleID {
+				roleHolders[member.User.ID] = true
+				break
+			}
+		}
+	}
+
+	// missingRole is the list of registered members without the role.
+	var missingRole []discord.UserID
+	var cursor acmregister.MemberCursor
+
+	for {
+		members, err := h.store.ListMembers(guild.GuildID, cursor, rosterPageSize)
+		if err != nil {
+			h.LogErr(guild.GuildID, errors.Wrap(err, "cannot list members"))
+			return InternalErrorResponseData()
+		}
+
+		if len(members) == 0 {
+			break
+		}
+
+		for _, member := range members {
+			if roleHolders[member.UserID] {
+				delete(roleHolders, member.UserID)
+				continue
+			}
+			// Members that left the server can't be given the role.
+			if inGuild[member.UserID] {
+				missingRole = append(missingRole, member.UserID)
+			}
+		}
+
+		cursor.UserID = members[len(members)-1].UserID
 	}
 
-	return &api.InteractionResponseData{
-		Flags:           discord.EphemeralMessage,
-		Content:         option.NewNullableString("User " + data.Who.Mention() + "'s nickname has been reset."),
-		AllowedMentions: &api.AllowedMentions{},
+	unregistered := make([]discord.UserID, 0, len(roleHolders))
+	for userID := range roleHolders {
+		unregistered = append(unregistered, userID)
 	}
-}
+	sort.Slice(unregistered, func(i, j int) bool {
+		return unregistered[i] < unregistered[j]
+	})
 
-func (h *Handler) cmdClearRegistration(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
-	_, err := h.store.GuildInfo(cmdData.Event.GuildID)
-	if err != nil {
-		logger := logger.FromContext(h.ctx)
-		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
-		return nil
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "**%d** registered member(s) without %s:\n", len(missingRole), guild.RoleID.Mention())
+	msg.WriteString(userList(missingRole))
+	fmt.Fprintf(&msg, "**%d** unregistered user(s) with %s:\n", len(unregistered), guild.RoleID.Mention())
+	msg.WriteString(userList(unregistered))
+
+	if !data.Fix {
+		msg.WriteString("This was a dry run, so nothing was changed. Use `fix: True` to fix these.")
+		return &api.InteractionResponseData{
+			Flags:           discord.EphemeralMessage,
+			Content:         option.NewNullableString(msg.String()),
+			AllowedMentions: &api.AllowedMentions{},
+		}
 	}
 
-	if err := h.store.DeleteGuild(cmdData.Event.GuildID); err != nil {
-		return ErrorResponseData(err)
+	auditReason := api.AuditLogReason(fmt.Sprintf(
+		"%s reconciled registered members, changed by acmRegister",
+		cmdData.Event.Sender().Tag(),
+	))
+
+	var failed int
+	for _, userID := range missingRole {
+		if err := h.s.AddRole(guild.GuildID, userID, guild.RoleID, api.AddRoleData{
+			AuditLogReason: auditReason,
+		}); err != nil {
+			h.LogErr(guild.GuildID, errors.Wrapf(err, "cannot add role to %v", userID))
+			failed++
+		}
+	}
+	for _, userID := range unregistered {
+		if err := h.s.RemoveRole(guild.GuildID, userID, guild.RoleID, auditReason); err != nil {
+			h.LogErr(guild.GuildID, errors.Wrapf(err, "cannot remove role from %v", userID))
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(&msg, "⚠️ Fixed all but **%d** user(s), run this command again to retry.", failed)
+	} else {
+		msg.WriteString("All of these have been fixed.")
 	}
 
 	return &api.InteractionResponseData{
-		Flags:   discord.EphemeralMessage,
-		Content: option.NewNullableString("Done. All members have been removed from the database, but their roles stay."),
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString(msg.String()),
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+// userList formats the users as a Markdown list, listing at most
+// reconcileListMax of them.
+func userList(userIDs []discord.UserID) string {
+	var b strings.Builder
+	for i, userID := range userIDs {
+		if i == reconcileListMax {
+			fmt.Fprintf(&b, "- ...and %d more\n", len(userIDs)-i)
+			break
+		}
+		b.WriteString("- " + userID.Mention() + "\n")
 	}
+	return b.String()
 }
 
 func (h *Handler) cmdMemberSetAllowedRole(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
-	_, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
 	if err != nil {
 		h.LogErr(cmdData.Event.GuildID, err)
 		return ErrorResponseData(errors.New("guild is not registered"))
@@ -362,6 +2030,9 @@ func (h *Handler) cmdMemberSetAllowedRole(ctx context.Context, cmdData cmdroute.
 		return InternalErrorResponseData()
 	}
 
+	h.auditSettings(guild, cmdData.Event.SenderID(), "/registered-member set-allowed-role",
+		"Users with "+data.Role.Mention()+" can now use this bot.")
+
 	return &api.InteractionResponseData{
 		Flags: discord.EphemeralMessage,
 		Content: option.NewNullableString("" +
@@ -372,89 +2043,396 @@ func (h *Handler) cmdMemberSetAllowedRole(ctx context.Context, cmdData cmdroute.
 }
 
 func (h *Handler) cmdEventExportMembers(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
-	_, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
 	if err != nil {
 		h.LogErr(cmdData.Event.GuildID, err)
 		return ErrorResponseData(errors.New("guild is not registered"))
 	}
 
+	if err := h.authorizeAdmin(cmdData.Event); err != nil {
+		return ErrorResponseData(err)
+	}
+
 	var data struct {
-		Event string `discord:"event"`
+		Event             string `discord:"event"`
+		Participants      string `discord:"participants?"`
+		Format            string `discord:"format?"`
+		IncludePronouns   bool   `discord:"include-pronouns?"`
+		IncludeNickname   bool   `discord:"include-nickname?"`
+		IncludeRegistered bool   `discord:"include-registered?"`
+		IncludeFields     bool   `discord:"include-fields?"`
+		RegisteredOnly    bool   `discord:"registered-only?"`
 	}
 	if err := cmdData.Options.Unmarshal(&data); err != nil {
 		return ErrorResponseData(err)
 	}
 
-	eventSnowflake, err := discord.ParseSnowflake(data.Event)
+	eventID, err := parseEventID(data.Event)
 	if err != nil {
-		return ErrorResponseData(errors.Wrap(err, "invalid event ID"))
+		return ErrorResponseData(err)
 	}
-	eventID := discord.EventID(eventSnowflake)
 
-	var members []api.GuildScheduledEventUser
-	for {
-		var after discord.UserID
-		if len(members) > 0 {
-			after = members[len(members)-1].User.ID
-		}
+	if err := checkParticipants(data.Participants); err != nil {
+		return ErrorResponseData(err)
+	}
 
-		page, err := h.s.ListScheduledEventUsers(cmdData.Event.GuildID, eventID, nil, false, 0, after)
-		if err != nil {
-			h.LogErr(cmdData.Event.GuildID, errors.Wrap(err, "cannot list event users"))
-			return InternalErrorResponseData()
-		}
-		if len(page) == 0 {
-			break
-		}
+	if data.Format == "" {
+		data.Format = "csv"
+	}
 
-		members = append(members, page...)
+	encode, ok := participantEncoders[data.Format]
+	if !ok {
+		return ErrorResponseData(fmt.Errorf("unknown format %q", data.Format))
 	}
 
-	type memberRecord struct {
-		UserID    discord.UserID
-		Username  string
-		FirstName string
-		LastName  string
-		Email     string
+	columns := participantColumns{
+		Pronouns:   data.IncludePronouns,
+		Nickname:   data.IncludeNickname,
+		Registered: data.IncludeRegistered,
+	}
+	if data.IncludeFields {
+		columns.Fields = guild.FormFields
+	}
+
+	members, err := h.eventParticipantUsers(cmdData.Event.GuildID, eventID, data.Participants)
+	if err != nil {
+		h.LogErr(cmdData.Event.GuildID, err)
+		return InternalErrorResponseData()
 	}
 
-	memberRecords := make([]memberRecord, 0, len(members))
+	records := make([]participantRecord, 0, len(members))
 	var missed int
 
 	for _, member := range members {
-		record := memberRecord{
-			UserID:   member.User.ID,
-			Username: member.User.Username,
+		record := participantRecord{
+			UserID:   member.ID,
+			Username: member.Username,
 		}
 
-		m, err := h.store.MemberInfo(cmdData.Event.GuildID, member.User.ID)
+		m, err := h.store.MemberInfo(cmdData.Event.GuildID, member.ID)
 		if err == nil {
-			record.FirstName = m.FirstName
-			record.LastName = m.LastName
-			record.Email = string(m.Email)
+			record.Registered = true
+			record.Metadata = *m
 		} else {
 			missed++
+			if data.RegisteredOnly {
+				continue
+			}
+		}
+
+		if columns.Nickname {
+			if m, err := h.s.Member(cmdData.Event.GuildID, member.ID); err == nil {
+				record.Nickname = m.Nick
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	var out bytes.Buffer
+	if err := encode(&out, columns, records); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	missedNote := "could not be found in the database"
+	if data.RegisteredOnly {
+		missedNote = "were left out for not being registered"
+	}
+
+	return &api.InteractionResponseData{
+		Content: option.NewNullableString(fmt.Sprintf(""+
+			"Here's a %s file with **%d member(s)** exported.\n"+
+			"A total of **%d member(s)** %s.",
+			strings.ToUpper(data.Format), len(records), missed, missedNote,
+		)),
+		Files: []sendpart.File{
+			{
+				Name:   "participants." + data.Format,
+				Reader: bytes.NewReader(out.Bytes()),
+			},
+		},
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+// participantRecord is a participant exported by /event-registration
+// export-members.
+type participantRecord struct {
+	UserID     discord.UserID
+	Username   string
+	Nickname   string
+	Registered bool
+	// Metadata is empty if the participant isn't registered.
+	Metadata acmregister.MemberMetadata
+}
+
+// participantColumns are the columns of /event-registration export-members
+// that have to be asked for.
+type participantColumns struct {
+	Pronouns   bool
+	Nickname   bool
+	Registered bool
+	// Fields are the custom form fields to export.
+	Fields []acmregister.FormField
+}
+
+// header returns the names of all exported columns.
+func (c participantColumns) header() []string {
+	header := []string{"user_id", "username", "first_name", "last_name", "email"}
+	if c.Pronouns {
+		header = append(header, "pronouns")
+	}
+	if c.Nickname {
+		header = append(header, "nickname")
+	}
+	if c.Registered {
+		header = append(header, "registered")
+	}
+	for _, field := range c.Fields {
+		header = append(header, field.Name)
+	}
+	return header
+}
+
+// row returns the record's values in the order of header.
+func (c participantColumns) row(record participantRecord) []string {
+	row := []string{
+		record.UserID.String(),
+		record.Username,
+		record.Metadata.FirstName,
+		record.Metadata.LastName,
+		string(record.Metadata.Email),
+	}
+	if c.Pronouns {
+		row = append(row, string(record.Metadata.Pronouns))
+	}
+	if c.Nickname {
+		row = append(row, record.Nickname)
+	}
+	if c.Registered {
+		row = append(row, strconv.FormatBool(record.Registered))
+	}
+	for _, field := range c.Fields {
+		row = append(row, record.Metadata.Extra[field.Name])
+	}
+	return row
+}
+
+// object returns the record as a JSON object. Custom form fields are kept
+// under "extra", like in /registered-member export.
+func (c participantColumns) object(record participantRecord) map[string]any {
+	object := map[string]any{
+		"user_id":    record.UserID.String(),
+		"username":   record.Username,
+		"first_name": record.Metadata.FirstName,
+		"last_name":  record.Metadata.LastName,
+		"email":      string(record.Metadata.Email),
+	}
+	if c.Pronouns {
+		object["pronouns"] = string(record.Metadata.Pronouns)
+	}
+	if c.Nickname {
+		object["nickname"] = record.Nickname
+	}
+	if c.Registered {
+		object["registered"] = record.Registered
+	}
+	if len(c.Fields) > 0 {
+		extra := make(map[string]string, len(c.Fields))
+		for _, field := range c.Fields {
+			extra[field.Name] = record.Metadata.Extra[field.Name]
+		}
+		object["extra"] = extra
+	}
+	return object
+}
+
+// participantEncoder writes all records to w with the given columns.
+type participantEncoder func(w io.Writer, columns participantColumns, records []participantRecord) error
+
+// participantEncoders maps the formats of /event-registration export-members
+// to their encoders. The format is also the file extension.
+var participantEncoders = map[string]participantEncoder{
+	"csv":   encodeParticipantsSeparated(','),
+	"tsv":   encodeParticipantsSeparated('\t'),
+	"json":  encodeParticipantsJSON,
+	"jsonl": encodeParticipantsJSONLines,
+}
+
+// encodeParticipantsSeparated returns an encoder that writes a header and a
+// row per record, separated by the given rune.
+func encodeParticipantsSeparated(comma rune) participantEncoder {
+	return func(w io.Writer, columns participantColumns, records []participantRecord) error {
+		csvw := csv.NewWriter(w)
+		csvw.Comma = comma
+		csvw.Write(columns.header())
+		for _, record := range records {
+			csvw.Write(columns.row(record))
+		}
+		csvw.Flush()
+		return errors.Wrap(csvw.Error(), "cannot write CSV")
+	}
+}
+
+// encodeParticipantsJSON writes a JSON array of all records.
+func encodeParticipantsJSON(w io.Writer, columns participantColumns, records []participantRecord) error {
+	objects := make([]map[string]any, len(records))
+	for i, record := range records {
+		objects[i] = columns.object(record)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return errors.Wrap(enc.Encode(objects), "cannot write JSON")
+}
+
+// encodeParticipantsJSONLines writes a JSON object per line for each record.
+func encodeParticipantsJSONLines(w io.Writer, columns participantColumns, records []participantRecord) error {
+	enc := json.NewEncoder(w)
+	for _, record := range records {
+		if err := enc.Encode(columns.object(record)); err != nil {
+			return errors.Wrap(err, "cannot write JSON")
+		}
+	}
+	return nil
+}
+
+// exportRangeDateLayout is the layout of the dates given to
+// /event-registration export-range.
+const exportRangeDateLayout = "2006-01-02"
+
+func (h *Handler) cmdEventExportRange(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		h.LogErr(cmdData.Event.GuildID, err)
+		return ErrorResponseData(errors.New("guild is not registered"))
+	}
+
+	if err := h.authorizeAdmin(cmdData.Event); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	var data struct {
+		From         string `discord:"from"`
+		To           string `discord:"to"`
+		Participants string `discord:"participants?"`
+	}
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	if err := checkParticipants(data.Participants); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	from, err := time.Parse(exportRangeDateLayout, data.From)
+	if err != nil {
+		return ErrorResponseData(fmt.Errorf("invalid from date %q, expected YYYY-MM-DD", data.From))
+	}
+
+	to, err := time.Parse(exportRangeDateLayout, data.To)
+	if err != nil {
+		return ErrorResponseData(fmt.Errorf("invalid to date %q, expected YYYY-MM-DD", data.To))
+	}
+
+	if to.Before(from) {
+		return ErrorResponseData(errors.New("the to date is before the from date"))
+	}
+
+	// Include all of the last day.
+	events, err := h.eventsBetween(guild.GuildID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		h.LogErr(guild.GuildID, err)
+		return InternalErrorResponseData()
+	}
+
+	if len(events) == 0 {
+		return ErrorResponseData(errors.New("there are no known events in that range"))
+	}
+
+	// Every event and member is looked up, so defer right away instead of
+	// waiting for the timeout.
+	cmdroute.DeferTicketFromContext(ctx).Defer()
+
+	attended := make([]map[discord.UserID]bool, len(events))
+	for i, event := range events {
+		userIDs, err := h.eventParticipantIDs(guild.GuildID, event.EventID, data.Participants)
+		if err != nil {
+			h.LogErr(guild.GuildID, errors.Wrapf(err, "cannot get participants of event %v", event.EventID))
+			return InternalErrorResponseData()
 		}
 
-		memberRecords = append(memberRecords, record)
+		attended[i] = make(map[discord.UserID]bool, len(userIDs))
+		for _, userID := range userIDs {
+			attended[i][userID] = true
+		}
 	}
 
 	var csvOut bytes.Buffer
 	csvw := csv.NewWriter(&csvOut)
-	csvw.Write([]string{"user_id", "username", "first_name", "last_name", "email"})
-	if err := xcsv.Marshal(csvw, memberRecords); err != nil {
+
+	header := []string{"user_id", "first_name", "last_name", "email"}
+	for _, event := range events {
+		header = append(header, fmt.Sprintf("%s (%s)",
+			event.Name, event.StartTime.UTC().Format(exportRangeDateLayout)))
+	}
+	header = append(header, "total")
+	csvw.Write(header)
+
+	var exported int
+	var cursor acmregister.MemberCursor
+	for {
+		members, err := h.store.ListMembers(guild.GuildID, cursor, rosterPageSize)
+		if err != nil {
+			h.LogErr(guild.GuildID, errors.Wrap(err, "cannot list members"))
+			return InternalErrorResponseData()
+		}
+
+		if len(members) == 0 {
+			break
+		}
+
+		for _, member := range members {
+			row := []string{
+				member.UserID.String(),
+				member.Metadata.FirstName,
+				member.Metadata.LastName,
+				string(member.Metadata.Email),
+			}
+
+			var total int
+			for i := range events {
+				if attended[i][member.UserID] {
+					row = append(row, "1")
+					total++
+				} else {
+					row = append(row, "0")
+				}
+			}
+
+			row = append(row, strconv.Itoa(total))
+			csvw.Write(row)
+		}
+
+		exported += len(members)
+		cursor.UserID = members[len(members)-1].UserID
+	}
+
+	csvw.Flush()
+	if err := csvw.Error(); err != nil {
 		return ErrorResponseData(errors.Wrap(err, "cannot write CSV"))
 	}
 
 	return &api.InteractionResponseData{
 		Content: option.NewNullableString(fmt.Sprintf(""+
-			"Here's a CSV file with **%d member(s)** exported.\n"+
-			"A total of **%d member(s)** could not be found in the database.",
-			len(members), missed,
+			"Here's a CSV file with **%d member(s)** across **%d event(s)** "+
+			"from %s to %s.\n"+
+			"Participants that aren't registered are not included.",
+			exported, len(events), data.From, data.To,
 		)),
 		Files: []sendpart.File{
 			{
-				Name:   "participants.csv",
+				Name:   fmt.Sprintf("attendance-%s-%s.csv", data.From, data.To),
 				Reader: bytes.NewReader(csvOut.Bytes()),
 			},
 		},
@@ -462,12 +2440,158 @@ func (h *Handler) cmdEventExportMembers(ctx context.Context, cmdData cmdroute.Co
 	}
 }
 
+// eventsBetween returns the guild's events that start at or after from and
+// before to, earliest first. Discord's events that haven't been saved are
+// included as well.
+func (h *Handler) eventsBetween(guildID discord.GuildID, from, to time.Time) ([]acmregister.ScheduledEvent, error) {
+	events, err := h.store.EventsBetween(guildID, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot list saved events")
+	}
+
+	saved := make(map[discord.EventID]bool, len(events))
+	for _, event := range events {
+		saved[event.EventID] = true
+	}
+
+	live, err := h.s.ListScheduledEvents(guildID, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot list events")
+	}
+
+	for i, event := range live {
+		start := event.StartTime.Time()
+		if saved[event.ID] || start.Before(from) || !start.Before(to) {
+			continue
+		}
+		events = append(events, savedEvent(&live[i], eventEnded(&live[i])))
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+
+	return events, nil
+}
+
+// checkParticipants returns an error if the participants option of an event
+// export is unknown.
+func checkParticipants(participants string) error {
+	switch participants {
+	case "", "interested", "attendees":
+		return nil
+	default:
+		return fmt.Errorf("unknown participants %q", participants)
+	}
+}
+
+// eventParticipantIDs returns the IDs of the event's participants, which are
+// either everyone interested or everyone that checked in.
+//
+// Discord forgets who is interested some time after the event is over, so the
+// saved participants are used for ended events and events that Discord can't
+// list anymore.
+func (h *Handler) eventParticipantIDs(guildID discord.GuildID, eventID discord.EventID, participants string) ([]discord.UserID, error) {
+	if participants == "attendees" {
+		attendees, err := h.store.EventAttendees(guildID, eventID)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot get event attendees")
+		}
+
+		userIDs := make([]discord.UserID, len(attendees))
+		for i, attendee := range attendees {
+			userIDs[i] = attendee.UserID
+		}
+		return userIDs, nil
+	}
+
+	saved, err := h.store.EventInfo(guildID, eventID)
+	if err == nil && saved.Ended {
+		return h.savedEventParticipantIDs(guildID, eventID)
+	}
+
+	users, err := h.listEventUsers(guildID, eventID)
+	if err != nil {
+		if saved == nil {
+			return nil, err
+		}
+		return h.savedEventParticipantIDs(guildID, eventID)
+	}
+
+	userIDs := make([]discord.UserID, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+	}
+	return userIDs, nil
+}
+
+// savedEventParticipantIDs returns the IDs of the saved participants of the
+// event.
+func (h *Handler) savedEventParticipantIDs(guildID discord.GuildID, eventID discord.EventID) ([]discord.UserID, error) {
+	userIDs, err := h.store.EventParticipants(guildID, eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get saved event participants")
+	}
+	return userIDs, nil
+}
+
+// eventParticipantUsers is like eventParticipantIDs, except the users are
+// fetched.
+func (h *Handler) eventParticipantUsers(guildID discord.GuildID, eventID discord.EventID, participants string) ([]discord.User, error) {
+	userIDs, err := h.eventParticipantIDs(guildID, eventID, participants)
+	if err != nil {
+		return nil, err
+	}
+	return h.resolveUsers(guildID, userIDs), nil
+}
+
+// listEventUsers asks Discord for everyone that is interested in the event.
+func (h *Handler) listEventUsers(guildID discord.GuildID, eventID discord.EventID) ([]discord.User, error) {
+	var users []discord.User
+	for {
+		var after discord.UserID
+		if len(users) > 0 {
+			after = users[len(users)-1].ID
+		}
+
+		page, err := h.s.ListScheduledEventUsers(guildID, eventID, nil, false, 0, after)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot list event users")
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		for _, eventUser := range page {
+			users = append(users, eventUser.User)
+		}
+	}
+
+	return users, nil
+}
+
+// resolveUsers fetches the users of the given IDs. Users that can't be
+// fetched anymore only have their IDs.
+func (h *Handler) resolveUsers(guildID discord.GuildID, userIDs []discord.UserID) []discord.User {
+	users := make([]discord.User, len(userIDs))
+	for i, userID := range userIDs {
+		if member, err := h.s.Member(guildID, userID); err == nil {
+			users[i] = member.User
+		} else if user, err := h.s.User(userID); err == nil {
+			users[i] = *user
+		} else {
+			users[i] = discord.User{ID: userID}
+		}
+	}
+	return users
+}
+
 var (
 	eventsAutocompletionGroup = singleflight.Group[discord.GuildID, []discord.GuildScheduledEvent]{}
 	eventsAutocompletionCache = ttlcache.New[discord.GuildID, []discord.GuildScheduledEvent]()
 )
 
-func (h *Handler) acEventExportMembers(ctx context.Context, acData cmdroute.AutocompleteData) api.AutocompleteChoices {
+func (h *Handler) acEvents(ctx context.Context, acData cmdroute.AutocompleteData) api.AutocompleteChoices {
 	client := h.s.WithContext(ctx)
 
 	switch option := acData.Options.Focused(); option.Name {
@@ -493,14 +2617,37 @@ func (h *Handler) acEventExportMembers(ctx context.Context, acData cmdroute.Auto
 		eventQuery := strings.ToLower(option.String())
 
 		var choices api.AutocompleteStringChoices
+		listed := make(map[discord.EventID]bool, len(events))
+
 		for _, event := range events {
 			if !strings.Contains(strings.ToLower(event.Name), eventQuery) {
 				continue
 			}
+			listed[event.ID] = true
 			choices = append(choices, discord.StringChoice{
-				Name:  fmt.Sprintf("%s (%d interested)", event.Name, event.UserCount),
+				Name:  truncate(fmt.Sprintf("%s (%d interested)", event.Name, event.UserCount), 100),
 				Value: event.ID.String(),
 			})
+			if len(choices) == 25 {
+				return choices
+			}
+		}
+
+		// Past events are only known from their snapshots.
+		saved, err := h.store.SearchEvents(acData.Event.GuildID, option.String(), 25)
+		if err != nil {
+			h.LogErr(acData.Event.GuildID, errors.Wrap(err, "cannot search saved events"))
+			return choices
+		}
+
+		for _, event := range saved {
+			if listed[event.EventID] {
+				continue
+			}
+			choices = append(choices, discord.StringChoice{
+				Name:  truncate(fmt.Sprintf("%s (%s)", event.Name, event.StartTime.Format("Jan 2, 2006")), 100),
+				Value: event.EventID.String(),
+			})
 			if len(choices) == 25 {
 				break
 			}
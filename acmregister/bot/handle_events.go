@@ -0,0 +1,247 @@
+package bot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/diamondburned/acmregister/acmregister"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/pkg/errors"
+)
+
+// DeparturePolicy decides what happens to registered members that leave the
+// guild.
+type DeparturePolicy string
+
+const (
+	// DepartureMark marks the member as departed.
+	DepartureMark DeparturePolicy = "mark"
+	// DepartureKeep leaves the member as-is. It is the default, since the
+	// other policies need the privileged Server Members Intent.
+	DepartureKeep DeparturePolicy = "keep"
+	// DepartureDelete unregisters the member.
+	DepartureDelete DeparturePolicy = "delete"
+)
+
+// ParseDeparturePolicy parses the given string into a DeparturePolicy.
+func ParseDeparturePolicy(policy string) (DeparturePolicy, error) {
+	switch p := DeparturePolicy(policy); p {
+	case DepartureMark, DepartureKeep, DepartureDelete:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown departure policy %q", policy)
+	}
+}
+
+// HandleMemberRemove handles a member leaving the guild according to
+// Opts.DeparturePolicy. It only works in gateway mode.
+func (h *Handler) HandleMemberRemove(ev *gateway.GuildMemberRemoveEvent) {
+	if ev.User.Bot {
+		return
+	}
+
+	guild, err := h.store.GuildInfo(ev.GuildID)
+	if err != nil {
+		return
+	}
+
+	switch h.opts.DeparturePolicy {
+	case DepartureMark:
+		err = h.store.SetMemberDeparted(ev.GuildID, ev.User.ID, time.Now())
+	case DepartureDelete:
+		err = h.store.UnregisterMember(ev.GuildID, ev.User.ID, 0)
+		if err == nil {
+			h.auditUnregistered(guild, 0, ev.User.ID, "has been unregistered for leaving the server.")
+		}
+	default:
+		return
+	}
+
+	if err != nil && !errors.Is(err, acmregister.ErrNotFound) {
+		h.LogErr(ev.GuildID, errors.Wrapf(err, "cannot handle %v leaving", ev.User.ID))
+	}
+}
+
+// HandleMemberAdd gives a registered member that rejoined the guild their role
+// and nickname back. It only works in gateway mode.
+func (h *Handler) HandleMemberAdd(ev *gateway.GuildMemberAddEvent) {
+	if ev.User.Bot {
+		return
+	}
+
+	guild, err := h.store.GuildInfo(ev.GuildID)
+	if err != nil {
+		return
+	}
+
+	metadata, err := h.store.MemberInfo(ev.GuildID, ev.User.ID)
+	if err != nil {
+		if !errors.Is(err, acmregister.ErrNotFound) {
+			h.LogErr(ev.GuildID, errors.Wrapf(err, "cannot get info of rejoined member %v", ev.User.ID))
+		}
+		return
+	}
+
+	if err := h.store.SetMemberDeparted(ev.GuildID, ev.User.ID, time.Time{}); err != nil {
+		h.LogErr(ev.GuildID, errors.Wrap(err, "cannot mark member as rejoined (not important)"))
+	}
+
+	if err := h.s.AddRole(guild.GuildID, ev.User.ID, guild.RoleID, api.AddRoleData{
+		AuditLogReason: "registered member rejoined, added by acmRegister",
+	}); err != nil {
+		h.LogErr(ev.GuildID, errors.Wrap(err, "cannot add role to rejoined member"))
+		return
+	}
+
+	if err := h.setNickname(guild, ev.User.ID, *metadata); err != nil {
+		h.LogErr(ev.GuildID, errors.Wrap(err, "cannot nickname rejoined member (not important)"))
+	}
+}
+
+// HandleGuildCreate snapshots the scheduled events of a known guild, since
+// their participants may have changed while the bot was offline. It only works
+// in gateway mode.
+func (h *Handler) HandleGuildCreate(ev *gateway.GuildCreateEvent) {
+	if _, err := h.store.GuildInfo(ev.ID); err != nil {
+		return
+	}
+
+	events, err := h.s.ListScheduledEvents(ev.ID, false)
+	if err != nil {
+		h.LogErr(ev.ID, errors.Wrap(err, "cannot list events to snapshot"))
+		return
+	}
+
+	for i := range events {
+		if err := h.snapshotEvent(&events[i]); err != nil {
+			h.LogErr(ev.ID, err)
+		}
+	}
+}
+
+// HandleScheduledEventCreate snapshots a new scheduled event. It only works in
+// gateway mode.
+func (h *Handler) HandleScheduledEventCreate(ev *gateway.GuildScheduledEventCreateEvent) {
+	h.handleScheduledEventChange(&ev.GuildScheduledEvent)
+}
+
+// HandleScheduledEventUpdate snapshots a scheduled event whose details or
+// status changed. It only works in gateway mode.
+func (h *Handler) HandleScheduledEventUpdate(ev *gateway.GuildScheduledEventUpdateEvent) {
+	h.handleScheduledEventChange(&ev.GuildScheduledEvent)
+}
+
+func (h *Handler) handleScheduledEventChange(event *discord.GuildScheduledEvent) {
+	if _, err := h.store.GuildInfo(event.GuildID); err != nil {
+		return
+	}
+
+	if err := h.snapshotEvent(event); err != nil {
+		h.LogErr(event.GuildID, err)
+	}
+}
+
+// HandleScheduledEventDelete marks a deleted scheduled event as ended, keeping
+// its participants. It only works in gateway mode.
+func (h *Handler) HandleScheduledEventDelete(ev *gateway.GuildScheduledEventDeleteEvent) {
+	if _, err := h.store.GuildInfo(ev.GuildID); err != nil {
+		return
+	}
+
+	if err := h.store.SaveEvent(savedEvent(&ev.GuildScheduledEvent, true)); err != nil {
+		h.LogErr(ev.GuildID, errors.Wrap(err, "cannot save deleted event"))
+	}
+}
+
+// HandleScheduledEventUserAdd saves a user that became interested in a
+// scheduled event. It only works in gateway mode.
+func (h *Handler) HandleScheduledEventUserAdd(ev *gateway.GuildScheduledEventUserAddEvent) {
+	if _, err := h.store.GuildInfo(ev.GuildID); err != nil {
+		return
+	}
+
+	saved, err := h.store.EventInfo(ev.GuildID, ev.EventID)
+	if err != nil {
+		if !errors.Is(err, acmregister.ErrNotFound) {
+			h.LogErr(ev.GuildID, errors.Wrap(err, "cannot get saved event"))
+			return
+		}
+
+		// The event was created before it could be saved, so snapshot all of
+		// it, which includes this user.
+		event, err := h.s.ScheduledEvent(ev.GuildID, ev.EventID, false)
+		if err != nil {
+			h.LogErr(ev.GuildID, errors.Wrap(err, "cannot get event to snapshot"))
+			return
+		}
+		if err := h.snapshotEvent(event); err != nil {
+			h.LogErr(ev.GuildID, err)
+		}
+		return
+	}
+
+	if saved.Ended {
+		return
+	}
+
+	if err := h.store.AddEventParticipants(ev.GuildID, ev.EventID, ev.UserID); err != nil {
+		h.LogErr(ev.GuildID, errors.Wrap(err, "cannot save event participant"))
+	}
+}
+
+// HandleScheduledEventUserRemove removes a user that is no longer interested
+// in a scheduled event. The participants of ended events are kept as they
+// were. It only works in gateway mode.
+func (h *Handler) HandleScheduledEventUserRemove(ev *gateway.GuildScheduledEventUserRemoveEvent) {
+	if _, err := h.store.GuildInfo(ev.GuildID); err != nil {
+		return
+	}
+
+	saved, err := h.store.EventInfo(ev.GuildID, ev.EventID)
+	if err == nil && saved.Ended {
+		return
+	}
+
+	if err := h.store.RemoveEventParticipant(ev.GuildID, ev.EventID, ev.UserID); err != nil {
+		h.LogErr(ev.GuildID, errors.Wrap(err, "cannot remove event participant"))
+	}
+}
+
+// snapshotEvent saves the event along with everyone that Discord lists as
+// interested in it. The listed users are added to the saved participants
+// rather than replacing them, since Discord stops listing them some time after
+// the event.
+func (h *Handler) snapshotEvent(event *discord.GuildScheduledEvent) error {
+	if err := h.store.SaveEvent(savedEvent(event, eventEnded(event))); err != nil {
+		return errors.Wrapf(err, "cannot save event %v", event.ID)
+	}
+
+	users, err := h.listEventUsers(event.GuildID, event.ID)
+	if err != nil {
+		return errors.Wrapf(err, "cannot snapshot participants of event %v", event.ID)
+	}
+
+	userIDs := make([]discord.UserID, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+	}
+
+	if err := h.store.AddEventParticipants(event.GuildID, event.ID, userIDs...); err != nil {
+		return errors.Wrapf(err, "cannot save participants of event %v", event.ID)
+	}
+
+	return nil
+}
+
+// savedEvent converts the event into its snapshot.
+func savedEvent(event *discord.GuildScheduledEvent, ended bool) acmregister.ScheduledEvent {
+	return acmregister.ScheduledEvent{
+		GuildID:   event.GuildID,
+		EventID:   event.ID,
+		Name:      event.Name,
+		StartTime: event.StartTime.Time(),
+		Ended:     ended,
+	}
+}
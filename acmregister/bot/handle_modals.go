@@ -1,6 +1,7 @@
 package bot
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/diamondburned/acmregister/acmregister"
@@ -35,10 +36,26 @@ func (h *Handler) modalRegisterResponse(ev *discord.InteractionEvent, modal *dis
 		return ErrorResponse(err)
 	}
 
-	metadata := acmregister.MemberMetadata(data)
-	metadata.Email = acmregister.Email(strings.TrimSpace(string(metadata.Email)))
-	metadata.FirstName = strings.TrimSpace(metadata.FirstName)
-	metadata.LastName = strings.TrimSpace(metadata.LastName)
+	metadata := acmregister.MemberMetadata{
+		Email:     acmregister.Email(strings.TrimSpace(string(data.Email))),
+		FirstName: strings.TrimSpace(data.FirstName),
+		LastName:  strings.TrimSpace(data.LastName),
+		Pronouns:  data.Pronouns,
+		Extra:     make(map[string]string),
+	}
+
+	// Keep the values of the other pages if the user is filling in the form
+	// again.
+	if prev, err := h.store.RestoreSubmission(ev.GuildID, ev.SenderID()); err == nil {
+		for _, field := range guild.FormFields {
+			if v, ok := prev.Extra[field.Name]; ok {
+				metadata.Extra[field.Name] = v
+			}
+		}
+	}
+
+	fields := formFieldPage(guild.FormFields, 0)
+	readFormFieldInputs(modal, fields, metadata.Extra)
 
 	member := acmregister.Member{
 		GuildID:  ev.GuildID,
@@ -59,11 +76,191 @@ func (h *Handler) modalRegisterResponse(ev *discord.InteractionEvent, modal *dis
 		return ErrorResponse(err)
 	}
 
+	if err := acmregister.ValidateFormFields(fields, metadata.Extra); err != nil {
+		return ErrorResponse(err)
+	}
+
+	if formFieldPages(guild.FormFields) > 1 {
+		return registerContinueResponse(newRegistrationForm, guild.FormFields, 1)
+	}
+
+	return h.finishRegistration(ev, guild, member)
+}
+
+func (h *Handler) modalRegisterFields(ev *discord.InteractionEvent, modal *discord.ModalInteraction, form registerForm, arg string) *api.InteractionResponse {
+	guild, err := h.store.GuildInfo(ev.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", ev.GuildID, "reason:", err)
+		return nil
+	}
+
+	_, err = h.store.MemberInfo(ev.GuildID, ev.SenderID())
+	switch {
+	case form == newRegistrationForm && err == nil:
+		return ErrorResponse(errors.New("you're already registered!"))
+	case form == editRegistrationForm && err != nil:
+		return ErrorResponse(errors.New("you're not registered"))
+	}
+
+	pages := formFieldPages(guild.FormFields)
+
+	page, err := strconv.Atoi(arg)
+	if err != nil || page < 1 || page >= pages {
+		return ErrorResponse(errors.New("the registration form has changed, " + form.restart))
+	}
+
+	metadata, err := h.store.RestoreSubmission(ev.GuildID, ev.SenderID())
+	if err != nil {
+		return ErrorResponse(errors.New("your submission has expired, " + form.restart))
+	}
+
+	if metadata.Extra == nil {
+		metadata.Extra = make(map[string]string)
+	}
+
+	fields := formFieldPage(guild.FormFields, page)
+	readFormFieldInputs(modal, fields, metadata.Extra)
+
+	member := acmregister.Member{
+		GuildID:  ev.GuildID,
+		UserID:   ev.SenderID(),
+		Metadata: *metadata,
+	}
+
+	if err := h.store.SaveSubmission(member); err != nil {
+		h.LogErr(ev.GuildID, errors.Wrap(err, "cannot save registration submission (not important)"))
+		// not important so we continue
+	}
+
+	if err := acmregister.ValidateFormFields(fields, metadata.Extra); err != nil {
+		return ErrorResponse(err)
+	}
+
+	if page+1 < pages {
+		return registerContinueResponse(form, guild.FormFields, page+1)
+	}
+
+	// Catch fields on earlier pages that were skipped or changed since.
+	if err := acmregister.ValidateFormFields(guild.FormFields, metadata.Extra); err != nil {
+		return ErrorResponse(errors.Wrap(err, form.restart+" to fix this"))
+	}
+
+	if form == editRegistrationForm {
+		return h.finishEdit(ev, guild, member)
+	}
+
+	return h.finishRegistration(ev, guild, member)
+}
+
+// finishRegistration either sends a confirmation email for the submitted member
+// or registers them right away if emails aren't confirmed.
+func (h *Handler) finishRegistration(ev *discord.InteractionEvent, guild *acmregister.KnownGuild, member acmregister.Member) *api.InteractionResponse {
 	if h.opts.EmailScheduler == nil {
-		return h.registerAndRespond(ev, guild, metadata)
+		return h.registerAndRespond(ev, guild, member.Metadata)
 	}
 
-	if err := h.opts.EmailScheduler.ScheduleConfirmationEmail(&h.Client, ev, member); err != nil {
+	if err := h.opts.EmailScheduler.ScheduleConfirmationEmail(&h.Client, ev, member, guild.Messages); err != nil {
+		h.LogErr(ev.GuildID, errors.Wrap(err, "cannot schedule confirmation email"))
+		return InternalErrorResponse()
+	}
+
+	return deferResponse(discord.EphemeralMessage)
+}
+
+func (h *Handler) modalEditResponse(ev *discord.InteractionEvent, modal *discord.ModalInteraction) *api.InteractionResponse {
+	guild, err := h.store.GuildInfo(ev.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", ev.GuildID, "reason:", err)
+		return nil
+	}
+
+	current, err := h.store.MemberInfo(ev.GuildID, ev.SenderID())
+	if err != nil {
+		return ErrorResponse(errors.New("you're not registered"))
+	}
+
+	var data struct {
+		Email     acmregister.Email    `discord:"email"`
+		FirstName string               `discord:"first"`
+		LastName  string               `discord:"last?"`
+		Pronouns  acmregister.Pronouns `discord:"pronouns?"`
+	}
+
+	if err := modal.Components.Unmarshal(&data); err != nil {
+		return ErrorResponse(err)
+	}
+
+	metadata := acmregister.MemberMetadata{
+		Email:     acmregister.Email(strings.TrimSpace(string(data.Email))),
+		FirstName: strings.TrimSpace(data.FirstName),
+		LastName:  strings.TrimSpace(data.LastName),
+		Pronouns:  data.Pronouns,
+		Extra:     make(map[string]string),
+	}
+
+	// Values on the other pages start from the current registration, unless
+	// the user is filling in the form again.
+	prev := current
+	if submission, err := h.store.RestoreSubmission(ev.GuildID, ev.SenderID()); err == nil {
+		prev = submission
+	}
+	for _, field := range guild.FormFields {
+		if v, ok := prev.Extra[field.Name]; ok {
+			metadata.Extra[field.Name] = v
+		}
+	}
+
+	fields := formFieldPage(guild.FormFields, 0)
+	readFormFieldInputs(modal, fields, metadata.Extra)
+
+	member := acmregister.Member{
+		GuildID:  ev.GuildID,
+		UserID:   ev.SenderID(),
+		Metadata: metadata,
+	}
+
+	if err := h.store.SaveSubmission(member); err != nil {
+		h.LogErr(ev.GuildID, errors.Wrap(err, "cannot save registration submission (not important)"))
+		// not important so we continue
+	}
+
+	if err := metadata.Pronouns.Validate(); err != nil {
+		return ErrorResponse(err)
+	}
+
+	if metadata.Email != current.Email {
+		if err := h.opts.verifyEmail(h.ctx, metadata.Email); err != nil {
+			return ErrorResponse(err)
+		}
+	}
+
+	if err := acmregister.ValidateFormFields(fields, metadata.Extra); err != nil {
+		return ErrorResponse(err)
+	}
+
+	if formFieldPages(guild.FormFields) > 1 {
+		return registerContinueResponse(editRegistrationForm, guild.FormFields, 1)
+	}
+
+	return h.finishEdit(ev, guild, member)
+}
+
+// finishEdit updates the registration of the member right away, unless their
+// email has changed and emails are confirmed, in which case a confirmation
+// email is sent to the new email first.
+func (h *Handler) finishEdit(ev *discord.InteractionEvent, guild *acmregister.KnownGuild, member acmregister.Member) *api.InteractionResponse {
+	current, err := h.store.MemberInfo(ev.GuildID, ev.SenderID())
+	if err != nil {
+		return ErrorResponse(errors.New("you're not registered"))
+	}
+
+	if h.opts.EmailScheduler == nil || member.Metadata.Email == current.Email {
+		return h.updateAndRespond(ev, guild, member.Metadata)
+	}
+
+	if err := h.opts.EmailScheduler.ScheduleConfirmationEmail(&h.Client, ev, member, guild.Messages); err != nil {
 		h.LogErr(ev.GuildID, errors.Wrap(err, "cannot schedule confirmation email"))
 		return InternalErrorResponse()
 	}
@@ -105,17 +302,69 @@ func (h *Handler) modalVerifyPIN(ev *discord.InteractionEvent, modal *discord.Mo
 
 	// At this point, the user ID matches with the known email, and the given
 	// PIN also matches that email, so we're good.
+	if err := h.store.AddMemberEvent(acmregister.MemberEvent{
+		GuildID: ev.GuildID,
+		UserID:  ev.SenderID(),
+		Type:    acmregister.MemberVerified,
+		ActorID: ev.SenderID(),
+	}); err != nil {
+		h.PrivateWarning(ev, errors.Wrap(err, "cannot record email verification (not important)"))
+	}
+
+	if _, err := h.store.MemberInfo(ev.GuildID, ev.SenderID()); err == nil {
+		// The member is changing their email.
+		return h.updateAndRespond(ev, guild, *metadata)
+	}
+
 	return h.registerAndRespond(ev, guild, *metadata)
 }
 
+func (h *Handler) updateAndRespond(ev *discord.InteractionEvent, guild *acmregister.KnownGuild, metadata acmregister.MemberMetadata) *api.InteractionResponse {
+	// The PIN could have been sent before the guild's form fields changed.
+	if err := acmregister.ValidateFormFields(guild.FormFields, metadata.Extra); err != nil {
+		return ErrorResponse(errors.Wrap(err, editRegistrationForm.restart+" to fix this"))
+	}
+
+	if err := h.store.UpdateMemberMetadata(ev.GuildID, ev.SenderID(), metadata); err != nil {
+		if errors.Is(err, acmregister.ErrMemberAlreadyExists) {
+			return ErrorResponse(err)
+		}
+		h.PrivateWarning(ev, errors.Wrap(err, "cannot update member in database"))
+		return InternalErrorResponse()
+	}
+
+	h.auditEdited(guild, ev.SenderID(), metadata)
+
+	if err := h.setNickname(guild, ev.SenderID(), metadata); err != nil {
+		h.PrivateWarning(ev, errors.Wrap(err, "cannot nickname edited member (not important)"))
+	}
+
+	return msgResponse(&api.InteractionResponseData{
+		Flags:   discord.EphemeralMessage,
+		Content: option.NewNullableString("Your registration has been updated."),
+	})
+}
+
 func (h *Handler) registerAndRespond(ev *discord.InteractionEvent, guild *acmregister.KnownGuild, metadata acmregister.MemberMetadata) *api.InteractionResponse {
+	// The PIN could have been sent before the guild's form fields changed.
+	if err := acmregister.ValidateFormFields(guild.FormFields, metadata.Extra); err != nil {
+		return ErrorResponse(errors.Wrap(err, "press Register again to fix this"))
+	}
+
+	if guild.ApprovalChannelID.IsValid() {
+		return h.submitForApproval(ev, guild, metadata)
+	}
+
 	member := acmregister.Member{
 		GuildID:  ev.GuildID,
 		UserID:   ev.SenderID(),
 		Metadata: metadata,
 	}
 
-	if err := h.store.RegisterMember(member); err != nil && !errors.Is(err, acmregister.ErrMemberAlreadyExists) {
+	switch err := h.store.RegisterMember(member); {
+	case err == nil:
+		h.auditRegistered(guild, ev.SenderID(), ev.SenderID(), metadata)
+	case !errors.Is(err, acmregister.ErrMemberAlreadyExists):
 		h.PrivateWarning(ev, errors.Wrap(err, "cannot save into database"))
 		return InternalErrorResponse()
 	}
@@ -124,25 +373,42 @@ func (h *Handler) registerAndRespond(ev *discord.InteractionEvent, guild *acmreg
 }
 
 func (h *Handler) assignThenRespond(ev *discord.InteractionEvent, guild *acmregister.KnownGuild, metadata acmregister.MemberMetadata) *api.InteractionResponse {
-	if err := h.s.AddRole(guild.GuildID, ev.SenderID(), guild.RoleID, api.AddRoleData{
-		AuditLogReason: "member registered, added by acmRegister",
-	}); err != nil {
-		h.PrivateWarning(ev, errors.Wrap(err, "cannot add role"))
+	if err := h.assignRegistered(guild, ev.SenderID(), metadata); err != nil {
+		h.PrivateWarning(ev, err)
 		return InternalErrorResponse()
 	}
 
-	if err := h.s.ModifyMember(guild.GuildID, ev.SenderID(), api.ModifyMemberData{
-		Nick: option.NewString(metadata.Nickname()),
+	return msgResponse(&api.InteractionResponseData{
+		Flags:   discord.EphemeralMessage,
+		Content: option.NewNullableString(guildRegisteredMessage(guild)),
+	})
+}
+
+// assignRegistered gives the registered role to the member and nicknames them.
+// Failing to nickname the member is only logged.
+func (h *Handler) assignRegistered(guild *acmregister.KnownGuild, userID discord.UserID, metadata acmregister.MemberMetadata) error {
+	if err := h.s.AddRole(guild.GuildID, userID, guild.RoleID, api.AddRoleData{
+		AuditLogReason: "member registered, added by acmRegister",
 	}); err != nil {
-		h.PrivateWarning(ev, errors.Wrap(err, "cannot nickname new member (not important)"))
+		return errors.Wrap(err, "cannot add role")
 	}
 
-	msg := guild.RegisteredMessage
-	if msg == "" {
-		msg = registeredMessage
+	if err := h.setNickname(guild, userID, metadata); err != nil {
+		h.LogErr(guild.GuildID, errors.Wrap(err, "cannot nickname new member (not important)"))
 	}
-	return msgResponse(&api.InteractionResponseData{
-		Flags:   discord.EphemeralMessage,
-		Content: option.NewNullableString(msg),
+
+	return nil
+}
+
+// setNickname nicknames the member using the guild's nickname settings. It does
+// nothing if the guild disabled nicknames.
+func (h *Handler) setNickname(guild *acmregister.KnownGuild, userID discord.UserID, metadata acmregister.MemberMetadata) error {
+	nick, ok := guild.Nickname.Nickname(metadata)
+	if !ok {
+		return nil
+	}
+
+	return h.s.ModifyMember(guild.GuildID, userID, api.ModifyMemberData{
+		Nick: option.NewString(nick),
 	})
 }
@@ -0,0 +1,191 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/diamondburned/acmregister/acmregister"
+	"github.com/diamondburned/acmregister/acmregister/logger"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/api/cmdroute"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/pkg/errors"
+)
+
+var errNotRegistered = errors.New("you're not registered in this server")
+
+func (h *Handler) cmdMyRegistrationView(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	metadata, err := h.store.MemberInfo(guild.GuildID, cmdData.Event.SenderID())
+	if err != nil {
+		return ErrorResponseData(errNotRegistered)
+	}
+
+	pronouns := pronounsText(metadata.Pronouns)
+	if pronouns == "" {
+		pronouns = "*not set*"
+	}
+
+	var b strings.Builder
+	b.WriteString("Your registration in this server:\n")
+	fmt.Fprintf(&b, "**Name:** %s\n", metadata.Name())
+	fmt.Fprintf(&b, "**Email:** %s\n", metadata.Email)
+	fmt.Fprintf(&b, "**Pronouns:** %s\n", pronouns)
+	for _, field := range guild.FormFields {
+		value := metadata.Extra[field.Name]
+		if value == "" {
+			value = "*not set*"
+		}
+		fmt.Fprintf(&b, "**%s:** %s\n", field.Label, value)
+	}
+	b.WriteString("\nUse `/my-registration edit` to change any of this.")
+
+	return &api.InteractionResponseData{
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString(b.String()),
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+// cmdMyRegistrationEdit opens the register form pre-filled with the member's
+// registration. The returned data is turned into a modal by modalResponses.
+func (h *Handler) cmdMyRegistrationEdit(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	metadata, err := h.store.MemberInfo(guild.GuildID, cmdData.Event.SenderID())
+	if err != nil {
+		return ErrorResponseData(errNotRegistered)
+	}
+
+	return h.makeRegisterModal(editRegistrationForm, *metadata, guild.FormFields)
+}
+
+func (h *Handler) cmdMyRegistrationResetNickname(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	metadata, err := h.store.MemberInfo(guild.GuildID, cmdData.Event.SenderID())
+	if err != nil {
+		return ErrorResponseData(errNotRegistered)
+	}
+
+	if guild.Nickname.Disabled {
+		return ErrorResponseData(errors.New("nicknames are disabled in this server"))
+	}
+
+	if err := h.setNickname(guild, cmdData.Event.SenderID(), *metadata); err != nil {
+		h.PrivateWarning(cmdData.Event, errors.Wrap(err, "cannot reset nickname"))
+		return InternalErrorResponseData()
+	}
+
+	h.auditNicknameReset(guild, cmdData.Event.SenderID(), cmdData.Event.SenderID(), *metadata)
+
+	return &api.InteractionResponseData{
+		Flags:   discord.EphemeralMessage,
+		Content: option.NewNullableString("Your nickname has been reset."),
+	}
+}
+
+func (h *Handler) cmdMyRegistrationLeave(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	if _, err := h.store.MemberInfo(guild.GuildID, cmdData.Event.SenderID()); err != nil {
+		return ErrorResponseData(errNotRegistered)
+	}
+
+	msg := "" +
+		"⚠️ This deletes your registration from this server and removes " +
+		guild.RoleID.Mention() + " from you. You can register again at any time."
+
+	return &api.InteractionResponseData{
+		Flags:   discord.EphemeralMessage,
+		Content: option.NewNullableString(msg),
+		Components: &discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.ButtonComponent{
+					Style:    discord.DangerButtonStyle(),
+					CustomID: "leave-registration:confirm",
+					Label:    "Leave",
+				},
+				&discord.ButtonComponent{
+					Style:    discord.SecondaryButtonStyle(),
+					CustomID: "leave-registration:cancel",
+					Label:    "Cancel",
+				},
+			},
+		},
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+func (h *Handler) buttonLeaveRegistration(ev *discord.InteractionEvent, arg string) *api.InteractionResponse {
+	update := func(content string) *api.InteractionResponse {
+		return &api.InteractionResponse{
+			Type: api.UpdateMessage,
+			Data: &api.InteractionResponseData{
+				Content:    option.NewNullableString(content),
+				Components: &discord.ContainerComponents{},
+			},
+		}
+	}
+
+	if arg != "confirm" {
+		return update("Cancelled. You're still registered.")
+	}
+
+	guild, err := h.store.GuildInfo(ev.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", ev.GuildID, "reason:", err)
+		return nil
+	}
+
+	if err := h.store.UnregisterMember(ev.GuildID, ev.SenderID(), ev.SenderID()); err != nil {
+		if errors.Is(err, acmregister.ErrNotFound) {
+			return update("You're not registered anymore.")
+		}
+		h.PrivateWarning(ev, errors.Wrap(err, "cannot unregister member"))
+		return InternalErrorResponse()
+	}
+
+	h.auditUnregistered(guild, ev.SenderID(), ev.SenderID(), "has left the registration.")
+
+	if err := h.s.RemoveRole(
+		ev.GuildID, ev.SenderID(), guild.RoleID,
+		api.AuditLogReason("member left the registration, removed by acmRegister"),
+	); err != nil {
+		h.PrivateWarning(ev, errors.Wrap(err, "cannot remove role from leaving member"))
+	}
+
+	if !guild.Nickname.Disabled {
+		if err := h.s.ModifyMember(ev.GuildID, ev.SenderID(), api.ModifyMemberData{
+			Nick: option.NewString(""),
+		}); err != nil {
+			h.PrivateWarning(ev, errors.Wrap(err, "cannot clear nickname of leaving member (not important)"))
+		}
+	}
+
+	return update("You've left the registration. Press Register to register again.")
+}
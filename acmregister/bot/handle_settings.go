@@ -0,0 +1,651 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/diamondburned/acmregister/acmregister"
+	"github.com/diamondburned/acmregister/acmregister/logger"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/api/cmdroute"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/pkg/errors"
+)
+
+func (h *Handler) cmdSettingsMessages(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	var data struct {
+		RegisterButtonLabel  string `discord:"register-button-label?"`
+		RegisteredMessage    string `discord:"registered-message?"`
+		VerifyPINMessage     string `discord:"verify-pin-message?"`
+		VerifyPINButtonLabel string `discord:"verify-pin-button-label?"`
+		VerifyPINModalTitle  string `discord:"verify-pin-modal-title?"`
+		Reset                bool   `discord:"reset?"`
+	}
+
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	registered := guild.RegisteredMessage
+	messages := guild.Messages
+
+	if data.Reset {
+		registered = ""
+		messages = acmregister.GuildMessages{}
+	}
+
+	set := func(dst *string, v string) {
+		if v = strings.TrimSpace(v); v != "" {
+			*dst = v
+		}
+	}
+
+	set(&registered, data.RegisteredMessage)
+	set(&messages.RegisterButtonLabel, data.RegisterButtonLabel)
+	set(&messages.VerifyPINMessage, data.VerifyPINMessage)
+	set(&messages.VerifyPINButtonLabel, data.VerifyPINButtonLabel)
+	set(&messages.VerifyPINModalTitle, data.VerifyPINModalTitle)
+
+	changed := registered != guild.RegisteredMessage || messages != guild.Messages
+	if changed {
+		if err := h.store.GuildSetMessages(guild.GuildID, registered, messages); err != nil {
+			h.LogErr(guild.GuildID, errors.Wrap(err, "cannot set guild messages"))
+			return InternalErrorResponseData()
+		}
+	}
+
+	var msg strings.Builder
+	if changed {
+		msg.WriteString("Messages updated! ")
+	}
+	msg.WriteString("This server uses these messages:\n")
+
+	line := func(name, value, defaultValue string) {
+		if value == "" {
+			value = defaultValue + " *(default)*"
+		}
+		fmt.Fprintf(&msg, "- **%s:** %s\n", name, value)
+	}
+
+	line("Register button label", messages.RegisterButtonLabel, registeredButtonLabel)
+	line("Registered message", registered, registeredMessage)
+	line("Verify PIN message", messages.VerifyPINMessage, verifyPINMessage)
+	line("Verify PIN button label", messages.VerifyPINButtonLabel, verifyPINButtonLabel)
+	line("Verify PIN modal title", messages.VerifyPINModalTitle, verifyPINModalTitle)
+
+	if messages.RegisterButtonLabel != guild.Messages.RegisterButtonLabel {
+		err := h.editRegisterPanel(guild, "", messages)
+		switch {
+		case err == nil:
+			msg.WriteString("\nThe register message now uses the new Register button label.")
+		case errors.Is(err, errRegisterMessageUnknown) || isNotFound(err):
+			msg.WriteString("\nThe register message cannot be found, so it still has the old Register button label. " +
+				"Use `/registration-settings panel` with a message to post a new one.")
+		default:
+			h.LogErr(guild.GuildID, err)
+			msg.WriteString("\nThe register message could not be updated with the new Register button label.")
+		}
+	}
+
+	if changed {
+		h.auditSettings(guild, cmdData.Event.SenderID(), "/registration-settings messages", msg.String())
+	}
+
+	return &api.InteractionResponseData{
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString(truncate(msg.String(), maxContent)),
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+var errRegisterMessageUnknown = errors.New("register message is unknown")
+
+// editRegisterPanel edits the register message in place to use the given body
+// and messages. An empty body keeps the current one. errRegisterMessageUnknown
+// is returned if the guild was initialized before the message was remembered.
+func (h *Handler) editRegisterPanel(guild *acmregister.KnownGuild, body string, msgs acmregister.GuildMessages) error {
+	if !guild.RegisterMessageID.IsValid() {
+		return errRegisterMessageUnknown
+	}
+
+	components := registerPanelComponents(msgs)
+	data := api.EditMessageData{Components: &components}
+	if body != "" {
+		data.Content = option.NewNullableString(body)
+	}
+
+	_, err := h.s.EditMessageComplex(guild.ChannelID, guild.RegisterMessageID, data)
+	return errors.Wrap(err, "cannot edit register message")
+}
+
+func (h *Handler) cmdSettingsPanel(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	var data struct {
+		Message     string            `discord:"message?"`
+		ButtonLabel string            `discord:"button-label?"`
+		ChannelID   discord.ChannelID `discord:"channel?"`
+		RoleID      discord.RoleID    `discord:"registered-role?"`
+	}
+
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	if discord.Snowflake(data.RoleID) == discord.Snowflake(guild.GuildID) {
+		return ErrorResponseData(errors.New("cannot use @everyone as the registered role"))
+	}
+
+	body := strings.TrimSpace(data.Message)
+	messages := guild.Messages
+	if label := strings.TrimSpace(data.ButtonLabel); label != "" {
+		messages.RegisterButtonLabel = label
+	}
+
+	channelID := guild.ChannelID
+	if data.ChannelID.IsValid() {
+		channelID = data.ChannelID
+	}
+
+	var msg strings.Builder
+
+	panelChanged := body != "" || messages != guild.Messages || channelID != guild.ChannelID
+	if panelChanged {
+		oldID := guild.RegisterMessageID
+		repost := channelID != guild.ChannelID || !oldID.IsValid()
+
+		if !repost {
+			err := h.editRegisterPanel(guild, body, messages)
+			switch {
+			case err == nil:
+				msg.WriteString("The register message has been updated.\n")
+			case isNotFound(err):
+				// Someone deleted the message, so post a new one.
+				repost = true
+				oldID = 0
+			default:
+				h.LogErr(guild.GuildID, err)
+				return InternalErrorResponseData()
+			}
+		}
+
+		if repost {
+			if body == "" && oldID.IsValid() {
+				old, err := h.s.Message(guild.ChannelID, oldID)
+				switch {
+				case err == nil:
+					body = old.Content
+				case isNotFound(err):
+					oldID = 0
+				default:
+					h.LogErr(guild.GuildID, errors.Wrap(err, "cannot fetch register message"))
+					return InternalErrorResponseData()
+				}
+			}
+
+			if body == "" {
+				return ErrorResponseData(errors.New(
+					"the current register message cannot be found; give a message to post a new one"))
+			}
+
+			newMsg, err := h.s.SendMessageComplex(channelID, api.SendMessageData{
+				Content:    body,
+				Components: registerPanelComponents(messages),
+			})
+			if err != nil {
+				return ErrorResponseData(errors.Wrap(err, "cannot send register message"))
+			}
+
+			if err := h.store.GuildSetRegisterMessage(guild.GuildID, channelID, newMsg.ID); err != nil {
+				h.s.DeleteMessage(channelID, newMsg.ID, "cannot save register message, check error")
+				h.LogErr(guild.GuildID, errors.Wrap(err, "cannot set register message"))
+				return InternalErrorResponseData()
+			}
+
+			fmt.Fprintf(&msg, "The register message has been posted in %s.", channelID.Mention())
+			if oldID.IsValid() {
+				if err := h.s.DeleteMessage(guild.ChannelID, oldID, "register message moved"); err != nil && !isNotFound(err) {
+					h.LogErr(guild.GuildID, errors.Wrap(err, "cannot delete old register message"))
+					msg.WriteString(" The old one could not be deleted, so delete it yourself.")
+				}
+			} else {
+				msg.WriteString(" Delete the old one yourself if it's still there.")
+			}
+			msg.WriteString("\n")
+		}
+
+		if messages != guild.Messages {
+			if err := h.store.GuildSetMessages(guild.GuildID, guild.RegisteredMessage, messages); err != nil {
+				h.LogErr(guild.GuildID, errors.Wrap(err, "cannot set guild messages"))
+				return InternalErrorResponseData()
+			}
+		}
+	}
+
+	roleID := guild.RoleID
+	if data.RoleID.IsValid() && data.RoleID != guild.RoleID {
+		if err := h.store.GuildSetRole(guild.GuildID, data.RoleID); err != nil {
+			h.LogErr(guild.GuildID, errors.Wrap(err, "cannot set registered role"))
+			return InternalErrorResponseData()
+		}
+		roleID = data.RoleID
+
+		fmt.Fprintf(&msg, ""+
+			"New members now get %s. Members who already registered keep %s; "+
+			"use `/registered-member migrate-role` to move them as well.\n",
+			roleID.Mention(), guild.RoleID.Mention())
+	}
+
+	if panelChanged || roleID != guild.RoleID {
+		h.auditSettings(guild, cmdData.Event.SenderID(), "/registration-settings panel", msg.String())
+	}
+
+	if msg.Len() == 0 {
+		fmt.Fprintf(&msg, "The register message is in %s", channelID.Mention())
+		if guild.RegisterMessageID.IsValid() {
+			fmt.Fprintf(&msg, " (https://discord.com/channels/%d/%d/%d)",
+				guild.GuildID, channelID, guild.RegisterMessageID)
+		}
+		fmt.Fprintf(&msg, ", and registered members get %s.", roleID.Mention())
+	}
+
+	return &api.InteractionResponseData{
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString(msg.String()),
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+// formFieldsList formats the given form fields as a Markdown list.
+func formFieldsList(fields []acmregister.FormField) string {
+	if len(fields) == 0 {
+		return "The registration form has no extra fields."
+	}
+
+	var list strings.Builder
+	for _, field := range fields {
+		fmt.Fprintf(&list, "- `%s`: %s", field.Name, field.Label)
+
+		var rules []string
+		if field.Required {
+			rules = append(rules, "required")
+		}
+		if field.MinLength > 0 || field.MaxLength > 0 {
+			min, max := field.LengthLimits()
+			rules = append(rules, fmt.Sprintf("%d to %d characters", min, max))
+		}
+		if field.Pattern != "" {
+			rules = append(rules, fmt.Sprintf("matches `%s`", field.Pattern))
+		}
+		if len(rules) > 0 {
+			fmt.Fprintf(&list, " (%s)", strings.Join(rules, ", "))
+		}
+
+		list.WriteString("\n")
+	}
+
+	return list.String()
+}
+
+func (h *Handler) cmdSettingsFields(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	return &api.InteractionResponseData{
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString(truncate(formFieldsList(guild.FormFields), maxContent)),
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+func (h *Handler) cmdSettingsAddField(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	var data struct {
+		Name      string `discord:"name"`
+		Label     string `discord:"label"`
+		Required  bool   `discord:"required?"`
+		MinLength int    `discord:"min-length?"`
+		MaxLength int    `discord:"max-length?"`
+		Pattern   string `discord:"pattern?"`
+	}
+
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	field := acmregister.FormField{
+		Name:      strings.ToLower(strings.TrimSpace(data.Name)),
+		Label:     strings.TrimSpace(data.Label),
+		Required:  data.Required,
+		MinLength: data.MinLength,
+		MaxLength: data.MaxLength,
+		Pattern:   data.Pattern,
+	}
+
+	if err := field.Validate(); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	// Both exports put custom fields next to their own columns.
+	allColumns := participantColumns{Pronouns: true, Nickname: true, Registered: true}.header()
+	for _, column := range append(allColumns, rosterColumns...) {
+		if field.Name == column {
+			return ErrorResponseData(fmt.Errorf("field name %q is already used by the built-in fields", field.Name))
+		}
+	}
+
+	fields := append([]acmregister.FormField(nil), guild.FormFields...)
+
+	replaced := false
+	for i, existing := range fields {
+		if existing.Name == field.Name {
+			fields[i] = field
+			replaced = true
+			break
+		}
+	}
+
+	if !replaced {
+		if len(fields) >= acmregister.MaxFormFields {
+			return ErrorResponseData(fmt.Errorf(
+				"the registration form can have at most %d extra fields", acmregister.MaxFormFields))
+		}
+		fields = append(fields, field)
+	}
+
+	if err := h.store.GuildSetFormFields(guild.GuildID, fields); err != nil {
+		h.LogErr(guild.GuildID, errors.Wrap(err, "cannot set form fields"))
+		return InternalErrorResponseData()
+	}
+
+	msg := "Field added! "
+	if replaced {
+		msg = "Field changed! "
+	}
+	msg += "The registration form now has:\n" + formFieldsList(fields)
+
+	h.auditSettings(guild, cmdData.Event.SenderID(), "/registration-settings add-field", msg)
+
+	return &api.InteractionResponseData{
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString(truncate(msg, maxContent)),
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+func (h *Handler) cmdSettingsRemoveField(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	var data struct {
+		Name string `discord:"name"`
+	}
+
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	fields := make([]acmregister.FormField, 0, len(guild.FormFields))
+	for _, field := range guild.FormFields {
+		if field.Name != data.Name {
+			fields = append(fields, field)
+		}
+	}
+
+	if len(fields) == len(guild.FormFields) {
+		return ErrorResponseData(fmt.Errorf("unknown field %q", data.Name))
+	}
+
+	if err := h.store.GuildSetFormFields(guild.GuildID, fields); err != nil {
+		h.LogErr(guild.GuildID, errors.Wrap(err, "cannot set form fields"))
+		return InternalErrorResponseData()
+	}
+
+	msg := "" +
+		"Field removed! Registered members keep their answers to it. " +
+		"The registration form now has:\n" + formFieldsList(fields)
+
+	h.auditSettings(guild, cmdData.Event.SenderID(), "/registration-settings remove-field", msg)
+
+	return &api.InteractionResponseData{
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString(truncate(msg, maxContent)),
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+func (h *Handler) acSettingsRemoveField(ctx context.Context, acData cmdroute.AutocompleteData) api.AutocompleteChoices {
+	switch option := acData.Options.Focused(); option.Name {
+	case "name":
+		guild, err := h.store.GuildInfo(acData.Event.GuildID)
+		if err != nil {
+			return nil
+		}
+
+		query := strings.ToLower(option.String())
+
+		var choices api.AutocompleteStringChoices
+		for _, field := range guild.FormFields {
+			if !strings.Contains(field.Name, query) && !strings.Contains(strings.ToLower(field.Label), query) {
+				continue
+			}
+			choices = append(choices, discord.StringChoice{
+				Name:  truncate(fmt.Sprintf("%s (%s)", field.Label, field.Name), 100),
+				Value: field.Name,
+			})
+		}
+
+		return choices
+	default:
+		return nil
+	}
+}
+
+// sampleMember is the member used to preview nickname templates when the
+// admin isn't registered themselves.
+var sampleMember = acmregister.MemberMetadata{
+	Email:     "ferris@example.com",
+	FirstName: "Ferris",
+	LastName:  "Crab",
+	Pronouns:  acmregister.TheyThem,
+}
+
+func (h *Handler) cmdSettingsNickname(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	var data struct {
+		Template string      `discord:"template?"`
+		Disable  option.Bool `discord:"disable?"`
+		Reset    bool        `discord:"reset?"`
+	}
+
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	settings := guild.Nickname
+	if data.Reset {
+		settings = acmregister.NicknameSettings{}
+	}
+
+	if template := strings.TrimSpace(data.Template); template != "" {
+		if _, err := acmregister.ParseNicknameTemplate(template); err != nil {
+			return ErrorResponseData(err)
+		}
+		settings.Template = template
+		settings.Disabled = false
+	}
+
+	if data.Disable != nil {
+		settings.Disabled = *data.Disable
+	}
+
+	changed := settings != guild.Nickname
+	if changed {
+		if err := h.store.GuildSetNickname(guild.GuildID, settings); err != nil {
+			h.LogErr(guild.GuildID, errors.Wrap(err, "cannot set nickname settings"))
+			return InternalErrorResponseData()
+		}
+	}
+
+	var msg strings.Builder
+	if changed {
+		msg.WriteString("Nickname settings updated! ")
+	}
+
+	if settings.Disabled {
+		msg.WriteString("Registered members aren't nicknamed.")
+	} else {
+		template := settings.Template
+		if template == "" {
+			template = acmregister.DefaultNicknameTemplate + " *(default)*"
+		}
+
+		preview := sampleMember
+		if metadata, err := h.store.MemberInfo(guild.GuildID, cmdData.Event.SenderID()); err == nil {
+			preview = *metadata
+		}
+		nick, _ := settings.Nickname(preview)
+
+		fmt.Fprintf(&msg, ""+
+			"Registered members are nicknamed using `%s`.\n"+
+			"For example, **%s** is nicknamed **%s**.",
+			template, preview.Name(), nick)
+	}
+
+	if changed {
+		msg.WriteString("\nExisting members keep their nicknames until they're reset with `/registered-member reset-name`.")
+		h.auditSettings(guild, cmdData.Event.SenderID(), "/registration-settings nickname", msg.String())
+	}
+
+	return &api.InteractionResponseData{
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString(msg.String()),
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+func (h *Handler) cmdSettingsApproval(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	var data struct {
+		ChannelID discord.ChannelID `discord:"channel?"`
+	}
+
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	if err := h.store.GuildSetApprovalChannel(guild.GuildID, data.ChannelID); err != nil {
+		h.LogErr(guild.GuildID, errors.Wrap(err, "cannot set approval channel"))
+		return InternalErrorResponseData()
+	}
+
+	var msg string
+	switch {
+	case data.ChannelID.IsValid():
+		msg = "Registrations now need to be approved in " + data.ChannelID.Mention() + "."
+	case guild.ApprovalChannelID.IsValid():
+		msg = "Registrations no longer need approval. " +
+			"Registrations already waiting in " + guild.ApprovalChannelID.Mention() +
+			" can still be approved or denied there."
+	default:
+		msg = "Registrations don't need approval."
+	}
+
+	if data.ChannelID != guild.ApprovalChannelID {
+		h.auditSettings(guild, cmdData.Event.SenderID(), "/registration-settings approval", msg)
+	}
+
+	return &api.InteractionResponseData{
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString(msg),
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
+
+func (h *Handler) cmdSettingsAudit(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	var data struct {
+		ChannelID discord.ChannelID `discord:"channel?"`
+	}
+
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	if err := h.store.GuildSetAuditChannel(guild.GuildID, data.ChannelID); err != nil {
+		h.LogErr(guild.GuildID, errors.Wrap(err, "cannot set audit channel"))
+		return InternalErrorResponseData()
+	}
+
+	var msg string
+	switch {
+	case data.ChannelID.IsValid():
+		msg = "Changes to the registration are now logged in " + data.ChannelID.Mention() + "."
+	case guild.AuditChannelID.IsValid():
+		msg = "Changes to the registration are no longer logged."
+	default:
+		msg = "Changes to the registration aren't logged."
+	}
+
+	if data.ChannelID != guild.AuditChannelID {
+		// Log to the new channel, or to the old one one last time if logging
+		// is being turned off.
+		logged := *guild
+		if data.ChannelID.IsValid() {
+			logged.AuditChannelID = data.ChannelID
+		}
+		h.auditSettings(&logged, cmdData.Event.SenderID(), "/registration-settings audit", msg)
+	}
+
+	return &api.InteractionResponseData{
+		Flags:           discord.EphemeralMessage,
+		Content:         option.NewNullableString(msg),
+		AllowedMentions: &api.AllowedMentions{},
+	}
+}
@@ -0,0 +1,219 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/acmregister/acmregister"
+	"github.com/diamondburned/acmregister/acmregister/logger"
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/api/cmdroute"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/pkg/errors"
+)
+
+const (
+	// statsDefaultWeeks is the number of weeks shown by /registration-stats
+	// by default.
+	statsDefaultWeeks = 8
+	// statsMaxWeeks is the maximum number of weeks that /registration-stats
+	// can show. It keeps the chart within the length limit of an embed field.
+	statsMaxWeeks = 26
+	// statsBarWidth is the width of the longest bar in the weekly chart.
+	statsBarWidth = 16
+	// statsMaxEmailHosts is the maximum number of email hosts shown when the
+	// bot doesn't restrict them.
+	statsMaxEmailHosts = 10
+)
+
+func (h *Handler) cmdRegistrationStats(ctx context.Context, cmdData cmdroute.CommandData) *api.InteractionResponseData {
+	guild, err := h.store.GuildInfo(cmdData.Event.GuildID)
+	if err != nil {
+		logger := logger.FromContext(h.ctx)
+		logger.Println("ignoring guild", cmdData.Event.GuildID, "reason:", err)
+		return nil
+	}
+
+	if err := h.authorizeAdmin(cmdData.Event); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	var data struct {
+		Weeks int `discord:"weeks?"`
+	}
+
+	if err := cmdData.Options.Unmarshal(&data); err != nil {
+		return ErrorResponseData(err)
+	}
+
+	if data.Weeks < 1 || data.Weeks > statsMaxWeeks {
+		data.Weeks = statsDefaultWeeks
+	}
+
+	since := statsSince(time.Now(), data.Weeks)
+
+	stats, err := h.store.RegistrationStats(guild.GuildID, since)
+	if err != nil {
+		h.PrivateWarning(cmdData.Event, errors.Wrap(err, "cannot get registration stats"))
+		return InternalErrorResponseData()
+	}
+
+	fields := []discord.EmbedField{
+		{Name: "Members", Value: fmt.Sprint(stats.Members), Inline: true},
+		{Name: "Awaiting Verification", Value: fmt.Sprint(stats.PendingSubmissions), Inline: true},
+	}
+	if guild.ApprovalChannelID.IsValid() || stats.PendingApprovals > 0 {
+		fields = append(fields, discord.EmbedField{
+			Name: "Awaiting Approval", Value: fmt.Sprint(stats.PendingApprovals), Inline: true,
+		})
+	}
+	if h.opts.PINStore != nil {
+		pinRate := "*no attempts*"
+		if stats.PINAttempts > 0 {
+			pinRate = fmt.Sprintf(
+				"%.0f%% (%d of %d)",
+				float64(stats.PINSuccesses)/float64(stats.PINAttempts)*100,
+				stats.PINSuccesses, stats.PINAttempts,
+			)
+		}
+		fields = append(fields, discord.EmbedField{
+			Name: "PIN Success Rate", Value: pinRate, Inline: true,
+		})
+	}
+	fields = append(fields,
+		discord.EmbedField{Name: "Registrations per Week", Value: weeklyChart(since, stats.Weekly)},
+		discord.EmbedField{Name: "Email Hosts", Value: h.emailHostCounts(stats.EmailHosts), Inline: true},
+		discord.EmbedField{Name: "Pronouns", Value: pronounCounts(stats.Pronouns), Inline: true},
+	)
+
+	return &api.InteractionResponseData{
+		Flags: discord.EphemeralMessage,
+		Embeds: &[]discord.Embed{{
+			Title:       "Registration Statistics",
+			Description: fmt.Sprintf("Weekly registrations and PIN attempts are counted since <t:%d:D>.", since.Unix()),
+			Fields:      fields,
+		}},
+	}
+}
+
+// statsSince returns the start of the first week to show, such that the last
+// of the given number of weeks is the current one. Weeks start on Monday in
+// UTC.
+func statsSince(now time.Time, weeks int) time.Time {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	return monday.AddDate(0, 0, -7*(weeks-1))
+}
+
+// weeklyChart renders the weekly registrations as a bar chart in a code
+// block.
+func weeklyChart(since time.Time, weekly []int) string {
+	var max int
+	for _, n := range weekly {
+		if n > max {
+			max = n
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("```\n")
+	for i, n := range weekly {
+		var bar int
+		if max > 0 {
+			bar = (n*statsBarWidth + max - 1) / max
+		}
+		week := since.AddDate(0, 0, 7*i)
+		fmt.Fprintf(&b, "%s %-*s %d\n", week.Format("Jan 02"), statsBarWidth, strings.Repeat("█", bar), n)
+	}
+	b.WriteString("```")
+
+	return b.String()
+}
+
+type statsCount struct {
+	name  string
+	count int
+}
+
+// sortedCounts returns the counts from the most to the least common.
+func sortedCounts(counts map[string]int) []statsCount {
+	sorted := make([]statsCount, 0, len(counts))
+	for name, count := range counts {
+		sorted = append(sorted, statsCount{name, count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].count != sorted[j].count {
+			return sorted[i].count > sorted[j].count
+		}
+		return sorted[i].name < sorted[j].name
+	})
+	return sorted
+}
+
+func formatCounts(counts []statsCount) string {
+	if len(counts) == 0 {
+		return "*none*"
+	}
+
+	var b strings.Builder
+	for _, c := range counts {
+		fmt.Fprintf(&b, "%s: **%d**\n", c.name, c.count)
+	}
+	return b.String()
+}
+
+// emailHostCounts lists the number of members by email host. If the bot only
+// allows certain hosts, then each of them is listed and the rest, such as
+// members registered by admins, are counted together.
+func (h *Handler) emailHostCounts(hosts map[string]int) string {
+	counts := make(map[string]int, len(hosts))
+
+	if len(h.opts.EmailHosts) > 0 {
+		for _, host := range h.opts.EmailHosts {
+			counts[strings.ToLower(host)] = 0
+		}
+		for host, n := range hosts {
+			if _, ok := counts[host]; ok {
+				counts[host] += n
+			} else {
+				counts["*other*"] += n
+			}
+		}
+		return formatCounts(sortedCounts(counts))
+	}
+
+	for host, n := range hosts {
+		if host == "" {
+			host = "*none*"
+		}
+		counts[host] += n
+	}
+
+	sorted := sortedCounts(counts)
+	if len(sorted) > statsMaxEmailHosts {
+		var others int
+		for _, c := range sorted[statsMaxEmailHosts:] {
+			others += c.count
+		}
+		sorted = append(sorted[:statsMaxEmailHosts], statsCount{"*other*", others})
+	}
+
+	return formatCounts(sorted)
+}
+
+// pronounCounts lists the number of members by pronouns.
+func pronounCounts(pronouns map[acmregister.Pronouns]int) string {
+	counts := make(map[string]int, len(pronouns))
+	for p, n := range pronouns {
+		text := pronounsText(p)
+		if text == "" {
+			text = "*not given*"
+		}
+		counts[text] += n
+	}
+	return formatCounts(sortedCounts(counts))
+}
@@ -3,8 +3,10 @@ package bot
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/diamondburned/acmregister/acmregister"
@@ -20,14 +22,14 @@ import (
 )
 
 // TODO: if member is already registered, just give them the role
-// TODO: command to migrate roles
 
 type Opts struct {
-	Store          acmregister.Store
-	PINStore       verifyemail.PINStore           // optional
-	EmailHosts     acmregister.EmailHostsVerifier // optional
-	EmailVerifier  acmregister.EmailVerifier      // optional
-	EmailScheduler ConfirmationEmailScheduler     // optional
+	Store           acmregister.Store
+	PINStore        verifyemail.PINStore           // optional
+	EmailHosts      acmregister.EmailHostsVerifier // optional
+	EmailVerifier   acmregister.EmailVerifier      // optional
+	EmailScheduler  ConfirmationEmailScheduler     // optional
+	DeparturePolicy DeparturePolicy                // optional, default DepartureKeep
 }
 
 func (o Opts) verifyEmail(ctx context.Context, email acmregister.Email) error {
@@ -61,29 +63,86 @@ func NewHandler(s *state.State, opts Opts) *Handler {
 
 	h.router.AddFunc("init-register", h.cmdInitRegister)
 	h.router.AddFunc("clear-registration", h.cmdClearRegistration)
+	h.router.AddFunc("registration-stats", h.cmdRegistrationStats)
 
 	h.router.Sub("registered-member", func(r *cmdroute.Router) {
-		r.Use(h.checkAdminAuthorized)
+		r.Use(
+			commandsOnly(cmdroute.Deferrable(s, cmdroute.DeferOpts{Flags: discord.EphemeralMessage})),
+			h.checkAdminAuthorized,
+		)
 		r.AddFunc("query", h.cmdMemberQuery)
+		r.AddFunc("list", h.cmdMemberList)
+		r.AddFunc("search", h.cmdMemberSearch)
+		r.AddAutocompleterFunc("search", h.acMemberSearch)
+		r.AddFunc("export", h.cmdMemberExport)
+		r.AddFunc("import", h.cmdMemberImport)
+		r.AddFunc("register", h.cmdMemberRegister)
 		r.AddFunc("unregister", h.cmdMemberUnregister)
 		r.AddFunc("reset-name", h.cmdMemberResetName)
+		r.AddFunc("migrate-role", h.cmdMemberMigrateRole)
+		r.AddFunc("reconcile", h.cmdMemberReconcile)
 		r.AddFunc("set-allowed-role", h.cmdMemberSetAllowedRole)
 	})
 
+	h.router.Sub("registration-settings", func(r *cmdroute.Router) {
+		r.Use(
+			commandsOnly(cmdroute.Deferrable(s, cmdroute.DeferOpts{Flags: discord.EphemeralMessage})),
+			h.checkAdminAuthorized,
+		)
+		r.AddFunc("messages", h.cmdSettingsMessages)
+		r.AddFunc("panel", h.cmdSettingsPanel)
+		r.AddFunc("nickname", h.cmdSettingsNickname)
+		r.AddFunc("approval", h.cmdSettingsApproval)
+		r.AddFunc("audit", h.cmdSettingsAudit)
+		r.AddFunc("fields", h.cmdSettingsFields)
+		r.AddFunc("add-field", h.cmdSettingsAddField)
+		r.AddFunc("remove-field", h.cmdSettingsRemoveField)
+		r.AddAutocompleterFunc("remove-field", h.acSettingsRemoveField)
+	})
+
+	h.router.Sub("my-registration", func(r *cmdroute.Router) {
+		r.Use(modalResponses)
+		r.AddFunc("view", h.cmdMyRegistrationView)
+		r.AddFunc("edit", h.cmdMyRegistrationEdit)
+		r.AddFunc("reset-nickname", h.cmdMyRegistrationResetNickname)
+		r.AddFunc("leave", h.cmdMyRegistrationLeave)
+	})
+
 	h.router.Sub("event-registration", func(r *cmdroute.Router) {
-		r.Use(cmdroute.Deferrable(s, cmdroute.DeferOpts{}))
+		// Exports are only shown to whoever asked for them, but the Check In
+		// button is for everyone.
+		r.Use(
+			subcommandsOnly(cmdroute.Deferrable(s, cmdroute.DeferOpts{Flags: discord.EphemeralMessage}),
+				"export-members", "export-range"),
+			subcommandsOnly(cmdroute.Deferrable(s, cmdroute.DeferOpts{}),
+				"check-in"),
+		)
 		r.AddFunc("export-members", h.cmdEventExportMembers)
-		r.AddAutocompleterFunc("export-members", h.acEventExportMembers)
+		r.AddAutocompleterFunc("export-members", h.acEvents)
+		r.AddFunc("export-range", h.cmdEventExportRange)
+		r.AddFunc("check-in", h.cmdEventCheckIn)
+		r.AddAutocompleterFunc("check-in", h.acEvents)
 	})
 
 	return h
 }
 
 func (h *Handler) Intents() gateway.Intents {
-	return 0 |
+	intents := 0 |
 		gateway.IntentGuilds |
 		gateway.IntentDirectMessages |
 		gateway.IntentGuildScheduledEvents
+
+	// Members leaving and rejoining are only needed for the departure policy,
+	// and they need the privileged Server Members Intent, which has to be
+	// enabled in the Developer Portal. Only ask for it if the policy was
+	// chosen, so that the bot still connects without it.
+	switch h.opts.DeparturePolicy {
+	case DepartureMark, DepartureDelete:
+		intents |= gateway.IntentGuildMembers
+	}
+
+	return intents
 }
 
 func (h *Handler) HandleInteraction(ev *discord.InteractionEvent) *api.InteractionResponse {
@@ -108,22 +167,52 @@ func (h *Handler) HandleInteraction(ev *discord.InteractionEvent) *api.Interacti
 		return h.router.HandleInteraction(ev)
 
 	case *discord.ButtonInteraction:
-		switch data.CustomID {
+		// Some buttons carry their arguments in their custom IDs, delimited by
+		// a colon.
+		id, arg, _ := strings.Cut(string(data.CustomID), ":")
+
+		switch id {
 		case "register":
 			return h.buttonRegister(ev)
 		case "verify-pin":
 			return h.buttonVerifyPIN(ev)
+		case "list-members":
+			return h.buttonListMembers(ev, arg)
+		case "clear-registration":
+			return h.buttonClearRegistration(ev, arg)
+		case "register-continue":
+			return h.buttonRegisterContinue(ev, newRegistrationForm, arg)
+		case "edit-continue":
+			return h.buttonRegisterContinue(ev, editRegistrationForm, arg)
+		case "leave-registration":
+			return h.buttonLeaveRegistration(ev, arg)
+		case "approve-registration":
+			return h.buttonApproveRegistration(ev, arg)
+		case "deny-registration":
+			return h.buttonDenyRegistration(ev, arg)
+		case "check-in":
+			return h.buttonCheckIn(ev, arg)
 		default:
 			logger := logger.FromContext(h.ctx)
 			logger.Printf("not handling unknown button %q", data.CustomID)
 		}
 
 	case *discord.ModalInteraction:
-		switch data.CustomID {
+		id, arg, _ := strings.Cut(string(data.CustomID), ":")
+
+		switch id {
 		case "register-response":
 			return h.modalRegisterResponse(ev, data)
+		case "register-fields":
+			return h.modalRegisterFields(ev, data, newRegistrationForm, arg)
+		case "edit-response":
+			return h.modalEditResponse(ev, data)
+		case "edit-fields":
+			return h.modalRegisterFields(ev, data, editRegistrationForm, arg)
 		case "verify-pin":
 			return h.modalVerifyPIN(ev, data)
+		case "deny-registration":
+			return h.modalDenyRegistration(ev, data, arg)
 		default:
 			logger := logger.FromContext(h.ctx)
 			logger.Printf("not handling unknown modal %q", data.CustomID)
@@ -141,6 +230,72 @@ func (h *Handler) HandleInteraction(ev *discord.InteractionEvent) *api.Interacti
 }
 
 func (h *Handler) checkAdminAuthorized(next cmdroute.InteractionHandler) cmdroute.InteractionHandler {
+	return cmdroute.InteractionHandlerFunc(func(ctx context.Context, ev *discord.InteractionEvent) *api.InteractionResponse {
+		if err := h.authorizeAdmin(ev); err != nil {
+			// Autocompletion can't show errors, so just don't suggest
+			// anything.
+			if _, ok := ev.Data.(*discord.AutocompleteInteraction); ok {
+				return &api.InteractionResponse{
+					Type: api.AutocompleteResult,
+					Data: &api.InteractionResponseData{
+						Choices: api.AutocompleteStringChoices{},
+					},
+				}
+			}
+			return ErrorResponse(err)
+		}
+		return next.HandleInteraction(ctx, ev)
+	})
+}
+
+// commandsOnly applies the middleware to commands only. Autocompletion must
+// be answered directly, so it can't be deferred or given message flags.
+func commandsOnly(mw cmdroute.Middleware) cmdroute.Middleware {
+	return func(next cmdroute.InteractionHandler) cmdroute.InteractionHandler {
+		wrapped := mw(next)
+		return cmdroute.InteractionHandlerFunc(func(ctx context.Context, ev *discord.InteractionEvent) *api.InteractionResponse {
+			if _, ok := ev.Data.(*discord.CommandInteraction); ok {
+				return wrapped.HandleInteraction(ctx, ev)
+			}
+			return next.HandleInteraction(ctx, ev)
+		})
+	}
+}
+
+// modalResponses turns command responses that have a title into modals, since
+// command handlers can only reply with messages.
+func modalResponses(next cmdroute.InteractionHandler) cmdroute.InteractionHandler {
+	return cmdroute.InteractionHandlerFunc(func(ctx context.Context, ev *discord.InteractionEvent) *api.InteractionResponse {
+		resp := next.HandleInteraction(ctx, ev)
+		if resp != nil && resp.Type == api.MessageInteractionWithSource && resp.Data != nil && resp.Data.Title != nil {
+			resp.Type = api.ModalResponse
+		}
+		return resp
+	})
+}
+
+// subcommandsOnly is like commandsOnly, except the middleware is only applied
+// to the subcommands of the given names.
+func subcommandsOnly(mw cmdroute.Middleware, names ...string) cmdroute.Middleware {
+	return func(next cmdroute.InteractionHandler) cmdroute.InteractionHandler {
+		wrapped := mw(next)
+		return cmdroute.InteractionHandlerFunc(func(ctx context.Context, ev *discord.InteractionEvent) *api.InteractionResponse {
+			if data, ok := ev.Data.(*discord.CommandInteraction); ok && len(data.Options) > 0 {
+				for _, name := range names {
+					if data.Options[0].Name == name {
+						return wrapped.HandleInteraction(ctx, ev)
+					}
+				}
+			}
+			return next.HandleInteraction(ctx, ev)
+		})
+	}
+}
+
+// authorizeAdmin returns an error if the sender of the given event is not
+// allowed to use admin-only interactions. Interactions that don't go through
+// the command router, such as buttons, should call this directly.
+func (h *Handler) authorizeAdmin(ev *discord.InteractionEvent) error {
 	checkAdminRoleID := func(ev *discord.InteractionEvent) (bool, error) {
 		info, _ := h.store.GuildInfo(ev.GuildID)
 		if info == nil || !info.AdminRoleID.IsValid() {
@@ -179,19 +334,18 @@ func (h *Handler) checkAdminAuthorized(next cmdroute.InteractionHandler) cmdrout
 		checkAdminPermission,
 	}
 
-	return cmdroute.InteractionHandlerFunc(func(ctx context.Context, ev *discord.InteractionEvent) *api.InteractionResponse {
-		for _, check := range checks {
-			ok, err := check(ev)
-			if err != nil {
-				h.PrivateWarning(ev, fmt.Errorf("cannot check admin role: %v", err))
-				continue
-			}
-			if ok {
-				return next.HandleInteraction(ctx, ev)
-			}
+	for _, check := range checks {
+		ok, err := check(ev)
+		if err != nil {
+			h.PrivateWarning(ev, fmt.Errorf("cannot check admin role: %v", err))
+			continue
 		}
-		return ErrorResponse(fmt.Errorf("you don't have permission to this command; contact the guild owner"))
-	})
+		if ok {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("you don't have permission to this command; contact the guild owner")
 }
 
 // Client wraps around state.State for some common functionalities.
@@ -213,8 +367,9 @@ func (c *Client) Context() context.Context {
 	return c.ctx
 }
 
-// FollowUp sends a followup response.
-func (c *Client) FollowUp(ev *discord.InteractionEvent, data *api.InteractionResponseData) {
+// FollowUp sends a followup response. The error is logged as well as
+// returned.
+func (c *Client) FollowUp(ev *discord.InteractionEvent, data *api.InteractionResponseData) error {
 	// Try for a few seconds.
 	ctx, cancel := context.WithTimeout(c.ctx, 3*time.Second)
 	defer cancel()
@@ -228,6 +383,13 @@ func (c *Client) FollowUp(ev *discord.InteractionEvent, data *api.InteractionRes
 	// The 3s is not arbitrary; it is the maximum time that an interaction can
 	// be valid before it has to defer or respond.
 	for ctx.Err() == nil {
+		// Files are read by every attempt, so start them over.
+		for _, file := range data.Files {
+			if seeker, ok := file.Reader.(io.Seeker); ok {
+				seeker.Seek(0, io.SeekStart)
+			}
+		}
+
 		if _, err = s.FollowUpInteraction(ev.AppID, ev.Token, *data); err == nil {
 			break
 		}
@@ -239,6 +401,22 @@ func (c *Client) FollowUp(ev *discord.InteractionEvent, data *api.InteractionRes
 		err = errors.Wrap(err, "cannot follow-up to interaction")
 		c.LogErr(ev.GuildID, err)
 	}
+
+	return err
+}
+
+// DirectMessage sends a message to the user's DMs.
+func (c *Client) DirectMessage(userID discord.UserID, content string) error {
+	ch, err := c.s.CreatePrivateChannel(userID)
+	if err != nil {
+		return errors.Wrap(err, "cannot create DM channel")
+	}
+
+	_, err = c.s.SendMessageComplex(ch.ID, api.SendMessageData{
+		Content:         content,
+		AllowedMentions: &api.AllowedMentions{},
+	})
+	return errors.Wrap(err, "cannot send DM")
 }
 
 // PrivateWarning is like PrivateErr, except the user does not get a reply back
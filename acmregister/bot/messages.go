@@ -1,6 +1,9 @@
 package bot
 
-// TODO: change to commands to change these
+import "github.com/diamondburned/acmregister/acmregister"
+
+// Default messages. Guilds can change these with /registration-settings
+// messages.
 const (
 	registeredButtonLabel = "Register"
 	registeredMessage     = "You're all set!"
@@ -9,4 +12,49 @@ const (
 		"to complete the verification process. PIN codes are valid for 30 " +
 		"minutes."
 	verifyPINButtonLabel = "Verify"
+	verifyPINModalTitle  = "Verify your PIN code"
+)
+
+// Discord's limits on the lengths of messages.
+const (
+	maxButtonLabel = 80
+	maxModalTitle  = 45
+	maxInputLabel  = 45
+	maxContent     = 2000
 )
+
+// messagesWithDefaults returns msgs with its empty messages replaced by the
+// defaults.
+func messagesWithDefaults(msgs acmregister.GuildMessages) acmregister.GuildMessages {
+	if msgs.RegisterButtonLabel == "" {
+		msgs.RegisterButtonLabel = registeredButtonLabel
+	}
+	if msgs.VerifyPINMessage == "" {
+		msgs.VerifyPINMessage = verifyPINMessage
+	}
+	if msgs.VerifyPINButtonLabel == "" {
+		msgs.VerifyPINButtonLabel = verifyPINButtonLabel
+	}
+	if msgs.VerifyPINModalTitle == "" {
+		msgs.VerifyPINModalTitle = verifyPINModalTitle
+	}
+	return msgs
+}
+
+// guildRegisteredMessage returns the message to reply with once a member of
+// the guild is registered.
+func guildRegisteredMessage(guild *acmregister.KnownGuild) string {
+	if guild.RegisteredMessage == "" {
+		return registeredMessage
+	}
+	return guild.RegisteredMessage
+}
+
+// pronounsText returns the pronouns as shown to users. It is empty for hidden
+// pronouns.
+func pronounsText(p acmregister.Pronouns) string {
+	if p == acmregister.AnyPronouns {
+		return "any pronouns"
+	}
+	return string(p)
+}
@@ -30,6 +30,12 @@ func BotOpts(ctx context.Context) (Opts, error) {
 	case "postgresql":
 		store = stores.Must(stores.NewPostgreSQL(ctx, os.Getenv("POSTGRESQL_URL")))
 		logger.Println("using PostgreSQL")
+	case "sqlite":
+		store = stores.Must(stores.NewSQLite(ctx, os.Getenv("SQLITE_URL")))
+		logger.Println("using SQLite")
+	case "memory":
+		store = stores.NewMemory()
+		logger.Println("using in-memory store, all data will be lost on exit")
 	default:
 		logger.Fatalf("unknown $STORE_DRIVER %q", driver)
 	}
@@ -45,6 +51,14 @@ func BotOpts(ctx context.Context) (Opts, error) {
 		},
 	}
 
+	if policy := os.Getenv("MEMBER_DEPARTURE_POLICY"); policy != "" {
+		p, err := bot.ParseDeparturePolicy(policy)
+		if err != nil {
+			logger.Fatalln("invalid $MEMBER_DEPARTURE_POLICY:", err)
+		}
+		opts.DeparturePolicy = p
+	}
+
 	if shibbolethURL := os.Getenv("VERIFY_SHIBBOLETH_URL"); shibbolethURL != "" {
 		log.Println("enabling Shibboleth verifier")
 		opts.EmailVerifier = &verifyemail.ShibbolethVerifier{
@@ -0,0 +1,120 @@
+package acmregister
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// MaxFormFields is the maximum number of custom form fields that a guild can
+// have.
+const MaxFormFields = 10
+
+// FormFieldMaxLength is the maximum length of a form field's value. It is the
+// limit of Discord's text inputs.
+const FormFieldMaxLength = 4000
+
+// FormField describes an extra field in a guild's registration form. Values of
+// the field are stored in MemberMetadata.Extra under the field's name.
+type FormField struct {
+	// Name is the key of the field in MemberMetadata.Extra. It is also used as
+	// the column name when exporting.
+	Name string `json:"name"`
+	// Label is the label shown to the user.
+	Label string `json:"label"`
+	// Required is true if the field must be filled in.
+	Required bool `json:"required,omitempty"`
+	// MinLength is the minimum length of the value, if any.
+	MinLength int `json:"min_length,omitempty"`
+	// MaxLength is the maximum length of the value. It is FormFieldMaxLength
+	// if zero.
+	MaxLength int `json:"max_length,omitempty"`
+	// Pattern is the regular expression that the whole value must match, if
+	// any. Empty optional values are not matched.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+var formFieldNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// Validate checks that the field itself is valid.
+func (f FormField) Validate() error {
+	if !formFieldNameRe.MatchString(f.Name) {
+		return fmt.Errorf("invalid field name %q, must be lowercase letters, digits or underscores", f.Name)
+	}
+	for _, placeholder := range nicknamePlaceholders {
+		if f.Name == placeholder {
+			return fmt.Errorf("field name %q is already used by a nickname placeholder", f.Name)
+		}
+	}
+	if f.Label == "" {
+		return errors.New("field label cannot be empty")
+	}
+	if f.MinLength < 0 || f.MaxLength < 0 || f.MaxLength > FormFieldMaxLength {
+		return fmt.Errorf("field length must be between 0 and %d", FormFieldMaxLength)
+	}
+	if f.MaxLength > 0 && f.MinLength > f.MaxLength {
+		return errors.New("field minimum length cannot be over its maximum length")
+	}
+	if _, err := f.compilePattern(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// LengthLimits returns the minimum and maximum length of the field's value.
+func (f FormField) LengthLimits() (min, max int) {
+	max = f.MaxLength
+	if max == 0 {
+		max = FormFieldMaxLength
+	}
+	return f.MinLength, max
+}
+
+// ValidateValue checks that the given value is valid for the field.
+func (f FormField) ValidateValue(value string) error {
+	if value == "" {
+		if f.Required {
+			return fmt.Errorf("%s is required", f.Label)
+		}
+		return nil
+	}
+
+	min, max := f.LengthLimits()
+	if n := utf8.RuneCountInString(value); n < min || n > max {
+		return fmt.Errorf("%s must be between %d and %d characters long", f.Label, min, max)
+	}
+
+	re, err := f.compilePattern()
+	if err != nil {
+		return err
+	}
+	if re != nil && !re.MatchString(value) {
+		return fmt.Errorf("%s is not in the right format", f.Label)
+	}
+
+	return nil
+}
+
+func (f FormField) compilePattern() (*regexp.Regexp, error) {
+	if f.Pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(`^(?:` + f.Pattern + `)$`)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid pattern for field %s", f.Name)
+	}
+	return re, nil
+}
+
+// ValidateFormFields checks that the given extra metadata is valid for all of
+// the given fields.
+func ValidateFormFields(fields []FormField, extra map[string]string) error {
+	for _, field := range fields {
+		if err := field.ValidateValue(extra[field.Name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
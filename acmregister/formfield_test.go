@@ -0,0 +1,159 @@
+package acmregister
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormFieldValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		field FormField
+		valid bool
+	}{
+		{
+			name:  "valid",
+			field: FormField{Name: "grad_year", Label: "Graduation Year", MinLength: 4, MaxLength: 4, Pattern: `\d+`},
+			valid: true,
+		},
+		{
+			name:  "uppercase name",
+			field: FormField{Name: "Major", Label: "Major"},
+		},
+		{
+			name:  "name starting with a digit",
+			field: FormField{Name: "2nd_major", Label: "Second Major"},
+		},
+		{
+			name:  "name too long",
+			field: FormField{Name: "a" + strings.Repeat("b", 32), Label: "Long"},
+		},
+		{
+			name:  "empty label",
+			field: FormField{Name: "major"},
+		},
+		{
+			name:  "negative length",
+			field: FormField{Name: "major", Label: "Major", MinLength: -1},
+		},
+		{
+			name:  "length over Discord's limit",
+			field: FormField{Name: "major", Label: "Major", MaxLength: FormFieldMaxLength + 1},
+		},
+		{
+			name:  "minimum over maximum",
+			field: FormField{Name: "major", Label: "Major", MinLength: 10, MaxLength: 5},
+		},
+		{
+			name:  "minimum without maximum",
+			field: FormField{Name: "major", Label: "Major", MinLength: 10},
+			valid: true,
+		},
+		{
+			name:  "nickname placeholder name",
+			field: FormField{Name: "first_initial", Label: "Initial"},
+		},
+		{
+			name:  "invalid pattern",
+			field: FormField{Name: "major", Label: "Major", Pattern: `(`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.field.Validate()
+			if test.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.valid && err == nil {
+				t.Error("expected an error, got none")
+			}
+		})
+	}
+}
+
+func TestFormFieldValidateValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		field FormField
+		value string
+		valid bool
+	}{
+		{
+			name:  "empty optional value",
+			field: FormField{Name: "major", Label: "Major", MinLength: 2, Pattern: `[A-Z]+`},
+			value: "",
+			valid: true,
+		},
+		{
+			name:  "empty required value",
+			field: FormField{Name: "major", Label: "Major", Required: true},
+			value: "",
+		},
+		{
+			name:  "required value",
+			field: FormField{Name: "major", Label: "Major", Required: true},
+			value: "CS",
+			valid: true,
+		},
+		{
+			name:  "too short",
+			field: FormField{Name: "major", Label: "Major", MinLength: 3},
+			value: "CS",
+		},
+		{
+			name:  "too long",
+			field: FormField{Name: "major", Label: "Major", MaxLength: 3},
+			value: "CPSC",
+		},
+		{
+			name:  "over the default maximum",
+			field: FormField{Name: "major", Label: "Major"},
+			value: strings.Repeat("a", FormFieldMaxLength+1),
+		},
+		{
+			name:  "multi-byte value counted in runes",
+			field: FormField{Name: "name", Label: "Name", MinLength: 2, MaxLength: 2},
+			value: "世界", // 6 bytes
+			valid: true,
+		},
+		{
+			name:  "multi-byte value too long",
+			field: FormField{Name: "name", Label: "Name", MaxLength: 2},
+			value: "世界!",
+		},
+		{
+			name:  "matching pattern",
+			field: FormField{Name: "cwid", Label: "CWID", Pattern: `\d{9}`},
+			value: "123456789",
+			valid: true,
+		},
+		{
+			name:  "pattern matching only a prefix",
+			field: FormField{Name: "cwid", Label: "CWID", Pattern: `\d{9}`},
+			value: "123456789x",
+		},
+		{
+			name:  "pattern matching only a suffix",
+			field: FormField{Name: "cwid", Label: "CWID", Pattern: `\d{9}`},
+			value: "x123456789",
+		},
+		{
+			name:  "anchored alternation",
+			field: FormField{Name: "level", Label: "Level", Pattern: `junior|senior`},
+			value: "seniority",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.field.ValidateValue(test.value)
+			if test.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.valid && err == nil {
+				t.Error("expected an error, got none")
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package acmregister
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// MaxNicknameLength is the maximum length of a Discord nickname.
+const MaxNicknameLength = 32
+
+// DefaultNicknameTemplate is the nickname template used by guilds that haven't
+// set one.
+const DefaultNicknameTemplate = "{first}[ {last}][ ({pronouns})]"
+
+// NicknameSettings describes how a guild nicknames its registered members.
+type NicknameSettings struct {
+	// Template is the nickname template. DefaultNicknameTemplate is used if
+	// it's empty. See ParseNicknameTemplate.
+	Template string `json:"template,omitempty"`
+	// Disabled, if true, leaves the nicknames of registered members alone.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// NicknameTemplate is a parsed nickname template.
+type NicknameTemplate struct {
+	sections []nicknameSection
+}
+
+type nicknameSection struct {
+	tokens   []nicknameToken
+	optional bool
+}
+
+type nicknameToken struct {
+	text  string // literal text, used if field is empty
+	field string
+}
+
+var defaultNicknameTemplate = mustParseNicknameTemplate(DefaultNicknameTemplate)
+
+func mustParseNicknameTemplate(s string) *NicknameTemplate {
+	t, err := ParseNicknameTemplate(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// ParseNicknameTemplate parses a nickname template. Placeholders are written
+// in braces:
+//
+//   - {first} and {last} are the first and last names.
+//   - {first_initial} and {last_initial} are their first letters.
+//   - {name} is the full name.
+//   - {pronouns} is the pronouns, or "any pronouns".
+//   - {email_user} is the part of the email before the @.
+//   - Any other name is the value of the custom form field with that name.
+//
+// Text in square brackets is optional: it is left out if any placeholder in it
+// is empty, so "{first}[ ({pronouns})]" doesn't leave empty parentheses.
+func ParseNicknameTemplate(s string) (*NicknameTemplate, error) {
+	var t NicknameTemplate
+	var section nicknameSection
+	var text strings.Builder
+
+	flushText := func() {
+		if text.Len() > 0 {
+			section.tokens = append(section.tokens, nicknameToken{text: text.String()})
+			text.Reset()
+		}
+	}
+
+	flushSection := func() {
+		flushText()
+		if len(section.tokens) > 0 {
+			t.sections = append(t.sections, section)
+		}
+		section = nicknameSection{}
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end == -1 {
+				return nil, errors.New("unclosed { in nickname template")
+			}
+			field := s[i+1 : i+end]
+			if !formFieldNameRe.MatchString(field) {
+				return nil, fmt.Errorf("invalid placeholder {%s} in nickname template", field)
+			}
+			flushText()
+			section.tokens = append(section.tokens, nicknameToken{field: field})
+			i += end
+		case '}':
+			return nil, errors.New("unexpected } in nickname template")
+		case '[':
+			if section.optional {
+				return nil, errors.New("nested [ in nickname template")
+			}
+			flushSection()
+			section.optional = true
+		case ']':
+			if !section.optional {
+				return nil, errors.New("unexpected ] in nickname template")
+			}
+			flushSection()
+		default:
+			text.WriteByte(s[i])
+		}
+	}
+
+	if section.optional {
+		return nil, errors.New("unclosed [ in nickname template")
+	}
+	flushSection()
+
+	if len(t.sections) == 0 {
+		return nil, errors.New("nickname template is empty")
+	}
+
+	return &t, nil
+}
+
+// Format formats the nickname for the given member. The nickname always fits
+// in MaxNicknameLength: if it's too long, optional sections are left out
+// starting from the last one, then the nickname is cut off.
+func (t *NicknameTemplate) Format(m MemberMetadata) string {
+	values := make([]string, len(t.sections))
+	for i, section := range t.sections {
+		values[i] = section.format(m)
+	}
+
+	nick := joinNickname(values)
+	for i := len(values) - 1; i >= 0 && utf8.RuneCountInString(nick) > MaxNicknameLength; i-- {
+		if t.sections[i].optional && values[i] != "" {
+			values[i] = ""
+			nick = joinNickname(values)
+		}
+	}
+
+	if runes := []rune(nick); len(runes) > MaxNicknameLength {
+		nick = strings.TrimSpace(string(runes[:MaxNicknameLength-1])) + "…"
+	}
+
+	return nick
+}
+
+func (s nicknameSection) format(m MemberMetadata) string {
+	var b strings.Builder
+	for _, token := range s.tokens {
+		if token.field == "" {
+			b.WriteString(token.text)
+			continue
+		}
+		v := m.nicknameField(token.field)
+		if v == "" && s.optional {
+			return ""
+		}
+		b.WriteString(v)
+	}
+	return b.String()
+}
+
+// joinNickname joins the formatted sections, collapsing the whitespace left
+// behind by empty placeholders.
+func joinNickname(values []string) string {
+	return strings.Join(strings.Fields(strings.Join(values, "")), " ")
+}
+
+// nicknamePlaceholders are the placeholders handled by nicknameField itself.
+// Custom form fields can't have these names, since they would be shadowed.
+var nicknamePlaceholders = []string{
+	"first", "last", "first_initial", "last_initial", "name", "pronouns", "email_user",
+}
+
+func (m MemberMetadata) nicknameField(field string) string {
+	initial := func(s string) string {
+		r, _ := utf8.DecodeRuneInString(s)
+		if r == utf8.RuneError {
+			return ""
+		}
+		return string(r)
+	}
+
+	switch field {
+	case "first":
+		return m.FirstName
+	case "last":
+		return m.LastName
+	case "first_initial":
+		return initial(m.FirstName)
+	case "last_initial":
+		return initial(m.LastName)
+	case "name":
+		return m.Name()
+	case "pronouns":
+		switch m.Pronouns {
+		case AnyPronouns:
+			return "any pronouns"
+		default:
+			return string(m.Pronouns)
+		}
+	case "email_user":
+		return m.Email.Username()
+	default:
+		return m.Extra[field]
+	}
+}
+
+// Nickname returns the nickname for the member using the given settings. False
+// is returned if the settings disable nicknames.
+func (n NicknameSettings) Nickname(m MemberMetadata) (string, bool) {
+	if n.Disabled {
+		return "", false
+	}
+
+	t := defaultNicknameTemplate
+	if n.Template != "" {
+		var err error
+		t, err = ParseNicknameTemplate(n.Template)
+		if err != nil {
+			// Templates are checked before being saved, so this shouldn't
+			// happen.
+			t = defaultNicknameTemplate
+		}
+	}
+
+	return t.Format(m), true
+}
@@ -0,0 +1,157 @@
+package acmregister
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestParseNicknameTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		valid    bool
+	}{
+		{"{first}", true},
+		{DefaultNicknameTemplate, true},
+		{"{first_initial}. {last} | {major}", true},
+		{"[{pronouns}] {first}", true},
+		{"", false},
+		{"[]", false},
+		{"{first", false},
+		{"first}", false},
+		{"{}", false},
+		{"{First}", false},
+		{"{first name}", false},
+		{"{first}[ {last}", false},
+		{"{first}] {last}", false},
+		{"{first}[ [{last}]]", false},
+		{"{first}[ ({pronouns)}]", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.template, func(t *testing.T) {
+			_, err := ParseNicknameTemplate(test.template)
+			if test.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.valid && err == nil {
+				t.Error("expected an error, got none")
+			}
+		})
+	}
+}
+
+func TestNicknameTemplateFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		member   MemberMetadata
+		expected string
+	}{
+		{
+			name:     "all sections",
+			template: DefaultNicknameTemplate,
+			member:   MemberMetadata{FirstName: "Diamond", LastName: "Burned", Pronouns: SheHer},
+			expected: "Diamond Burned (she/her)",
+		},
+		{
+			name:     "empty optional placeholders",
+			template: DefaultNicknameTemplate,
+			member:   MemberMetadata{FirstName: "Diamond"},
+			expected: "Diamond",
+		},
+		{
+			name:     "any pronouns",
+			template: DefaultNicknameTemplate,
+			member:   MemberMetadata{FirstName: "Diamond", Pronouns: AnyPronouns},
+			expected: "Diamond (any pronouns)",
+		},
+		{
+			name:     "empty optional custom field",
+			template: "{first}[ | {major}]",
+			member:   MemberMetadata{FirstName: "Diamond"},
+			expected: "Diamond",
+		},
+		{
+			name:     "custom field",
+			template: "{first}[ | {major}]",
+			member:   MemberMetadata{FirstName: "Diamond", Extra: map[string]string{"major": "CS"}},
+			expected: "Diamond | CS",
+		},
+		{
+			name:     "empty required placeholder",
+			template: "{first} {last} {email_user}",
+			member:   MemberMetadata{FirstName: "Diamond", Email: "diamond@csu.fullerton.edu"},
+			expected: "Diamond diamond",
+		},
+		{
+			name:     "multi-byte initials",
+			template: "{first_initial}{last_initial}",
+			member:   MemberMetadata{FirstName: "Ánh", LastName: "Đặng"},
+			expected: "ÁĐ",
+		},
+		{
+			name:     "multi-byte names",
+			template: DefaultNicknameTemplate,
+			member:   MemberMetadata{FirstName: "世界", LastName: "你好", Pronouns: TheyThem},
+			expected: "世界 你好 (they/them)",
+		},
+		{
+			name:     "exactly the maximum length",
+			template: DefaultNicknameTemplate,
+			member:   MemberMetadata{FirstName: "Alexandria", LastName: "Montgomerys", Pronouns: SheHer},
+			expected: "Alexandria Montgomerys (she/her)",
+		},
+		{
+			name:     "last optional section dropped",
+			template: DefaultNicknameTemplate,
+			member:   MemberMetadata{FirstName: "Alexandria", LastName: "Montgomeryss", Pronouns: SheHer},
+			expected: "Alexandria Montgomeryss",
+		},
+		{
+			name:     "all optional sections dropped",
+			template: DefaultNicknameTemplate,
+			member:   MemberMetadata{FirstName: "Alexandria", LastName: "Montgomery-Worthington", Pronouns: HeHim},
+			expected: "Alexandria",
+		},
+		{
+			name:     "truncated",
+			template: "{first}",
+			member:   MemberMetadata{FirstName: strings.Repeat("a", 40)},
+			expected: strings.Repeat("a", 31) + "…",
+		},
+		{
+			name:     "truncated multi-byte",
+			template: "{first}",
+			member:   MemberMetadata{FirstName: strings.Repeat("世", 40)},
+			expected: strings.Repeat("世", 31) + "…",
+		},
+		{
+			name:     "truncated at a space",
+			template: "{first} {last}",
+			member:   MemberMetadata{FirstName: strings.Repeat("a", 30), LastName: "Bbbbb"},
+			expected: strings.Repeat("a", 30) + "…",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tmpl, err := ParseNicknameTemplate(test.template)
+			if err != nil {
+				t.Fatal("cannot parse template:", err)
+			}
+
+			nick := tmpl.Format(test.member)
+			if nick != test.expected {
+				t.Errorf("unexpected nickname\n"+
+					"expected: %q\n"+
+					"actual:   %q",
+					test.expected, nick)
+			}
+
+			if n := utf8.RuneCountInString(nick); n > MaxNicknameLength {
+				t.Errorf("nickname is %d characters long, over %d", n, MaxNicknameLength)
+			}
+		})
+	}
+}
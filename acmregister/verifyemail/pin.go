@@ -21,7 +21,9 @@ type PINStore interface {
 	// TODO: invalidate the old PIN if there's already an existing one.
 	GeneratePIN(discord.GuildID, discord.UserID) (PIN, error)
 	// ValidatePIN validates the email associated with the given PIN. PINStores
-	// should use its underlying SubmissionStore for this.
+	// should use its underlying SubmissionStore for this. Every attempt is
+	// recorded for the registration stats, except attempts in unknown guilds,
+	// which only return ErrNotFound.
 	ValidatePIN(discord.GuildID, discord.UserID, PIN) (*acmregister.MemberMetadata, error)
 }
 
@@ -9,7 +9,8 @@ import (
 )
 
 type VerifyEmailData struct {
-	AppID  discord.AppID      `json:"app_id"`
-	Token  string             `json:"token"`
-	Member acmregister.Member `json:"member"`
+	AppID    discord.AppID             `json:"app_id"`
+	Token    string                    `json:"token"`
+	Member   acmregister.Member        `json:"member"`
+	Messages acmregister.GuildMessages `json:"messages"`
 }
@@ -32,11 +32,12 @@ func (s confirmationEmailScheduler) Close() error {
 	return nil
 }
 
-func (s confirmationEmailScheduler) ScheduleConfirmationEmail(c *bot.Client, ev *discord.InteractionEvent, m acmregister.Member) error {
+func (s confirmationEmailScheduler) ScheduleConfirmationEmail(c *bot.Client, ev *discord.InteractionEvent, m acmregister.Member, msgs acmregister.GuildMessages) error {
 	body, err := json.Marshal(api.VerifyEmailData{
-		AppID:  ev.AppID,
-		Token:  ev.Token,
-		Member: m,
+		AppID:    ev.AppID,
+		Token:    ev.Token,
+		Member:   m,
+		Messages: msgs,
 	})
 	if err != nil {
 		return errors.Wrap(err, "cannot marshal VerifyEmailData")
@@ -72,5 +72,5 @@ func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	client := bot.NewClient(r.Context(), h.discord)
 
-	bot.SendConfirmationEmail(r.Context(), h.opts.SMTPVerifier, client, ev, data.Member)
+	bot.SendConfirmationEmail(r.Context(), h.opts.SMTPVerifier, client, ev, data.Member, data.Messages)
 }
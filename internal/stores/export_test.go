@@ -0,0 +1,41 @@
+package stores
+
+import (
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+// The Advance methods implement storetest.TimeTraveler by moving the expiry
+// time of the guild's submissions back instead of moving the clock forward.
+
+func (s memoryStore) Advance(guildID discord.GuildID, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return errUnknownGuild
+	}
+
+	for userID, submission := range g.submissions {
+		submission.expireAt = submission.expireAt.Add(-d)
+		g.submissions[userID] = submission
+	}
+
+	return nil
+}
+
+func (s sqliteStore) Advance(guildID discord.GuildID, d time.Duration) error {
+	_, err := s.db.ExecContext(s.ctx,
+		"UPDATE registration_submissions SET expire_at = expire_at - ? WHERE guild_id = ?",
+		int64(d/time.Second), int64(guildID))
+	return err
+}
+
+func (s pgStore) Advance(guildID discord.GuildID, d time.Duration) error {
+	_, err := s.db.Exec(s.ctx,
+		"UPDATE registration_submissions SET expire_at = expire_at - make_interval(secs => $1) WHERE guild_id = $2",
+		d.Seconds(), int64(guildID))
+	return err
+}
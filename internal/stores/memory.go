@@ -0,0 +1,915 @@
+package stores
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/acmregister/acmregister"
+	"github.com/diamondburned/acmregister/acmregister/verifyemail"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/pkg/errors"
+)
+
+var errUnknownGuild = errors.New("guild is not registered")
+
+type memoryStore struct {
+	*memoryState
+	ctx context.Context
+}
+
+type memoryState struct {
+	mu     sync.Mutex
+	guilds map[discord.GuildID]*memoryGuild
+	// events is the member history of all guilds. It is kept outside of
+	// memoryGuild so that it outlives the guild, like in the SQL schemas.
+	events []acmregister.MemberEvent
+}
+
+// memoryGuild holds everything belonging to a guild, so deleting it cascades
+// the same way the SQL schemas do. The member history is the exception; see
+// memoryState.
+type memoryGuild struct {
+	info        acmregister.KnownGuild
+	members     map[discord.UserID]acmregister.Member
+	emails      map[acmregister.Email]discord.UserID
+	submissions map[discord.UserID]memorySubmission
+	pins        map[discord.UserID]verifyemail.PIN
+	approvals   map[discord.UserID]acmregister.Approval
+	pinAttempts []memoryPINAttempt
+	attendance  map[discord.EventID][]acmregister.Attendance
+	scheduled   map[discord.EventID]acmregister.ScheduledEvent
+	eventUsers  map[discord.EventID]map[discord.UserID]struct{}
+}
+
+type memoryPINAttempt struct {
+	success bool
+	at      time.Time
+}
+
+type memorySubmission struct {
+	metadata acmregister.MemberMetadata
+	expireAt time.Time
+}
+
+// NewMemory creates a new store that keeps everything in memory. Everything is
+// lost once the process exits, so it is only useful for tests and ephemeral
+// deployments.
+func NewMemory() StoreCloser {
+	return memoryStore{
+		memoryState: &memoryState{
+			guilds: make(map[discord.GuildID]*memoryGuild),
+		},
+		ctx: context.Background(),
+	}
+}
+
+func (s memoryStore) Close() error {
+	return nil
+}
+
+func (s memoryStore) WithContext(ctx context.Context) acmregister.ContainsContext {
+	s.ctx = ctx
+	return s
+}
+
+func (s memoryStore) InitGuild(guild acmregister.KnownGuild) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.guilds[guild.GuildID]; ok {
+		return errors.New("guild is already registered")
+	}
+
+	guild.FormFields = append([]acmregister.FormField(nil), guild.FormFields...)
+
+	s.guilds[guild.GuildID] = &memoryGuild{
+		info:        guild,
+		members:     make(map[discord.UserID]acmregister.Member),
+		emails:      make(map[acmregister.Email]discord.UserID),
+		submissions: make(map[discord.UserID]memorySubmission),
+		pins:        make(map[discord.UserID]verifyemail.PIN),
+		approvals:   make(map[discord.UserID]acmregister.Approval),
+		attendance:  make(map[discord.EventID][]acmregister.Attendance),
+		scheduled:   make(map[discord.EventID]acmregister.ScheduledEvent),
+		eventUsers:  make(map[discord.EventID]map[discord.UserID]struct{}),
+	}
+
+	return nil
+}
+
+func (s memoryStore) GuildInfo(guildID discord.GuildID) (*acmregister.KnownGuild, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return nil, acmregister.ErrNotFound
+	}
+
+	info := g.info
+	return &info, nil
+}
+
+func (s memoryStore) GuildSetAdminRole(guildID discord.GuildID, roleID discord.RoleID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return acmregister.ErrNotFound
+	}
+
+	g.info.AdminRoleID = roleID
+	return nil
+}
+
+func (s memoryStore) GuildSetRole(guildID discord.GuildID, roleID discord.RoleID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return acmregister.ErrNotFound
+	}
+
+	g.info.RoleID = roleID
+	return nil
+}
+
+func (s memoryStore) GuildSetRegisterMessage(guildID discord.GuildID, channelID discord.ChannelID, messageID discord.MessageID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return acmregister.ErrNotFound
+	}
+
+	g.info.ChannelID = channelID
+	g.info.RegisterMessageID = messageID
+	return nil
+}
+
+func (s memoryStore) GuildSetMessages(guildID discord.GuildID, registeredMessage string, msgs acmregister.GuildMessages) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return acmregister.ErrNotFound
+	}
+
+	g.info.RegisteredMessage = registeredMessage
+	g.info.Messages = msgs
+	return nil
+}
+
+func (s memoryStore) GuildSetFormFields(guildID discord.GuildID, fields []acmregister.FormField) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return acmregister.ErrNotFound
+	}
+
+	// Copy the fields so that the caller can't change them. This also makes
+	// empty fields nil, like the SQL stores.
+	g.info.FormFields = append([]acmregister.FormField(nil), fields...)
+	return nil
+}
+
+func (s memoryStore) GuildSetNickname(guildID discord.GuildID, settings acmregister.NicknameSettings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return acmregister.ErrNotFound
+	}
+
+	g.info.Nickname = settings
+	return nil
+}
+
+func (s memoryStore) GuildSetApprovalChannel(guildID discord.GuildID, channelID discord.ChannelID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return acmregister.ErrNotFound
+	}
+
+	g.info.ApprovalChannelID = channelID
+	return nil
+}
+
+func (s memoryStore) GuildSetAuditChannel(guildID discord.GuildID, channelID discord.ChannelID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return acmregister.ErrNotFound
+	}
+
+	g.info.AuditChannelID = channelID
+	return nil
+}
+
+func (s memoryStore) DeleteGuild(guildID discord.GuildID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.guilds[guildID]; !ok {
+		return acmregister.ErrNotFound
+	}
+
+	delete(s.guilds, guildID)
+	return nil
+}
+
+func (s memoryStore) MemberInfo(guildID discord.GuildID, userID discord.UserID) (*acmregister.MemberMetadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return nil, acmregister.ErrNotFound
+	}
+
+	member, ok := g.members[userID]
+	if !ok {
+		return nil, acmregister.ErrNotFound
+	}
+
+	return &member.Metadata, nil
+}
+
+func (s memoryStore) RegisterMember(m acmregister.Member) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[m.GuildID]
+	if !ok {
+		return errUnknownGuild
+	}
+
+	if _, ok := g.members[m.UserID]; ok {
+		return acmregister.ErrMemberAlreadyExists
+	}
+
+	if _, ok := g.emails[m.Metadata.Email]; ok {
+		return acmregister.ErrMemberAlreadyExists
+	}
+
+	m.RegisteredAt = registeredAt(m)
+	m.DepartedAt = time.Time{}
+
+	g.members[m.UserID] = m
+	g.emails[m.Metadata.Email] = m.UserID
+	g.deleteSubmission(m.UserID)
+	s.addEvent(registeredEvent(m))
+
+	return nil
+}
+
+func (s memoryStore) UpdateMemberMetadata(guildID discord.GuildID, userID discord.UserID, metadata acmregister.MemberMetadata) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return acmregister.ErrNotFound
+	}
+
+	m, ok := g.members[userID]
+	if !ok {
+		return acmregister.ErrNotFound
+	}
+
+	if owner, ok := g.emails[metadata.Email]; ok && owner != userID {
+		return acmregister.ErrMemberAlreadyExists
+	}
+
+	delete(g.emails, m.Metadata.Email)
+	g.emails[metadata.Email] = userID
+
+	m.Metadata = metadata
+	g.members[userID] = m
+	g.deleteSubmission(userID)
+	s.addEvent(acmregister.MemberEvent{
+		GuildID: guildID,
+		UserID:  userID,
+		Type:    acmregister.MemberEdited,
+		ActorID: userID,
+	})
+
+	return nil
+}
+
+func (s memoryStore) SetMemberDeparted(guildID discord.GuildID, userID discord.UserID, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return acmregister.ErrNotFound
+	}
+
+	member, ok := g.members[userID]
+	if !ok {
+		return acmregister.ErrNotFound
+	}
+
+	member.DepartedAt = at
+	g.members[userID] = member
+	return nil
+}
+
+func (s memoryStore) UnregisterMember(guildID discord.GuildID, userID, actorID discord.UserID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return acmregister.ErrNotFound
+	}
+
+	member, ok := g.members[userID]
+	if !ok {
+		return acmregister.ErrNotFound
+	}
+
+	delete(g.members, userID)
+	delete(g.emails, member.Metadata.Email)
+	s.addEvent(acmregister.MemberEvent{
+		GuildID: guildID,
+		UserID:  userID,
+		Type:    acmregister.MemberUnregistered,
+		ActorID: actorID,
+	})
+
+	return nil
+}
+
+func (s memoryStore) AddMemberEvent(ev acmregister.MemberEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.addEvent(ev)
+	return nil
+}
+
+func (s memoryStore) MemberEvents(guildID discord.GuildID, userID discord.UserID) ([]acmregister.MemberEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []acmregister.MemberEvent
+	for _, ev := range s.events {
+		if ev.GuildID == guildID && ev.UserID == userID {
+			events = append(events, ev)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+
+	return events, nil
+}
+
+func (s memoryStore) ListMembers(guildID discord.GuildID, cursor acmregister.MemberCursor, limit int) ([]acmregister.Member, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return nil, nil
+	}
+
+	members := make([]acmregister.Member, 0, len(g.members))
+	for userID, member := range g.members {
+		if cursor.Before && userID >= cursor.UserID {
+			continue
+		}
+		if !cursor.Before && userID <= cursor.UserID {
+			continue
+		}
+		members = append(members, member)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].UserID < members[j].UserID
+	})
+
+	if len(members) > limit {
+		if cursor.Before {
+			members = members[len(members)-limit:]
+		} else {
+			members = members[:limit]
+		}
+	}
+
+	return members, nil
+}
+
+func (s memoryStore) SearchMembers(guildID discord.GuildID, query string, limit int) ([]acmregister.Member, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return nil, nil
+	}
+
+	query = strings.ToLower(query)
+
+	var members []acmregister.Member
+	for _, member := range g.members {
+		// Match the same way the SQL stores do.
+		metadata := member.Metadata
+		haystack := string(metadata.Email) + "\n" + metadata.FirstName + " " + metadata.LastName
+		if !strings.Contains(strings.ToLower(haystack), query) {
+			continue
+		}
+		members = append(members, member)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].UserID < members[j].UserID
+	})
+
+	if len(members) > limit {
+		members = members[:limit]
+	}
+
+	return members, nil
+}
+
+func (s memoryStore) SaveSubmission(m acmregister.Member) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[m.GuildID]
+	if !ok {
+		return errUnknownGuild
+	}
+
+	now := time.Now()
+
+	g.submissions[m.UserID] = memorySubmission{
+		metadata: m.Metadata,
+		expireAt: now.Add(acmregister.SubmissionSaveDuration),
+	}
+
+	s.cleanupSubmissions(now)
+	return nil
+}
+
+func (s memoryStore) RestoreSubmission(guildID discord.GuildID, userID discord.UserID) (*acmregister.MemberMetadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return nil, acmregister.ErrNotFound
+	}
+
+	submission, ok := g.submissions[userID]
+	if !ok || submission.expireAt.Before(time.Now()) {
+		return nil, acmregister.ErrNotFound
+	}
+
+	return &submission.metadata, nil
+}
+
+func (s memoryStore) SubmitApproval(a acmregister.Approval) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[a.GuildID]
+	if !ok {
+		return errUnknownGuild
+	}
+
+	g.approvals[a.UserID] = acmregister.Approval{
+		GuildID:     a.GuildID,
+		UserID:      a.UserID,
+		Metadata:    a.Metadata,
+		SubmittedAt: submittedAt(a),
+	}
+	g.deleteSubmission(a.UserID)
+
+	return nil
+}
+
+func (s memoryStore) ApprovalInfo(guildID discord.GuildID, userID discord.UserID) (*acmregister.Approval, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return nil, acmregister.ErrNotFound
+	}
+
+	approval, ok := g.approvals[userID]
+	if !ok {
+		return nil, acmregister.ErrNotFound
+	}
+
+	return &approval, nil
+}
+
+func (s memoryStore) ApproveMember(guildID discord.GuildID, userID, approvedBy discord.UserID) (*acmregister.MemberMetadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return nil, acmregister.ErrNotFound
+	}
+
+	approval, ok := g.approvals[userID]
+	if !ok || !approval.IsPending() {
+		return nil, acmregister.ErrNotFound
+	}
+
+	if _, ok := g.members[userID]; ok {
+		return nil, acmregister.ErrMemberAlreadyExists
+	}
+
+	if _, ok := g.emails[approval.Metadata.Email]; ok {
+		return nil, acmregister.ErrMemberAlreadyExists
+	}
+
+	now := time.Now()
+
+	g.members[userID] = acmregister.Member{
+		GuildID:      guildID,
+		UserID:       userID,
+		Metadata:     approval.Metadata,
+		RegisteredAt: now,
+	}
+	g.emails[approval.Metadata.Email] = userID
+	delete(g.approvals, userID)
+	s.addEvent(acmregister.MemberEvent{
+		GuildID: guildID,
+		UserID:  userID,
+		Type:    acmregister.MemberRegistered,
+		ActorID: approvedBy,
+		Time:    now,
+	})
+
+	return &approval.Metadata, nil
+}
+
+func (s memoryStore) DenyMember(guildID discord.GuildID, userID, deniedBy discord.UserID, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return acmregister.ErrNotFound
+	}
+
+	approval, ok := g.approvals[userID]
+	if !ok || !approval.IsPending() {
+		return acmregister.ErrNotFound
+	}
+
+	approval.DeniedAt = time.Now()
+	approval.DeniedBy = deniedBy
+	approval.DenyReason = reason
+	g.approvals[userID] = approval
+
+	return nil
+}
+
+func (s memoryStore) CheckIn(a acmregister.Attendance) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[a.GuildID]
+	if !ok {
+		return errUnknownGuild
+	}
+
+	for _, attendance := range g.attendance[a.EventID] {
+		if attendance.UserID == a.UserID {
+			return acmregister.ErrAlreadyCheckedIn
+		}
+	}
+
+	a.CheckedInAt = checkedInAt(a)
+	g.attendance[a.EventID] = append(g.attendance[a.EventID], a)
+
+	return nil
+}
+
+func (s memoryStore) EventAttendees(guildID discord.GuildID, eventID discord.EventID) ([]acmregister.Attendance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return nil, nil
+	}
+
+	attendees := append([]acmregister.Attendance(nil), g.attendance[eventID]...)
+	sort.SliceStable(attendees, func(i, j int) bool {
+		return attendees[i].CheckedInAt.Before(attendees[j].CheckedInAt)
+	})
+
+	return attendees, nil
+}
+
+func (s memoryStore) SaveEvent(ev acmregister.ScheduledEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[ev.GuildID]
+	if !ok {
+		return errUnknownGuild
+	}
+
+	g.scheduled[ev.EventID] = ev
+	return nil
+}
+
+func (s memoryStore) EventInfo(guildID discord.GuildID, eventID discord.EventID) (*acmregister.ScheduledEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return nil, acmregister.ErrNotFound
+	}
+
+	ev, ok := g.scheduled[eventID]
+	if !ok {
+		return nil, acmregister.ErrNotFound
+	}
+
+	return &ev, nil
+}
+
+func (s memoryStore) SearchEvents(guildID discord.GuildID, query string, limit int) ([]acmregister.ScheduledEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return nil, nil
+	}
+
+	query = strings.ToLower(query)
+
+	var events []acmregister.ScheduledEvent
+	for _, ev := range g.scheduled {
+		if strings.Contains(strings.ToLower(ev.Name), query) {
+			events = append(events, ev)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].StartTime.Equal(events[j].StartTime) {
+			return events[i].StartTime.After(events[j].StartTime)
+		}
+		return events[i].EventID > events[j].EventID
+	})
+
+	if len(events) > limit {
+		events = events[:limit]
+	}
+
+	return events, nil
+}
+
+func (s memoryStore) EventsBetween(guildID discord.GuildID, from, to time.Time) ([]acmregister.ScheduledEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return nil, nil
+	}
+
+	var events []acmregister.ScheduledEvent
+	for _, ev := range g.scheduled {
+		if !ev.StartTime.Before(from) && ev.StartTime.Before(to) {
+			events = append(events, ev)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].StartTime.Equal(events[j].StartTime) {
+			return events[i].StartTime.Before(events[j].StartTime)
+		}
+		return events[i].EventID < events[j].EventID
+	})
+
+	return events, nil
+}
+
+func (s memoryStore) AddEventParticipants(guildID discord.GuildID, eventID discord.EventID, userIDs ...discord.UserID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return errUnknownGuild
+	}
+
+	users, ok := g.eventUsers[eventID]
+	if !ok {
+		users = make(map[discord.UserID]struct{}, len(userIDs))
+		g.eventUsers[eventID] = users
+	}
+
+	for _, userID := range userIDs {
+		users[userID] = struct{}{}
+	}
+
+	return nil
+}
+
+func (s memoryStore) RemoveEventParticipant(guildID discord.GuildID, eventID discord.EventID, userID discord.UserID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if g, ok := s.guilds[guildID]; ok {
+		delete(g.eventUsers[eventID], userID)
+	}
+
+	return nil
+}
+
+func (s memoryStore) EventParticipants(guildID discord.GuildID, eventID discord.EventID) ([]discord.UserID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return nil, nil
+	}
+
+	userIDs := make([]discord.UserID, 0, len(g.eventUsers[eventID]))
+	for userID := range g.eventUsers[eventID] {
+		userIDs = append(userIDs, userID)
+	}
+
+	sort.Slice(userIDs, func(i, j int) bool {
+		return userIDs[i] < userIDs[j]
+	})
+
+	return userIDs, nil
+}
+
+func (s memoryStore) GeneratePIN(guildID discord.GuildID, userID discord.UserID) (verifyemail.PIN, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, 15*time.Second)
+	defer cancel()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return verifyemail.InvalidPIN, errUnknownGuild
+	}
+
+	// PINs reference a submission.
+	if _, ok := g.submissions[userID]; !ok {
+		return verifyemail.InvalidPIN, errors.New("cannot store PIN: no submission")
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return verifyemail.InvalidPIN, ctx.Err()
+		default:
+		}
+
+		pin := verifyemail.GeneratePIN()
+		if g.pinUsed(userID, pin) {
+			continue
+		}
+
+		g.pins[userID] = pin
+		return pin, nil
+	}
+}
+
+func (s memoryStore) ValidatePIN(guildID discord.GuildID, userID discord.UserID, pin verifyemail.PIN) (*acmregister.MemberMetadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return nil, acmregister.ErrNotFound
+	}
+
+	now := time.Now()
+
+	known, ok := g.pins[userID]
+	submission, hasSubmission := g.submissions[userID]
+	valid := ok && known == pin && hasSubmission && !submission.expireAt.Before(now)
+
+	g.pinAttempts = append(g.pinAttempts, memoryPINAttempt{
+		success: valid,
+		at:      now,
+	})
+
+	if !valid {
+		return nil, acmregister.ErrNotFound
+	}
+
+	return &submission.metadata, nil
+}
+
+func (s memoryStore) RegistrationStats(guildID discord.GuildID, since time.Time) (*acmregister.RegistrationStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := newRegistrationStats(since)
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return stats, nil
+	}
+
+	now := time.Now()
+
+	stats.Members = len(g.members)
+	for _, m := range g.members {
+		if !m.RegisteredAt.Before(since) {
+			addWeekly(stats, int(m.RegisteredAt.Sub(since)/week), 1)
+		}
+
+		_, host, _ := m.Metadata.Email.Split()
+		stats.EmailHosts[strings.ToLower(host)]++
+		stats.Pronouns[m.Metadata.Pronouns]++
+	}
+
+	for _, submission := range g.submissions {
+		if !submission.expireAt.Before(now) {
+			stats.PendingSubmissions++
+		}
+	}
+
+	for _, approval := range g.approvals {
+		if approval.IsPending() {
+			stats.PendingApprovals++
+		}
+	}
+
+	for _, attempt := range g.pinAttempts {
+		if attempt.at.Before(since) {
+			continue
+		}
+		stats.PINAttempts++
+		if attempt.success {
+			stats.PINSuccesses++
+		}
+	}
+
+	return stats, nil
+}
+
+func (s *memoryState) cleanupSubmissions(now time.Time) {
+	for _, g := range s.guilds {
+		for userID, submission := range g.submissions {
+			if submission.expireAt.Before(now) {
+				g.deleteSubmission(userID)
+			}
+		}
+	}
+}
+
+// deleteSubmission deletes the user's submission along with its PIN.
+func (g *memoryGuild) deleteSubmission(userID discord.UserID) {
+	delete(g.submissions, userID)
+	delete(g.pins, userID)
+}
+
+// addEvent appends the event to the member history. The caller must hold the
+// lock.
+func (s *memoryState) addEvent(ev acmregister.MemberEvent) {
+	ev.Time = eventTime(ev)
+	s.events = append(s.events, ev)
+}
+
+// pinUsed returns true if the PIN is already used by another user in the
+// guild.
+func (g *memoryGuild) pinUsed(userID discord.UserID, pin verifyemail.PIN) bool {
+	for id, used := range g.pins {
+		if id != userID && used == pin {
+			return true
+		}
+	}
+	return false
+}
@@ -8,6 +8,19 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type EventAttendance struct {
+	GuildID     int64
+	EventID     int64
+	UserID      int64
+	CheckedInAt pgtype.Timestamptz
+}
+
+type EventParticipant struct {
+	GuildID int64
+	EventID int64
+	UserID  int64
+}
+
 type KnownGuild struct {
 	GuildID           int64
 	ChannelID         int64
@@ -15,28 +28,71 @@ type KnownGuild struct {
 	InitUserID        int64
 	RegisteredMessage string
 	AdminRoleID       pgtype.Int8
+	Messages          []byte
+	RegisterMessageID pgtype.Int8
+	FormFields        []byte
+	Nickname          []byte
+	ApprovalChannelID pgtype.Int8
+	AuditChannelID    pgtype.Int8
 }
 
 type Member struct {
-	GuildID  int64
-	UserID   int64
-	Email    string
-	Metadata []byte
+	GuildID      int64
+	UserID       int64
+	Email        string
+	Metadata     []byte
+	RegisteredAt pgtype.Timestamptz
+	RegisteredBy pgtype.Int8
+	DepartedAt   pgtype.Timestamptz
+}
+
+type MemberEvent struct {
+	ID        int64
+	GuildID   int64
+	UserID    int64
+	Type      string
+	ActorID   pgtype.Int8
+	CreatedAt pgtype.Timestamptz
 }
 
 type Meta struct {
 	V int16
 }
 
+type PinAttempt struct {
+	GuildID     int64
+	UserID      int64
+	Success     bool
+	AttemptedAt pgtype.Timestamptz
+}
+
 type PinCode struct {
 	GuildID int64
 	UserID  int64
 	Pin     int16
 }
 
+type RegistrationApproval struct {
+	GuildID     int64
+	UserID      int64
+	Metadata    []byte
+	SubmittedAt pgtype.Timestamptz
+	DeniedAt    pgtype.Timestamptz
+	DeniedBy    pgtype.Int8
+	DenyReason  string
+}
+
 type RegistrationSubmission struct {
 	GuildID  int64
 	UserID   int64
 	Metadata []byte
 	ExpireAt pgtype.Timestamp
 }
+
+type ScheduledEvent struct {
+	GuildID   int64
+	EventID   int64
+	Name      string
+	StartTime pgtype.Timestamptz
+	Ended     bool
+}
@@ -29,10 +29,16 @@ INSERT INTO
 		channel_id,
 		init_user_id,
 		role_id,
-		registered_message
+		registered_message,
+		messages,
+		register_message_id,
+		form_fields,
+		nickname,
+		approval_channel_id,
+		audit_channel_id
 	)
 VALUES
-	($1, $2, $3, $4, $5);
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
 
 -- name: SetGuildAdminRoleID :execrows
 UPDATE
@@ -42,11 +48,88 @@ SET
 WHERE
 	guild_id = $1;
 
+-- name: SetGuildRoleID :execrows
+UPDATE
+	known_guilds
+SET
+	role_id = $2
+WHERE
+	guild_id = $1;
+
+-- name: SetGuildMessages :execrows
+UPDATE
+	known_guilds
+SET
+	registered_message = $2,
+	messages = $3
+WHERE
+	guild_id = $1;
+
+-- name: SetGuildRegisterMessage :execrows
+UPDATE
+	known_guilds
+SET
+	channel_id = $2,
+	register_message_id = $3
+WHERE
+	guild_id = $1;
+
+-- name: SetGuildFormFields :execrows
+UPDATE
+	known_guilds
+SET
+	form_fields = $2
+WHERE
+	guild_id = $1;
+
+-- name: SetGuildNickname :execrows
+UPDATE
+	known_guilds
+SET
+	nickname = $2
+WHERE
+	guild_id = $1;
+
+-- name: SetGuildApprovalChannelID :execrows
+UPDATE
+	known_guilds
+SET
+	approval_channel_id = $2
+WHERE
+	guild_id = $1;
+
+-- name: SetGuildAuditChannelID :execrows
+UPDATE
+	known_guilds
+SET
+	audit_channel_id = $2
+WHERE
+	guild_id = $1;
+
 -- name: RegisterMember :exec
 INSERT INTO
-	members (guild_id, user_id, email, metadata)
+	members (guild_id, user_id, email, metadata, registered_at, registered_by)
 VALUES
-	($1, $2, $3, $4);
+	($1, $2, $3, $4, $5, $6);
+
+-- name: SetMemberDepartedAt :execrows
+UPDATE
+	members
+SET
+	departed_at = $3
+WHERE
+	guild_id = $1
+	AND user_id = $2;
+
+-- name: UpdateMemberMetadata :execrows
+UPDATE
+	members
+SET
+	email = $3,
+	metadata = $4
+WHERE
+	guild_id = $1
+	AND user_id = $2;
 
 -- name: UnregisterMember :execrows
 DELETE FROM
@@ -120,3 +203,335 @@ WHERE
 	AND pin_codes.user_id = $2
 	AND pin_codes.pin = $3
 	AND registration_submissions.expire_at >= NOW();
+
+-- name: ListMembersAfter :many
+SELECT
+	user_id,
+	metadata,
+	registered_at,
+	registered_by,
+	departed_at
+FROM
+	members
+WHERE
+	guild_id = $1
+	AND user_id > $2
+ORDER BY
+	user_id ASC
+LIMIT
+	$3;
+
+-- name: ListMembersBefore :many
+SELECT
+	user_id,
+	metadata,
+	registered_at,
+	registered_by,
+	departed_at
+FROM
+	members
+WHERE
+	guild_id = $1
+	AND user_id < $2
+ORDER BY
+	user_id DESC
+LIMIT
+	$3;
+
+-- name: SearchMembers :many
+SELECT
+	user_id,
+	metadata,
+	registered_at,
+	registered_by,
+	departed_at
+FROM
+	members
+WHERE
+	guild_id = $1
+	-- Match against "email\nfirst last", so that the full name can also be
+	-- searched. Old rows have their metadata nested in a Metadata object.
+	AND CONCAT_WS(
+		E'\n',
+		email,
+		CONCAT_WS(
+			' ',
+			COALESCE(
+				metadata ->> 'first_name',
+				metadata -> 'Metadata' ->> 'first_name'
+			),
+			COALESCE(
+				metadata ->> 'last_name',
+				metadata -> 'Metadata' ->> 'last_name'
+			)
+		)
+	) ILIKE sqlc.arg(pattern)
+ORDER BY
+	user_id ASC
+LIMIT
+	sqlc.arg(max_results);
+
+-- name: SubmitApproval :exec
+INSERT INTO
+	registration_approvals (guild_id, user_id, metadata, submitted_at)
+VALUES
+	($1, $2, $3, $4) ON CONFLICT (guild_id, user_id)
+DO
+UPDATE
+SET
+	metadata = EXCLUDED.metadata,
+	submitted_at = EXCLUDED.submitted_at,
+	denied_at = NULL,
+	denied_by = NULL,
+	deny_reason = '';
+
+-- name: ApprovalInfo :one
+SELECT
+	metadata,
+	submitted_at,
+	denied_at,
+	denied_by,
+	deny_reason
+FROM
+	registration_approvals
+WHERE
+	guild_id = $1
+	AND user_id = $2;
+
+-- name: DeletePendingApproval :execrows
+DELETE FROM
+	registration_approvals
+WHERE
+	guild_id = $1
+	AND user_id = $2
+	AND denied_at IS NULL;
+
+-- name: DenyApproval :execrows
+UPDATE
+	registration_approvals
+SET
+	denied_at = $3,
+	denied_by = $4,
+	deny_reason = $5
+WHERE
+	guild_id = $1
+	AND user_id = $2
+	AND denied_at IS NULL;
+
+-- name: AddMemberEvent :exec
+INSERT INTO
+	member_events (guild_id, user_id, type, actor_id, created_at)
+VALUES
+	($1, $2, $3, $4, $5);
+
+-- name: MemberEvents :many
+SELECT
+	type,
+	actor_id,
+	created_at
+FROM
+	member_events
+WHERE
+	guild_id = $1
+	AND user_id = $2
+ORDER BY
+	created_at ASC,
+	id ASC;
+
+-- name: AddPINAttempt :exec
+-- Attempts in unknown guilds are not recorded.
+INSERT INTO
+	pin_attempts (guild_id, user_id, success, attempted_at)
+SELECT
+	known_guilds.guild_id,
+	sqlc.arg(user_id)::BIGINT,
+	sqlc.arg(success)::BOOLEAN,
+	sqlc.arg(attempted_at)::TIMESTAMPTZ
+FROM
+	known_guilds
+WHERE
+	known_guilds.guild_id = sqlc.arg(guild_id);
+
+-- name: CountMembers :one
+SELECT
+	COUNT(*)
+FROM
+	members
+WHERE
+	guild_id = $1;
+
+-- name: CountMembersPerWeek :many
+SELECT
+	FLOOR(
+		EXTRACT(
+			EPOCH
+			FROM
+				registered_at - sqlc.arg(since)::TIMESTAMPTZ
+		) / 604800
+	)::BIGINT AS week,
+	COUNT(*) AS members
+FROM
+	members
+WHERE
+	guild_id = sqlc.arg(guild_id)
+	AND registered_at >= sqlc.arg(since)::TIMESTAMPTZ
+GROUP BY
+	week;
+
+-- name: CountMembersByEmailHost :many
+SELECT
+	LOWER(SPLIT_PART(email, '@', 2))::TEXT AS host,
+	COUNT(*) AS members
+FROM
+	members
+WHERE
+	guild_id = $1
+GROUP BY
+	host;
+
+-- name: CountMembersByPronouns :many
+-- Old rows have their metadata nested in a Metadata object.
+SELECT
+	COALESCE(
+		metadata ->> 'pronouns',
+		metadata -> 'Metadata' ->> 'pronouns',
+		''
+	)::TEXT AS pronouns,
+	COUNT(*) AS members
+FROM
+	members
+WHERE
+	guild_id = $1
+GROUP BY
+	pronouns;
+
+-- name: CountPendingSubmissions :one
+SELECT
+	COUNT(*)
+FROM
+	registration_submissions
+WHERE
+	guild_id = $1
+	AND expire_at >= NOW();
+
+-- name: CountPendingApprovals :one
+SELECT
+	COUNT(*)
+FROM
+	registration_approvals
+WHERE
+	guild_id = $1
+	AND denied_at IS NULL;
+
+-- name: CountPINAttempts :one
+SELECT
+	COUNT(*) AS attempts,
+	COUNT(*) FILTER (
+		WHERE
+			success
+	) AS successes
+FROM
+	pin_attempts
+WHERE
+	guild_id = sqlc.arg(guild_id)
+	AND attempted_at >= sqlc.arg(since);
+
+-- name: CheckIn :exec
+INSERT INTO
+	event_attendance (guild_id, event_id, user_id, checked_in_at)
+VALUES
+	($1, $2, $3, $4);
+
+-- name: EventAttendees :many
+SELECT
+	user_id,
+	checked_in_at
+FROM
+	event_attendance
+WHERE
+	guild_id = $1
+	AND event_id = $2
+ORDER BY
+	checked_in_at ASC,
+	user_id ASC;
+
+-- name: SaveEvent :exec
+INSERT INTO
+	scheduled_events (guild_id, event_id, name, start_time, ended)
+VALUES
+	($1, $2, $3, $4, $5) ON CONFLICT (guild_id, event_id)
+DO
+UPDATE
+SET
+	name = EXCLUDED.name,
+	start_time = EXCLUDED.start_time,
+	ended = EXCLUDED.ended;
+
+-- name: EventInfo :one
+SELECT
+	name,
+	start_time,
+	ended
+FROM
+	scheduled_events
+WHERE
+	guild_id = $1
+	AND event_id = $2;
+
+-- name: SearchEvents :many
+SELECT
+	event_id,
+	name,
+	start_time,
+	ended
+FROM
+	scheduled_events
+WHERE
+	guild_id = sqlc.arg(guild_id)
+	AND name ILIKE sqlc.arg(pattern)
+ORDER BY
+	start_time DESC,
+	event_id DESC
+LIMIT
+	sqlc.arg(max_results);
+
+-- name: EventsBetween :many
+SELECT
+	event_id,
+	name,
+	start_time,
+	ended
+FROM
+	scheduled_events
+WHERE
+	guild_id = sqlc.arg(guild_id)
+	AND start_time >= sqlc.arg(from_time)
+	AND start_time < sqlc.arg(to_time)
+ORDER BY
+	start_time ASC,
+	event_id ASC;
+
+-- name: AddEventParticipant :exec
+INSERT INTO
+	event_participants (guild_id, event_id, user_id)
+VALUES
+	($1, $2, $3) ON CONFLICT DO NOTHING;
+
+-- name: RemoveEventParticipant :exec
+DELETE FROM
+	event_participants
+WHERE
+	guild_id = $1
+	AND event_id = $2
+	AND user_id = $3;
+
+-- name: EventParticipants :many
+SELECT
+	user_id
+FROM
+	event_participants
+WHERE
+	guild_id = $1
+	AND event_id = $2
+ORDER BY
+	user_id ASC;
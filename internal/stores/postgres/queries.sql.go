@@ -11,6 +11,146 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addEventParticipant = `-- name: AddEventParticipant :exec
+INSERT INTO
+	event_participants (guild_id, event_id, user_id)
+VALUES
+	($1, $2, $3) ON CONFLICT DO NOTHING
+`
+
+type AddEventParticipantParams struct {
+	GuildID int64
+	EventID int64
+	UserID  int64
+}
+
+func (q *Queries) AddEventParticipant(ctx context.Context, arg AddEventParticipantParams) error {
+	_, err := q.db.Exec(ctx, addEventParticipant, arg.GuildID, arg.EventID, arg.UserID)
+	return err
+}
+
+const addMemberEvent = `-- name: AddMemberEvent :exec
+INSERT INTO
+	member_events (guild_id, user_id, type, actor_id, created_at)
+VALUES
+	($1, $2, $3, $4, $5)
+`
+
+type AddMemberEventParams struct {
+	GuildID   int64
+	UserID    int64
+	Type      string
+	ActorID   pgtype.Int8
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) AddMemberEvent(ctx context.Context, arg AddMemberEventParams) error {
+	_, err := q.db.Exec(ctx, addMemberEvent,
+		arg.GuildID,
+		arg.UserID,
+		arg.Type,
+		arg.ActorID,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const addPINAttempt = `-- name: AddPINAttempt :exec
+INSERT INTO
+	pin_attempts (guild_id, user_id, success, attempted_at)
+SELECT
+	known_guilds.guild_id,
+	$1::BIGINT,
+	$2::BOOLEAN,
+	$3::TIMESTAMPTZ
+FROM
+	known_guilds
+WHERE
+	known_guilds.guild_id = $4
+`
+
+type AddPINAttemptParams struct {
+	UserID      int64
+	Success     bool
+	AttemptedAt pgtype.Timestamptz
+	GuildID     int64
+}
+
+// Attempts in unknown guilds are not recorded.
+func (q *Queries) AddPINAttempt(ctx context.Context, arg AddPINAttemptParams) error {
+	_, err := q.db.Exec(ctx, addPINAttempt,
+		arg.UserID,
+		arg.Success,
+		arg.AttemptedAt,
+		arg.GuildID,
+	)
+	return err
+}
+
+const approvalInfo = `-- name: ApprovalInfo :one
+SELECT
+	metadata,
+	submitted_at,
+	denied_at,
+	denied_by,
+	deny_reason
+FROM
+	registration_approvals
+WHERE
+	guild_id = $1
+	AND user_id = $2
+`
+
+type ApprovalInfoParams struct {
+	GuildID int64
+	UserID  int64
+}
+
+type ApprovalInfoRow struct {
+	Metadata    []byte
+	SubmittedAt pgtype.Timestamptz
+	DeniedAt    pgtype.Timestamptz
+	DeniedBy    pgtype.Int8
+	DenyReason  string
+}
+
+func (q *Queries) ApprovalInfo(ctx context.Context, arg ApprovalInfoParams) (ApprovalInfoRow, error) {
+	row := q.db.QueryRow(ctx, approvalInfo, arg.GuildID, arg.UserID)
+	var i ApprovalInfoRow
+	err := row.Scan(
+		&i.Metadata,
+		&i.SubmittedAt,
+		&i.DeniedAt,
+		&i.DeniedBy,
+		&i.DenyReason,
+	)
+	return i, err
+}
+
+const checkIn = `-- name: CheckIn :exec
+INSERT INTO
+	event_attendance (guild_id, event_id, user_id, checked_in_at)
+VALUES
+	($1, $2, $3, $4)
+`
+
+type CheckInParams struct {
+	GuildID     int64
+	EventID     int64
+	UserID      int64
+	CheckedInAt pgtype.Timestamptz
+}
+
+func (q *Queries) CheckIn(ctx context.Context, arg CheckInParams) error {
+	_, err := q.db.Exec(ctx, checkIn,
+		arg.GuildID,
+		arg.EventID,
+		arg.UserID,
+		arg.CheckedInAt,
+	)
+	return err
+}
+
 const cleanupSubmissions = `-- name: CleanupSubmissions :exec
 DELETE FROM
 	registration_submissions
@@ -23,6 +163,215 @@ func (q *Queries) CleanupSubmissions(ctx context.Context) error {
 	return err
 }
 
+const countMembers = `-- name: CountMembers :one
+SELECT
+	COUNT(*)
+FROM
+	members
+WHERE
+	guild_id = $1
+`
+
+func (q *Queries) CountMembers(ctx context.Context, guildID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countMembers, guildID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countMembersByEmailHost = `-- name: CountMembersByEmailHost :many
+SELECT
+	LOWER(SPLIT_PART(email, '@', 2))::TEXT AS host,
+	COUNT(*) AS members
+FROM
+	members
+WHERE
+	guild_id = $1
+GROUP BY
+	host
+`
+
+type CountMembersByEmailHostRow struct {
+	Host    string
+	Members int64
+}
+
+func (q *Queries) CountMembersByEmailHost(ctx context.Context, guildID int64) ([]CountMembersByEmailHostRow, error) {
+	rows, err := q.db.Query(ctx, countMembersByEmailHost, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountMembersByEmailHostRow
+	for rows.Next() {
+		var i CountMembersByEmailHostRow
+		if err := rows.Scan(&i.Host, &i.Members); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countMembersByPronouns = `-- name: CountMembersByPronouns :many
+SELECT
+	COALESCE(
+		metadata ->> 'pronouns',
+		metadata -> 'Metadata' ->> 'pronouns',
+		''
+	)::TEXT AS pronouns,
+	COUNT(*) AS members
+FROM
+	members
+WHERE
+	guild_id = $1
+GROUP BY
+	pronouns
+`
+
+type CountMembersByPronounsRow struct {
+	Pronouns string
+	Members  int64
+}
+
+// Old rows have their metadata nested in a Metadata object.
+func (q *Queries) CountMembersByPronouns(ctx context.Context, guildID int64) ([]CountMembersByPronounsRow, error) {
+	rows, err := q.db.Query(ctx, countMembersByPronouns, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountMembersByPronounsRow
+	for rows.Next() {
+		var i CountMembersByPronounsRow
+		if err := rows.Scan(&i.Pronouns, &i.Members); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countMembersPerWeek = `-- name: CountMembersPerWeek :many
+SELECT
+	FLOOR(
+		EXTRACT(
+			EPOCH
+			FROM
+				registered_at - $1::TIMESTAMPTZ
+		) / 604800
+	)::BIGINT AS week,
+	COUNT(*) AS members
+FROM
+	members
+WHERE
+	guild_id = $2
+	AND registered_at >= $1::TIMESTAMPTZ
+GROUP BY
+	week
+`
+
+type CountMembersPerWeekParams struct {
+	Since   pgtype.Timestamptz
+	GuildID int64
+}
+
+type CountMembersPerWeekRow struct {
+	Week    int64
+	Members int64
+}
+
+func (q *Queries) CountMembersPerWeek(ctx context.Context, arg CountMembersPerWeekParams) ([]CountMembersPerWeekRow, error) {
+	rows, err := q.db.Query(ctx, countMembersPerWeek, arg.Since, arg.GuildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountMembersPerWeekRow
+	for rows.Next() {
+		var i CountMembersPerWeekRow
+		if err := rows.Scan(&i.Week, &i.Members); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countPINAttempts = `-- name: CountPINAttempts :one
+SELECT
+	COUNT(*) AS attempts,
+	COUNT(*) FILTER (
+		WHERE
+			success
+	) AS successes
+FROM
+	pin_attempts
+WHERE
+	guild_id = $1
+	AND attempted_at >= $2
+`
+
+type CountPINAttemptsParams struct {
+	GuildID int64
+	Since   pgtype.Timestamptz
+}
+
+type CountPINAttemptsRow struct {
+	Attempts  int64
+	Successes int64
+}
+
+func (q *Queries) CountPINAttempts(ctx context.Context, arg CountPINAttemptsParams) (CountPINAttemptsRow, error) {
+	row := q.db.QueryRow(ctx, countPINAttempts, arg.GuildID, arg.Since)
+	var i CountPINAttemptsRow
+	err := row.Scan(&i.Attempts, &i.Successes)
+	return i, err
+}
+
+const countPendingApprovals = `-- name: CountPendingApprovals :one
+SELECT
+	COUNT(*)
+FROM
+	registration_approvals
+WHERE
+	guild_id = $1
+	AND denied_at IS NULL
+`
+
+func (q *Queries) CountPendingApprovals(ctx context.Context, guildID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countPendingApprovals, guildID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPendingSubmissions = `-- name: CountPendingSubmissions :one
+SELECT
+	COUNT(*)
+FROM
+	registration_submissions
+WHERE
+	guild_id = $1
+	AND expire_at >= NOW()
+`
+
+func (q *Queries) CountPendingSubmissions(ctx context.Context, guildID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countPendingSubmissions, guildID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const deleteGuild = `-- name: DeleteGuild :execrows
 DELETE FROM
 	known_guilds
@@ -38,6 +387,28 @@ func (q *Queries) DeleteGuild(ctx context.Context, guildID int64) (int64, error)
 	return result.RowsAffected(), nil
 }
 
+const deletePendingApproval = `-- name: DeletePendingApproval :execrows
+DELETE FROM
+	registration_approvals
+WHERE
+	guild_id = $1
+	AND user_id = $2
+	AND denied_at IS NULL
+`
+
+type DeletePendingApprovalParams struct {
+	GuildID int64
+	UserID  int64
+}
+
+func (q *Queries) DeletePendingApproval(ctx context.Context, arg DeletePendingApprovalParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePendingApproval, arg.GuildID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteSubmission = `-- name: DeleteSubmission :exec
 DELETE FROM
 	registration_submissions
@@ -56,9 +427,210 @@ func (q *Queries) DeleteSubmission(ctx context.Context, arg DeleteSubmissionPara
 	return err
 }
 
+const denyApproval = `-- name: DenyApproval :execrows
+UPDATE
+	registration_approvals
+SET
+	denied_at = $3,
+	denied_by = $4,
+	deny_reason = $5
+WHERE
+	guild_id = $1
+	AND user_id = $2
+	AND denied_at IS NULL
+`
+
+type DenyApprovalParams struct {
+	GuildID    int64
+	UserID     int64
+	DeniedAt   pgtype.Timestamptz
+	DeniedBy   pgtype.Int8
+	DenyReason string
+}
+
+func (q *Queries) DenyApproval(ctx context.Context, arg DenyApprovalParams) (int64, error) {
+	result, err := q.db.Exec(ctx, denyApproval,
+		arg.GuildID,
+		arg.UserID,
+		arg.DeniedAt,
+		arg.DeniedBy,
+		arg.DenyReason,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const eventAttendees = `-- name: EventAttendees :many
+SELECT
+	user_id,
+	checked_in_at
+FROM
+	event_attendance
+WHERE
+	guild_id = $1
+	AND event_id = $2
+ORDER BY
+	checked_in_at ASC,
+	user_id ASC
+`
+
+type EventAttendeesParams struct {
+	GuildID int64
+	EventID int64
+}
+
+type EventAttendeesRow struct {
+	UserID      int64
+	CheckedInAt pgtype.Timestamptz
+}
+
+func (q *Queries) EventAttendees(ctx context.Context, arg EventAttendeesParams) ([]EventAttendeesRow, error) {
+	rows, err := q.db.Query(ctx, eventAttendees, arg.GuildID, arg.EventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EventAttendeesRow
+	for rows.Next() {
+		var i EventAttendeesRow
+		if err := rows.Scan(&i.UserID, &i.CheckedInAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const eventInfo = `-- name: EventInfo :one
+SELECT
+	name,
+	start_time,
+	ended
+FROM
+	scheduled_events
+WHERE
+	guild_id = $1
+	AND event_id = $2
+`
+
+type EventInfoParams struct {
+	GuildID int64
+	EventID int64
+}
+
+type EventInfoRow struct {
+	Name      string
+	StartTime pgtype.Timestamptz
+	Ended     bool
+}
+
+func (q *Queries) EventInfo(ctx context.Context, arg EventInfoParams) (EventInfoRow, error) {
+	row := q.db.QueryRow(ctx, eventInfo, arg.GuildID, arg.EventID)
+	var i EventInfoRow
+	err := row.Scan(&i.Name, &i.StartTime, &i.Ended)
+	return i, err
+}
+
+const eventParticipants = `-- name: EventParticipants :many
+SELECT
+	user_id
+FROM
+	event_participants
+WHERE
+	guild_id = $1
+	AND event_id = $2
+ORDER BY
+	user_id ASC
+`
+
+type EventParticipantsParams struct {
+	GuildID int64
+	EventID int64
+}
+
+func (q *Queries) EventParticipants(ctx context.Context, arg EventParticipantsParams) ([]int64, error) {
+	rows, err := q.db.Query(ctx, eventParticipants, arg.GuildID, arg.EventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var user_id int64
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const eventsBetween = `-- name: EventsBetween :many
+SELECT
+	event_id,
+	name,
+	start_time,
+	ended
+FROM
+	scheduled_events
+WHERE
+	guild_id = $1
+	AND start_time >= $2
+	AND start_time < $3
+ORDER BY
+	start_time ASC,
+	event_id ASC
+`
+
+type EventsBetweenParams struct {
+	GuildID  int64
+	FromTime pgtype.Timestamptz
+	ToTime   pgtype.Timestamptz
+}
+
+type EventsBetweenRow struct {
+	EventID   int64
+	Name      string
+	StartTime pgtype.Timestamptz
+	Ended     bool
+}
+
+func (q *Queries) EventsBetween(ctx context.Context, arg EventsBetweenParams) ([]EventsBetweenRow, error) {
+	rows, err := q.db.Query(ctx, eventsBetween, arg.GuildID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EventsBetweenRow
+	for rows.Next() {
+		var i EventsBetweenRow
+		if err := rows.Scan(
+			&i.EventID,
+			&i.Name,
+			&i.StartTime,
+			&i.Ended,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const guildInfo = `-- name: GuildInfo :one
 SELECT
-	guild_id, channel_id, role_id, init_user_id, registered_message, admin_role_id
+	guild_id, channel_id, role_id, init_user_id, registered_message, admin_role_id, messages, register_message_id, form_fields, nickname, approval_channel_id, audit_channel_id
 FROM
 	known_guilds
 WHERE
@@ -77,6 +649,12 @@ func (q *Queries) GuildInfo(ctx context.Context, guildID int64) (KnownGuild, err
 		&i.InitUserID,
 		&i.RegisteredMessage,
 		&i.AdminRoleID,
+		&i.Messages,
+		&i.RegisterMessageID,
+		&i.FormFields,
+		&i.Nickname,
+		&i.ApprovalChannelID,
+		&i.AuditChannelID,
 	)
 	return i, err
 }
@@ -88,10 +666,16 @@ INSERT INTO
 		channel_id,
 		init_user_id,
 		role_id,
-		registered_message
+		registered_message,
+		messages,
+		register_message_id,
+		form_fields,
+		nickname,
+		approval_channel_id,
+		audit_channel_id
 	)
 VALUES
-	($1, $2, $3, $4, $5)
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 `
 
 type InitGuildParams struct {
@@ -100,6 +684,12 @@ type InitGuildParams struct {
 	InitUserID        int64
 	RoleID            int64
 	RegisteredMessage string
+	Messages          []byte
+	RegisterMessageID pgtype.Int8
+	FormFields        []byte
+	Nickname          []byte
+	ApprovalChannelID pgtype.Int8
+	AuditChannelID    pgtype.Int8
 }
 
 func (q *Queries) InitGuild(ctx context.Context, arg InitGuildParams) error {
@@ -109,6 +699,12 @@ func (q *Queries) InitGuild(ctx context.Context, arg InitGuildParams) error {
 		arg.InitUserID,
 		arg.RoleID,
 		arg.RegisteredMessage,
+		arg.Messages,
+		arg.RegisterMessageID,
+		arg.FormFields,
+		arg.Nickname,
+		arg.ApprovalChannelID,
+		arg.AuditChannelID,
 	)
 	return err
 }
@@ -124,15 +720,177 @@ SET
 	pin = $3
 `
 
-type InsertPINParams struct {
+type InsertPINParams struct {
+	GuildID int64
+	UserID  int64
+	Pin     int16
+}
+
+func (q *Queries) InsertPIN(ctx context.Context, arg InsertPINParams) error {
+	_, err := q.db.Exec(ctx, insertPIN, arg.GuildID, arg.UserID, arg.Pin)
+	return err
+}
+
+const listMembersAfter = `-- name: ListMembersAfter :many
+SELECT
+	user_id,
+	metadata,
+	registered_at,
+	registered_by,
+	departed_at
+FROM
+	members
+WHERE
+	guild_id = $1
+	AND user_id > $2
+ORDER BY
+	user_id ASC
+LIMIT
+	$3
+`
+
+type ListMembersAfterParams struct {
+	GuildID int64
+	UserID  int64
+	Limit   int32
+}
+
+type ListMembersAfterRow struct {
+	UserID       int64
+	Metadata     []byte
+	RegisteredAt pgtype.Timestamptz
+	RegisteredBy pgtype.Int8
+	DepartedAt   pgtype.Timestamptz
+}
+
+func (q *Queries) ListMembersAfter(ctx context.Context, arg ListMembersAfterParams) ([]ListMembersAfterRow, error) {
+	rows, err := q.db.Query(ctx, listMembersAfter, arg.GuildID, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMembersAfterRow
+	for rows.Next() {
+		var i ListMembersAfterRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Metadata,
+			&i.RegisteredAt,
+			&i.RegisteredBy,
+			&i.DepartedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMembersBefore = `-- name: ListMembersBefore :many
+SELECT
+	user_id,
+	metadata,
+	registered_at,
+	registered_by,
+	departed_at
+FROM
+	members
+WHERE
+	guild_id = $1
+	AND user_id < $2
+ORDER BY
+	user_id DESC
+LIMIT
+	$3
+`
+
+type ListMembersBeforeParams struct {
+	GuildID int64
+	UserID  int64
+	Limit   int32
+}
+
+type ListMembersBeforeRow struct {
+	UserID       int64
+	Metadata     []byte
+	RegisteredAt pgtype.Timestamptz
+	RegisteredBy pgtype.Int8
+	DepartedAt   pgtype.Timestamptz
+}
+
+func (q *Queries) ListMembersBefore(ctx context.Context, arg ListMembersBeforeParams) ([]ListMembersBeforeRow, error) {
+	rows, err := q.db.Query(ctx, listMembersBefore, arg.GuildID, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMembersBeforeRow
+	for rows.Next() {
+		var i ListMembersBeforeRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Metadata,
+			&i.RegisteredAt,
+			&i.RegisteredBy,
+			&i.DepartedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const memberEvents = `-- name: MemberEvents :many
+SELECT
+	type,
+	actor_id,
+	created_at
+FROM
+	member_events
+WHERE
+	guild_id = $1
+	AND user_id = $2
+ORDER BY
+	created_at ASC,
+	id ASC
+`
+
+type MemberEventsParams struct {
 	GuildID int64
 	UserID  int64
-	Pin     int16
 }
 
-func (q *Queries) InsertPIN(ctx context.Context, arg InsertPINParams) error {
-	_, err := q.db.Exec(ctx, insertPIN, arg.GuildID, arg.UserID, arg.Pin)
-	return err
+type MemberEventsRow struct {
+	Type      string
+	ActorID   pgtype.Int8
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) MemberEvents(ctx context.Context, arg MemberEventsParams) ([]MemberEventsRow, error) {
+	rows, err := q.db.Query(ctx, memberEvents, arg.GuildID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MemberEventsRow
+	for rows.Next() {
+		var i MemberEventsRow
+		if err := rows.Scan(&i.Type, &i.ActorID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const memberInfo = `-- name: MemberInfo :one
@@ -159,16 +917,18 @@ func (q *Queries) MemberInfo(ctx context.Context, arg MemberInfoParams) ([]byte,
 
 const registerMember = `-- name: RegisterMember :exec
 INSERT INTO
-	members (guild_id, user_id, email, metadata)
+	members (guild_id, user_id, email, metadata, registered_at, registered_by)
 VALUES
-	($1, $2, $3, $4)
+	($1, $2, $3, $4, $5, $6)
 `
 
 type RegisterMemberParams struct {
-	GuildID  int64
-	UserID   int64
-	Email    string
-	Metadata []byte
+	GuildID      int64
+	UserID       int64
+	Email        string
+	Metadata     []byte
+	RegisteredAt pgtype.Timestamptz
+	RegisteredBy pgtype.Int8
 }
 
 func (q *Queries) RegisterMember(ctx context.Context, arg RegisterMemberParams) error {
@@ -177,10 +937,32 @@ func (q *Queries) RegisterMember(ctx context.Context, arg RegisterMemberParams)
 		arg.UserID,
 		arg.Email,
 		arg.Metadata,
+		arg.RegisteredAt,
+		arg.RegisteredBy,
 	)
 	return err
 }
 
+const removeEventParticipant = `-- name: RemoveEventParticipant :exec
+DELETE FROM
+	event_participants
+WHERE
+	guild_id = $1
+	AND event_id = $2
+	AND user_id = $3
+`
+
+type RemoveEventParticipantParams struct {
+	GuildID int64
+	EventID int64
+	UserID  int64
+}
+
+func (q *Queries) RemoveEventParticipant(ctx context.Context, arg RemoveEventParticipantParams) error {
+	_, err := q.db.Exec(ctx, removeEventParticipant, arg.GuildID, arg.EventID, arg.UserID)
+	return err
+}
+
 const restoreSubmission = `-- name: RestoreSubmission :one
 SELECT
 	metadata
@@ -204,6 +986,38 @@ func (q *Queries) RestoreSubmission(ctx context.Context, arg RestoreSubmissionPa
 	return metadata, err
 }
 
+const saveEvent = `-- name: SaveEvent :exec
+INSERT INTO
+	scheduled_events (guild_id, event_id, name, start_time, ended)
+VALUES
+	($1, $2, $3, $4, $5) ON CONFLICT (guild_id, event_id)
+DO
+UPDATE
+SET
+	name = EXCLUDED.name,
+	start_time = EXCLUDED.start_time,
+	ended = EXCLUDED.ended
+`
+
+type SaveEventParams struct {
+	GuildID   int64
+	EventID   int64
+	Name      string
+	StartTime pgtype.Timestamptz
+	Ended     bool
+}
+
+func (q *Queries) SaveEvent(ctx context.Context, arg SaveEventParams) error {
+	_, err := q.db.Exec(ctx, saveEvent,
+		arg.GuildID,
+		arg.EventID,
+		arg.Name,
+		arg.StartTime,
+		arg.Ended,
+	)
+	return err
+}
+
 const saveSubmission = `-- name: SaveSubmission :exec
 INSERT INTO
 	registration_submissions (guild_id, user_id, metadata, expire_at)
@@ -227,6 +1041,136 @@ func (q *Queries) SaveSubmission(ctx context.Context, arg SaveSubmissionParams)
 	return err
 }
 
+const searchEvents = `-- name: SearchEvents :many
+SELECT
+	event_id,
+	name,
+	start_time,
+	ended
+FROM
+	scheduled_events
+WHERE
+	guild_id = $1
+	AND name ILIKE $2
+ORDER BY
+	start_time DESC,
+	event_id DESC
+LIMIT
+	$3
+`
+
+type SearchEventsParams struct {
+	GuildID    int64
+	Pattern    string
+	MaxResults int32
+}
+
+type SearchEventsRow struct {
+	EventID   int64
+	Name      string
+	StartTime pgtype.Timestamptz
+	Ended     bool
+}
+
+func (q *Queries) SearchEvents(ctx context.Context, arg SearchEventsParams) ([]SearchEventsRow, error) {
+	rows, err := q.db.Query(ctx, searchEvents, arg.GuildID, arg.Pattern, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchEventsRow
+	for rows.Next() {
+		var i SearchEventsRow
+		if err := rows.Scan(
+			&i.EventID,
+			&i.Name,
+			&i.StartTime,
+			&i.Ended,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchMembers = `-- name: SearchMembers :many
+SELECT
+	user_id,
+	metadata,
+	registered_at,
+	registered_by,
+	departed_at
+FROM
+	members
+WHERE
+	guild_id = $1
+	-- Match against "email\nfirst last", so that the full name can also be
+	-- searched. Old rows have their metadata nested in a Metadata object.
+	AND CONCAT_WS(
+		E'\n',
+		email,
+		CONCAT_WS(
+			' ',
+			COALESCE(
+				metadata ->> 'first_name',
+				metadata -> 'Metadata' ->> 'first_name'
+			),
+			COALESCE(
+				metadata ->> 'last_name',
+				metadata -> 'Metadata' ->> 'last_name'
+			)
+		)
+	) ILIKE $2
+ORDER BY
+	user_id ASC
+LIMIT
+	$3
+`
+
+type SearchMembersParams struct {
+	GuildID    int64
+	Pattern    string
+	MaxResults int32
+}
+
+type SearchMembersRow struct {
+	UserID       int64
+	Metadata     []byte
+	RegisteredAt pgtype.Timestamptz
+	RegisteredBy pgtype.Int8
+	DepartedAt   pgtype.Timestamptz
+}
+
+func (q *Queries) SearchMembers(ctx context.Context, arg SearchMembersParams) ([]SearchMembersRow, error) {
+	rows, err := q.db.Query(ctx, searchMembers, arg.GuildID, arg.Pattern, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchMembersRow
+	for rows.Next() {
+		var i SearchMembersRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Metadata,
+			&i.RegisteredAt,
+			&i.RegisteredBy,
+			&i.DepartedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const setGuildAdminRoleID = `-- name: SetGuildAdminRoleID :execrows
 UPDATE
 	known_guilds
@@ -249,6 +1193,220 @@ func (q *Queries) SetGuildAdminRoleID(ctx context.Context, arg SetGuildAdminRole
 	return result.RowsAffected(), nil
 }
 
+const setGuildApprovalChannelID = `-- name: SetGuildApprovalChannelID :execrows
+UPDATE
+	known_guilds
+SET
+	approval_channel_id = $2
+WHERE
+	guild_id = $1
+`
+
+type SetGuildApprovalChannelIDParams struct {
+	GuildID           int64
+	ApprovalChannelID pgtype.Int8
+}
+
+func (q *Queries) SetGuildApprovalChannelID(ctx context.Context, arg SetGuildApprovalChannelIDParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setGuildApprovalChannelID, arg.GuildID, arg.ApprovalChannelID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setGuildAuditChannelID = `-- name: SetGuildAuditChannelID :execrows
+UPDATE
+	known_guilds
+SET
+	audit_channel_id = $2
+WHERE
+	guild_id = $1
+`
+
+type SetGuildAuditChannelIDParams struct {
+	GuildID        int64
+	AuditChannelID pgtype.Int8
+}
+
+func (q *Queries) SetGuildAuditChannelID(ctx context.Context, arg SetGuildAuditChannelIDParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setGuildAuditChannelID, arg.GuildID, arg.AuditChannelID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setGuildFormFields = `-- name: SetGuildFormFields :execrows
+UPDATE
+	known_guilds
+SET
+	form_fields = $2
+WHERE
+	guild_id = $1
+`
+
+type SetGuildFormFieldsParams struct {
+	GuildID    int64
+	FormFields []byte
+}
+
+func (q *Queries) SetGuildFormFields(ctx context.Context, arg SetGuildFormFieldsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setGuildFormFields, arg.GuildID, arg.FormFields)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setGuildMessages = `-- name: SetGuildMessages :execrows
+UPDATE
+	known_guilds
+SET
+	registered_message = $2,
+	messages = $3
+WHERE
+	guild_id = $1
+`
+
+type SetGuildMessagesParams struct {
+	GuildID           int64
+	RegisteredMessage string
+	Messages          []byte
+}
+
+func (q *Queries) SetGuildMessages(ctx context.Context, arg SetGuildMessagesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setGuildMessages, arg.GuildID, arg.RegisteredMessage, arg.Messages)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setGuildNickname = `-- name: SetGuildNickname :execrows
+UPDATE
+	known_guilds
+SET
+	nickname = $2
+WHERE
+	guild_id = $1
+`
+
+type SetGuildNicknameParams struct {
+	GuildID  int64
+	Nickname []byte
+}
+
+func (q *Queries) SetGuildNickname(ctx context.Context, arg SetGuildNicknameParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setGuildNickname, arg.GuildID, arg.Nickname)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setGuildRegisterMessage = `-- name: SetGuildRegisterMessage :execrows
+UPDATE
+	known_guilds
+SET
+	channel_id = $2,
+	register_message_id = $3
+WHERE
+	guild_id = $1
+`
+
+type SetGuildRegisterMessageParams struct {
+	GuildID           int64
+	ChannelID         int64
+	RegisterMessageID pgtype.Int8
+}
+
+func (q *Queries) SetGuildRegisterMessage(ctx context.Context, arg SetGuildRegisterMessageParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setGuildRegisterMessage, arg.GuildID, arg.ChannelID, arg.RegisterMessageID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setGuildRoleID = `-- name: SetGuildRoleID :execrows
+UPDATE
+	known_guilds
+SET
+	role_id = $2
+WHERE
+	guild_id = $1
+`
+
+type SetGuildRoleIDParams struct {
+	GuildID int64
+	RoleID  int64
+}
+
+func (q *Queries) SetGuildRoleID(ctx context.Context, arg SetGuildRoleIDParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setGuildRoleID, arg.GuildID, arg.RoleID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setMemberDepartedAt = `-- name: SetMemberDepartedAt :execrows
+UPDATE
+	members
+SET
+	departed_at = $3
+WHERE
+	guild_id = $1
+	AND user_id = $2
+`
+
+type SetMemberDepartedAtParams struct {
+	GuildID    int64
+	UserID     int64
+	DepartedAt pgtype.Timestamptz
+}
+
+func (q *Queries) SetMemberDepartedAt(ctx context.Context, arg SetMemberDepartedAtParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setMemberDepartedAt, arg.GuildID, arg.UserID, arg.DepartedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const submitApproval = `-- name: SubmitApproval :exec
+INSERT INTO
+	registration_approvals (guild_id, user_id, metadata, submitted_at)
+VALUES
+	($1, $2, $3, $4) ON CONFLICT (guild_id, user_id)
+DO
+UPDATE
+SET
+	metadata = EXCLUDED.metadata,
+	submitted_at = EXCLUDED.submitted_at,
+	denied_at = NULL,
+	denied_by = NULL,
+	deny_reason = ''
+`
+
+type SubmitApprovalParams struct {
+	GuildID     int64
+	UserID      int64
+	Metadata    []byte
+	SubmittedAt pgtype.Timestamptz
+}
+
+func (q *Queries) SubmitApproval(ctx context.Context, arg SubmitApprovalParams) error {
+	_, err := q.db.Exec(ctx, submitApproval,
+		arg.GuildID,
+		arg.UserID,
+		arg.Metadata,
+		arg.SubmittedAt,
+	)
+	return err
+}
+
 const unregisterMember = `-- name: UnregisterMember :execrows
 DELETE FROM
 	members
@@ -270,6 +1428,37 @@ func (q *Queries) UnregisterMember(ctx context.Context, arg UnregisterMemberPara
 	return result.RowsAffected(), nil
 }
 
+const updateMemberMetadata = `-- name: UpdateMemberMetadata :execrows
+UPDATE
+	members
+SET
+	email = $3,
+	metadata = $4
+WHERE
+	guild_id = $1
+	AND user_id = $2
+`
+
+type UpdateMemberMetadataParams struct {
+	GuildID  int64
+	UserID   int64
+	Email    string
+	Metadata []byte
+}
+
+func (q *Queries) UpdateMemberMetadata(ctx context.Context, arg UpdateMemberMetadataParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateMemberMetadata,
+		arg.GuildID,
+		arg.UserID,
+		arg.Email,
+		arg.Metadata,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const validatePIN = `-- name: ValidatePIN :one
 SELECT
 	registration_submissions.metadata
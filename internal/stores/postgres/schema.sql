@@ -63,3 +63,230 @@ ALTER TABLE
 	known_guilds
 ADD COLUMN
 	admin_role_id BIGINT;
+
+-- NEW VERSION
+UPDATE
+	meta
+SET
+	v = 4;
+
+-- Add the registered_at column to the members table. Older members don't have
+-- a known registration time.
+ALTER TABLE
+	members
+ADD COLUMN
+	registered_at TIMESTAMPTZ;
+
+-- NEW VERSION
+UPDATE
+	meta
+SET
+	v = 5;
+
+-- Add the registered_by column to the members table. It is NULL for members who
+-- registered themselves.
+ALTER TABLE
+	members
+ADD COLUMN
+	registered_by BIGINT;
+
+-- NEW VERSION
+UPDATE
+	meta
+SET
+	v = 6;
+
+-- Add the departed_at column to the members table. It is set when the member
+-- leaves the guild and cleared when they rejoin.
+ALTER TABLE
+	members
+ADD COLUMN
+	departed_at TIMESTAMPTZ;
+
+-- NEW VERSION
+UPDATE
+	meta
+SET
+	v = 7;
+
+-- Add the messages column to the known_guilds table. It holds the guild's
+-- customized messages; missing ones use the defaults.
+ALTER TABLE
+	known_guilds
+ADD COLUMN
+	messages JSONB NOT NULL DEFAULT '{}';
+
+-- NEW VERSION
+UPDATE
+	meta
+SET
+	v = 8;
+
+-- Add the register_message_id column to the known_guilds table. It is NULL for
+-- guilds that posted their register message before this was recorded.
+ALTER TABLE
+	known_guilds
+ADD COLUMN
+	register_message_id BIGINT;
+
+-- NEW VERSION
+UPDATE
+	meta
+SET
+	v = 9;
+
+-- Add the form_fields column to the known_guilds table. It holds the guild's
+-- custom registration form fields.
+ALTER TABLE
+	known_guilds
+ADD COLUMN
+	form_fields JSONB NOT NULL DEFAULT '[]';
+
+-- NEW VERSION
+UPDATE
+	meta
+SET
+	v = 10;
+
+-- Add the nickname column to the known_guilds table. It holds how the guild
+-- nicknames its registered members; an empty object uses the defaults.
+ALTER TABLE
+	known_guilds
+ADD COLUMN
+	nickname JSONB NOT NULL DEFAULT '{}';
+
+-- NEW VERSION
+UPDATE
+	meta
+SET
+	v = 11;
+
+-- Add the approval_channel_id column to the known_guilds table. Registrations
+-- need approval if it is set.
+ALTER TABLE
+	known_guilds
+ADD COLUMN
+	approval_channel_id BIGINT;
+
+-- Registrations waiting for approval. Denied registrations are kept until the
+-- member registers again.
+CREATE TABLE
+	registration_approvals (
+		guild_id BIGINT NOT NULL REFERENCES known_guilds(guild_id) ON DELETE CASCADE,
+		user_id BIGINT NOT NULL,
+		metadata JSONB NOT NULL,
+		submitted_at TIMESTAMPTZ NOT NULL,
+		denied_at TIMESTAMPTZ,
+		denied_by BIGINT,
+		deny_reason TEXT NOT NULL DEFAULT '',
+		UNIQUE(guild_id, user_id)
+	);
+
+-- NEW VERSION
+UPDATE
+	meta
+SET
+	v = 12;
+
+-- Add the audit_channel_id column to the known_guilds table. Changes to the
+-- registration are logged to it if it is set.
+ALTER TABLE
+	known_guilds
+ADD COLUMN
+	audit_channel_id BIGINT;
+
+-- NEW VERSION
+UPDATE
+	meta
+SET
+	v = 13;
+
+-- The append-only history of registration events of each member. It is kept
+-- after the member is unregistered.
+CREATE TABLE
+	member_events (
+		id BIGSERIAL PRIMARY KEY,
+		guild_id BIGINT NOT NULL REFERENCES known_guilds(guild_id) ON DELETE CASCADE,
+		user_id BIGINT NOT NULL,
+		type TEXT NOT NULL,
+		actor_id BIGINT,
+		created_at TIMESTAMPTZ NOT NULL
+	);
+
+CREATE INDEX
+	member_events_member ON member_events (guild_id, user_id);
+
+-- NEW VERSION
+UPDATE
+	meta
+SET
+	v = 14;
+
+-- Every attempt to verify a PIN, for computing the verification success rate.
+CREATE TABLE
+	pin_attempts (
+		guild_id BIGINT NOT NULL REFERENCES known_guilds(guild_id) ON DELETE CASCADE,
+		user_id BIGINT NOT NULL,
+		success BOOLEAN NOT NULL,
+		attempted_at TIMESTAMPTZ NOT NULL
+	);
+
+CREATE INDEX
+	pin_attempts_guild ON pin_attempts (guild_id, attempted_at);
+
+-- NEW VERSION
+UPDATE
+	meta
+SET
+	v = 15;
+
+-- Members that checked in to scheduled events.
+CREATE TABLE
+	event_attendance (
+		guild_id BIGINT NOT NULL REFERENCES known_guilds(guild_id) ON DELETE CASCADE,
+		event_id BIGINT NOT NULL,
+		user_id BIGINT NOT NULL,
+		checked_in_at TIMESTAMPTZ NOT NULL,
+		UNIQUE(guild_id, event_id, user_id)
+	);
+
+-- NEW VERSION
+UPDATE
+	meta
+SET
+	v = 16;
+
+-- Snapshots of scheduled events, which Discord forgets some time after they
+-- end.
+CREATE TABLE
+	scheduled_events (
+		guild_id BIGINT NOT NULL REFERENCES known_guilds(guild_id) ON DELETE CASCADE,
+		event_id BIGINT NOT NULL,
+		name TEXT NOT NULL,
+		start_time TIMESTAMPTZ NOT NULL,
+		ended BOOLEAN NOT NULL,
+		UNIQUE(guild_id, event_id)
+	);
+
+-- Users interested in scheduled events. Users may be added before the event
+-- itself is saved, so this doesn't reference scheduled_events.
+CREATE TABLE
+	event_participants (
+		guild_id BIGINT NOT NULL REFERENCES known_guilds(guild_id) ON DELETE CASCADE,
+		event_id BIGINT NOT NULL,
+		user_id BIGINT NOT NULL,
+		UNIQUE(guild_id, event_id, user_id)
+	);
+
+-- NEW VERSION
+UPDATE
+	meta
+SET
+	v = 17;
+
+-- Keep the member history after the guild is deleted, so that clearing the
+-- registration doesn't lose it.
+ALTER TABLE
+	member_events
+DROP CONSTRAINT
+	member_events_guild_id_fkey;
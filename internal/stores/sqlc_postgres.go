@@ -56,12 +56,33 @@ func (s pgStore) WithContext(ctx context.Context) acmregister.ContainsContext {
 }
 
 func (s pgStore) InitGuild(guild acmregister.KnownGuild) error {
+	messages, err := json.Marshal(guild.Messages)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode guild messages as JSON")
+	}
+
+	formFields, err := marshalFormFields(guild.FormFields)
+	if err != nil {
+		return err
+	}
+
+	nickname, err := json.Marshal(guild.Nickname)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode guild nickname settings as JSON")
+	}
+
 	return s.q.InitGuild(s.ctx, postgres.InitGuildParams{
 		GuildID:           int64(guild.GuildID),
 		ChannelID:         int64(guild.ChannelID),
 		RoleID:            int64(guild.RoleID),
 		InitUserID:        int64(guild.InitUserID),
 		RegisteredMessage: guild.RegisteredMessage,
+		Messages:          messages,
+		RegisterMessageID: pgtype.Int8{Int64: int64(guild.RegisterMessageID), Valid: guild.RegisterMessageID.IsValid()},
+		FormFields:        formFields,
+		Nickname:          nickname,
+		ApprovalChannelID: pgtype.Int8{Int64: int64(guild.ApprovalChannelID), Valid: guild.ApprovalChannelID.IsValid()},
+		AuditChannelID:    pgtype.Int8{Int64: int64(guild.AuditChannelID), Valid: guild.AuditChannelID.IsValid()},
 	})
 }
 
@@ -71,6 +92,21 @@ func (s pgStore) GuildInfo(guildID discord.GuildID) (*acmregister.KnownGuild, er
 		return nil, postgresErr(err)
 	}
 
+	var messages acmregister.GuildMessages
+	if err := json.Unmarshal([]byte(v.Messages), &messages); err != nil {
+		return nil, errors.Wrap(err, "cannot decode guild messages")
+	}
+
+	formFields, err := unmarshalFormFields([]byte(v.FormFields))
+	if err != nil {
+		return nil, err
+	}
+
+	var nickname acmregister.NicknameSettings
+	if err := json.Unmarshal([]byte(v.Nickname), &nickname); err != nil {
+		return nil, errors.Wrap(err, "cannot decode guild nickname settings")
+	}
+
 	return &acmregister.KnownGuild{
 		GuildID:           discord.GuildID(v.GuildID),
 		ChannelID:         discord.ChannelID(v.ChannelID),
@@ -78,6 +114,12 @@ func (s pgStore) GuildInfo(guildID discord.GuildID) (*acmregister.KnownGuild, er
 		InitUserID:        discord.UserID(v.InitUserID),
 		RegisteredMessage: v.RegisteredMessage,
 		AdminRoleID:       discord.RoleID(v.AdminRoleID.Int64),
+		RegisterMessageID: discord.MessageID(v.RegisterMessageID.Int64),
+		Messages:          messages,
+		FormFields:        formFields,
+		Nickname:          nickname,
+		ApprovalChannelID: discord.ChannelID(v.ApprovalChannelID.Int64),
+		AuditChannelID:    discord.ChannelID(v.AuditChannelID.Int64),
 	}, nil
 }
 
@@ -95,6 +137,121 @@ func (s pgStore) GuildSetAdminRole(guildID discord.GuildID, roleID discord.RoleI
 	return nil
 }
 
+func (s pgStore) GuildSetRole(guildID discord.GuildID, roleID discord.RoleID) error {
+	n, err := s.q.SetGuildRoleID(s.ctx, postgres.SetGuildRoleIDParams{
+		GuildID: int64(guildID),
+		RoleID:  int64(roleID),
+	})
+	if err != nil {
+		return postgresErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s pgStore) GuildSetRegisterMessage(guildID discord.GuildID, channelID discord.ChannelID, messageID discord.MessageID) error {
+	n, err := s.q.SetGuildRegisterMessage(s.ctx, postgres.SetGuildRegisterMessageParams{
+		GuildID:           int64(guildID),
+		ChannelID:         int64(channelID),
+		RegisterMessageID: pgtype.Int8{Int64: int64(messageID), Valid: messageID.IsValid()},
+	})
+	if err != nil {
+		return postgresErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s pgStore) GuildSetMessages(guildID discord.GuildID, registeredMessage string, msgs acmregister.GuildMessages) error {
+	messages, err := json.Marshal(msgs)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode guild messages as JSON")
+	}
+
+	n, err := s.q.SetGuildMessages(s.ctx, postgres.SetGuildMessagesParams{
+		GuildID:           int64(guildID),
+		RegisteredMessage: registeredMessage,
+		Messages:          messages,
+	})
+	if err != nil {
+		return postgresErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s pgStore) GuildSetFormFields(guildID discord.GuildID, fields []acmregister.FormField) error {
+	formFields, err := marshalFormFields(fields)
+	if err != nil {
+		return err
+	}
+
+	n, err := s.q.SetGuildFormFields(s.ctx, postgres.SetGuildFormFieldsParams{
+		GuildID:    int64(guildID),
+		FormFields: formFields,
+	})
+	if err != nil {
+		return postgresErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s pgStore) GuildSetNickname(guildID discord.GuildID, settings acmregister.NicknameSettings) error {
+	nickname, err := json.Marshal(settings)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode guild nickname settings as JSON")
+	}
+
+	n, err := s.q.SetGuildNickname(s.ctx, postgres.SetGuildNicknameParams{
+		GuildID:  int64(guildID),
+		Nickname: nickname,
+	})
+	if err != nil {
+		return postgresErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s pgStore) GuildSetApprovalChannel(guildID discord.GuildID, channelID discord.ChannelID) error {
+	n, err := s.q.SetGuildApprovalChannelID(s.ctx, postgres.SetGuildApprovalChannelIDParams{
+		GuildID:           int64(guildID),
+		ApprovalChannelID: pgtype.Int8{Int64: int64(channelID), Valid: channelID.IsValid()},
+	})
+	if err != nil {
+		return postgresErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s pgStore) GuildSetAuditChannel(guildID discord.GuildID, channelID discord.ChannelID) error {
+	n, err := s.q.SetGuildAuditChannelID(s.ctx, postgres.SetGuildAuditChannelIDParams{
+		GuildID:        int64(guildID),
+		AuditChannelID: pgtype.Int8{Int64: int64(channelID), Valid: channelID.IsValid()},
+	})
+	if err != nil {
+		return postgresErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
 func (s pgStore) DeleteGuild(guildID discord.GuildID) error {
 	n, err := s.q.DeleteGuild(s.ctx, int64(guildID))
 	if err != nil {
@@ -115,27 +272,7 @@ func (s pgStore) MemberInfo(guildID discord.GuildID, userID discord.UserID) (*ac
 		return nil, postgresErr(err)
 	}
 
-	var metadata acmregister.MemberMetadata
-	if err := json.Unmarshal(b, &metadata); err != nil {
-		return nil, errors.Wrap(err, "member metadata JSON is corrupted")
-	}
-
-	if metadata == (acmregister.MemberMetadata{}) {
-		// We used to have a bug where [acmregister.Member] was used for
-		// marshaling, so we have to try and fix that.
-		var memberFix struct {
-			Metadata acmregister.MemberMetadata
-		}
-		if err := json.Unmarshal(b, &memberFix); err != nil {
-			return nil, errors.Wrap(err, "member metadata JSON is corrupted")
-		}
-		if memberFix.Metadata == (acmregister.MemberMetadata{}) {
-			return nil, errors.New("member metadata is empty")
-		}
-		return &memberFix.Metadata, nil
-	}
-
-	return &metadata, nil
+	return unmarshalMemberMetadata(b)
 }
 
 func (s pgStore) RegisterMember(m acmregister.Member) error {
@@ -157,6 +294,14 @@ func (s pgStore) RegisterMember(m acmregister.Member) error {
 		UserID:   int64(m.UserID),
 		Email:    string(m.Metadata.Email),
 		Metadata: pgMetadata,
+		RegisteredAt: pgtype.Timestamptz{
+			Time:  registeredAt(m),
+			Valid: true,
+		},
+		RegisteredBy: pgtype.Int8{
+			Int64: int64(m.RegisteredBy),
+			Valid: m.RegisteredBy.IsValid(),
+		},
 	}); err != nil {
 		if postgres.IsConstraintFailed(err) {
 			return acmregister.ErrMemberAlreadyExists
@@ -164,6 +309,10 @@ func (s pgStore) RegisterMember(m acmregister.Member) error {
 		return postgresErr(err)
 	}
 
+	if err := pgAddMemberEvent(s.ctx, q, registeredEvent(m)); err != nil {
+		return err
+	}
+
 	q.DeleteSubmission(s.ctx, postgres.DeleteSubmissionParams{
 		GuildID: int64(m.GuildID),
 		UserID:  int64(m.UserID),
@@ -176,8 +325,82 @@ func (s pgStore) RegisterMember(m acmregister.Member) error {
 	return nil
 }
 
-func (s pgStore) UnregisterMember(guildID discord.GuildID, userID discord.UserID) error {
-	n, err := s.q.UnregisterMember(s.ctx, postgres.UnregisterMemberParams{
+func (s pgStore) UpdateMemberMetadata(guildID discord.GuildID, userID discord.UserID, metadata acmregister.MemberMetadata) error {
+	pgMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode member metadata as JSON")
+	}
+
+	tx, err := s.db.Begin(s.ctx)
+	if err != nil {
+		return postgresErr(err)
+	}
+	defer tx.Rollback(s.ctx)
+
+	q := postgres.New(tx)
+
+	n, err := q.UpdateMemberMetadata(s.ctx, postgres.UpdateMemberMetadataParams{
+		GuildID:  int64(guildID),
+		UserID:   int64(userID),
+		Email:    string(metadata.Email),
+		Metadata: pgMetadata,
+	})
+	if err != nil {
+		if postgres.IsConstraintFailed(err) {
+			return acmregister.ErrMemberAlreadyExists
+		}
+		return postgresErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+
+	if err := pgAddMemberEvent(s.ctx, q, acmregister.MemberEvent{
+		GuildID: guildID,
+		UserID:  userID,
+		Type:    acmregister.MemberEdited,
+		ActorID: userID,
+	}); err != nil {
+		return err
+	}
+
+	q.DeleteSubmission(s.ctx, postgres.DeleteSubmissionParams{
+		GuildID: int64(guildID),
+		UserID:  int64(userID),
+	})
+
+	if err := tx.Commit(s.ctx); err != nil {
+		return postgresErr(err)
+	}
+
+	return nil
+}
+
+func (s pgStore) SetMemberDeparted(guildID discord.GuildID, userID discord.UserID, at time.Time) error {
+	n, err := s.q.SetMemberDepartedAt(s.ctx, postgres.SetMemberDepartedAtParams{
+		GuildID:    int64(guildID),
+		UserID:     int64(userID),
+		DepartedAt: pgtype.Timestamptz{Time: at, Valid: !at.IsZero()},
+	})
+	if err != nil {
+		return postgresErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s pgStore) UnregisterMember(guildID discord.GuildID, userID, actorID discord.UserID) error {
+	tx, err := s.db.Begin(s.ctx)
+	if err != nil {
+		return postgresErr(err)
+	}
+	defer tx.Rollback(s.ctx)
+
+	q := postgres.New(tx)
+
+	n, err := q.UnregisterMember(s.ctx, postgres.UnregisterMemberParams{
 		GuildID: int64(guildID),
 		UserID:  int64(userID),
 	})
@@ -187,9 +410,120 @@ func (s pgStore) UnregisterMember(guildID discord.GuildID, userID discord.UserID
 	if n == 0 {
 		return acmregister.ErrNotFound
 	}
+
+	if err := pgAddMemberEvent(s.ctx, q, acmregister.MemberEvent{
+		GuildID: guildID,
+		UserID:  userID,
+		Type:    acmregister.MemberUnregistered,
+		ActorID: actorID,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(s.ctx); err != nil {
+		return postgresErr(err)
+	}
+
+	return nil
+}
+
+func (s pgStore) AddMemberEvent(ev acmregister.MemberEvent) error {
+	return pgAddMemberEvent(s.ctx, s.q, ev)
+}
+
+func (s pgStore) MemberEvents(guildID discord.GuildID, userID discord.UserID) ([]acmregister.MemberEvent, error) {
+	rows, err := s.q.MemberEvents(s.ctx, postgres.MemberEventsParams{
+		GuildID: int64(guildID),
+		UserID:  int64(userID),
+	})
+	if err != nil {
+		return nil, postgresErr(err)
+	}
+
+	events := make([]acmregister.MemberEvent, len(rows))
+	for i, row := range rows {
+		events[i] = acmregister.MemberEvent{
+			GuildID: guildID,
+			UserID:  userID,
+			Type:    acmregister.MemberEventType(row.Type),
+			ActorID: discord.UserID(row.ActorID.Int64),
+			Time:    row.CreatedAt.Time,
+		}
+	}
+
+	return events, nil
+}
+
+func pgAddMemberEvent(ctx context.Context, q *postgres.Queries, ev acmregister.MemberEvent) error {
+	if err := q.AddMemberEvent(ctx, postgres.AddMemberEventParams{
+		GuildID: int64(ev.GuildID),
+		UserID:  int64(ev.UserID),
+		Type:    string(ev.Type),
+		ActorID: pgtype.Int8{
+			Int64: int64(ev.ActorID),
+			Valid: ev.ActorID.IsValid(),
+		},
+		CreatedAt: pgtype.Timestamptz{
+			Time:  eventTime(ev),
+			Valid: true,
+		},
+	}); err != nil {
+		return postgresErr(err)
+	}
 	return nil
 }
 
+func (s pgStore) ListMembers(guildID discord.GuildID, cursor acmregister.MemberCursor, limit int) ([]acmregister.Member, error) {
+	var rows []pgMemberRow
+
+	if cursor.Before {
+		v, err := s.q.ListMembersBefore(s.ctx, postgres.ListMembersBeforeParams{
+			GuildID: int64(guildID),
+			UserID:  int64(cursor.UserID),
+			Limit:   int32(limit),
+		})
+		if err != nil {
+			return nil, postgresErr(err)
+		}
+		// Rows are in descending order, so flip them back.
+		for i := len(v) - 1; i >= 0; i-- {
+			rows = append(rows, pgMemberRow(v[i]))
+		}
+	} else {
+		v, err := s.q.ListMembersAfter(s.ctx, postgres.ListMembersAfterParams{
+			GuildID: int64(guildID),
+			UserID:  int64(cursor.UserID),
+			Limit:   int32(limit),
+		})
+		if err != nil {
+			return nil, postgresErr(err)
+		}
+		for _, r := range v {
+			rows = append(rows, pgMemberRow(r))
+		}
+	}
+
+	return pgMembers(guildID, rows)
+}
+
+func (s pgStore) SearchMembers(guildID discord.GuildID, query string, limit int) ([]acmregister.Member, error) {
+	v, err := s.q.SearchMembers(s.ctx, postgres.SearchMembersParams{
+		GuildID:    int64(guildID),
+		Pattern:    containsPattern(query),
+		MaxResults: int32(limit),
+	})
+	if err != nil {
+		return nil, postgresErr(err)
+	}
+
+	rows := make([]pgMemberRow, len(v))
+	for i, r := range v {
+		rows[i] = pgMemberRow(r)
+	}
+
+	return pgMembers(guildID, rows)
+}
+
 func (s pgStore) SaveSubmission(m acmregister.Member) error {
 	pgMetadata, err := json.Marshal(m.Metadata)
 	if err != nil {
@@ -218,12 +552,327 @@ func (s pgStore) RestoreSubmission(guildID discord.GuildID, userID discord.UserI
 		return nil, postgresErr(err)
 	}
 
-	var metadata acmregister.MemberMetadata
-	if err := json.Unmarshal(b, &metadata); err != nil {
-		return nil, errors.Wrap(err, "member metadata JSON is corrupted")
+	return unmarshalMetadata(b)
+}
+
+func (s pgStore) SubmitApproval(a acmregister.Approval) error {
+	pgMetadata, err := json.Marshal(a.Metadata)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode member metadata as JSON")
 	}
 
-	return &metadata, nil
+	tx, err := s.db.Begin(s.ctx)
+	if err != nil {
+		return postgresErr(err)
+	}
+	defer tx.Rollback(s.ctx)
+
+	q := postgres.New(tx)
+
+	if err := q.SubmitApproval(s.ctx, postgres.SubmitApprovalParams{
+		GuildID:     int64(a.GuildID),
+		UserID:      int64(a.UserID),
+		Metadata:    pgMetadata,
+		SubmittedAt: pgtype.Timestamptz{Time: submittedAt(a), Valid: true},
+	}); err != nil {
+		return postgresErr(err)
+	}
+
+	q.DeleteSubmission(s.ctx, postgres.DeleteSubmissionParams{
+		GuildID: int64(a.GuildID),
+		UserID:  int64(a.UserID),
+	})
+
+	if err := tx.Commit(s.ctx); err != nil {
+		return postgresErr(err)
+	}
+
+	return nil
+}
+
+func (s pgStore) ApprovalInfo(guildID discord.GuildID, userID discord.UserID) (*acmregister.Approval, error) {
+	v, err := s.q.ApprovalInfo(s.ctx, postgres.ApprovalInfoParams{
+		GuildID: int64(guildID),
+		UserID:  int64(userID),
+	})
+	if err != nil {
+		return nil, postgresErr(err)
+	}
+
+	metadata, err := unmarshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	approval := &acmregister.Approval{
+		GuildID:     guildID,
+		UserID:      userID,
+		Metadata:    *metadata,
+		SubmittedAt: v.SubmittedAt.Time,
+		DeniedBy:    discord.UserID(v.DeniedBy.Int64),
+		DenyReason:  v.DenyReason,
+	}
+	if v.DeniedAt.Valid {
+		approval.DeniedAt = v.DeniedAt.Time
+	}
+
+	return approval, nil
+}
+
+func (s pgStore) ApproveMember(guildID discord.GuildID, userID, approvedBy discord.UserID) (*acmregister.MemberMetadata, error) {
+	tx, err := s.db.Begin(s.ctx)
+	if err != nil {
+		return nil, postgresErr(err)
+	}
+	defer tx.Rollback(s.ctx)
+
+	q := postgres.New(tx)
+
+	v, err := q.ApprovalInfo(s.ctx, postgres.ApprovalInfoParams{
+		GuildID: int64(guildID),
+		UserID:  int64(userID),
+	})
+	if err != nil {
+		return nil, postgresErr(err)
+	}
+
+	n, err := q.DeletePendingApproval(s.ctx, postgres.DeletePendingApprovalParams{
+		GuildID: int64(guildID),
+		UserID:  int64(userID),
+	})
+	if err != nil {
+		return nil, postgresErr(err)
+	}
+	if n == 0 {
+		// The approval has been denied.
+		return nil, acmregister.ErrNotFound
+	}
+
+	metadata, err := unmarshalMetadata(v.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if err := q.RegisterMember(s.ctx, postgres.RegisterMemberParams{
+		GuildID:      int64(guildID),
+		UserID:       int64(userID),
+		Email:        string(metadata.Email),
+		Metadata:     v.Metadata,
+		RegisteredAt: pgtype.Timestamptz{Time: now, Valid: true},
+	}); err != nil {
+		if postgres.IsConstraintFailed(err) {
+			return nil, acmregister.ErrMemberAlreadyExists
+		}
+		return nil, postgresErr(err)
+	}
+
+	if err := pgAddMemberEvent(s.ctx, q, acmregister.MemberEvent{
+		GuildID: guildID,
+		UserID:  userID,
+		Type:    acmregister.MemberRegistered,
+		ActorID: approvedBy,
+		Time:    now,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(s.ctx); err != nil {
+		return nil, postgresErr(err)
+	}
+
+	return metadata, nil
+}
+
+func (s pgStore) DenyMember(guildID discord.GuildID, userID, deniedBy discord.UserID, reason string) error {
+	n, err := s.q.DenyApproval(s.ctx, postgres.DenyApprovalParams{
+		GuildID:    int64(guildID),
+		UserID:     int64(userID),
+		DeniedAt:   pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		DeniedBy:   pgtype.Int8{Int64: int64(deniedBy), Valid: deniedBy.IsValid()},
+		DenyReason: reason,
+	})
+	if err != nil {
+		return postgresErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s pgStore) CheckIn(a acmregister.Attendance) error {
+	if err := s.q.CheckIn(s.ctx, postgres.CheckInParams{
+		GuildID: int64(a.GuildID),
+		EventID: int64(a.EventID),
+		UserID:  int64(a.UserID),
+		CheckedInAt: pgtype.Timestamptz{
+			Time:  checkedInAt(a),
+			Valid: true,
+		},
+	}); err != nil {
+		if postgres.IsConstraintFailed(err) {
+			return acmregister.ErrAlreadyCheckedIn
+		}
+		return postgresErr(err)
+	}
+	return nil
+}
+
+func (s pgStore) EventAttendees(guildID discord.GuildID, eventID discord.EventID) ([]acmregister.Attendance, error) {
+	rows, err := s.q.EventAttendees(s.ctx, postgres.EventAttendeesParams{
+		GuildID: int64(guildID),
+		EventID: int64(eventID),
+	})
+	if err != nil {
+		return nil, postgresErr(err)
+	}
+
+	attendees := make([]acmregister.Attendance, len(rows))
+	for i, row := range rows {
+		attendees[i] = acmregister.Attendance{
+			GuildID:     guildID,
+			EventID:     eventID,
+			UserID:      discord.UserID(row.UserID),
+			CheckedInAt: row.CheckedInAt.Time,
+		}
+	}
+
+	return attendees, nil
+}
+
+func (s pgStore) SaveEvent(ev acmregister.ScheduledEvent) error {
+	if err := s.q.SaveEvent(s.ctx, postgres.SaveEventParams{
+		GuildID:   int64(ev.GuildID),
+		EventID:   int64(ev.EventID),
+		Name:      ev.Name,
+		StartTime: pgtype.Timestamptz{Time: ev.StartTime, Valid: true},
+		Ended:     ev.Ended,
+	}); err != nil {
+		return postgresErr(err)
+	}
+	return nil
+}
+
+func (s pgStore) EventInfo(guildID discord.GuildID, eventID discord.EventID) (*acmregister.ScheduledEvent, error) {
+	v, err := s.q.EventInfo(s.ctx, postgres.EventInfoParams{
+		GuildID: int64(guildID),
+		EventID: int64(eventID),
+	})
+	if err != nil {
+		return nil, postgresErr(err)
+	}
+
+	return &acmregister.ScheduledEvent{
+		GuildID:   guildID,
+		EventID:   eventID,
+		Name:      v.Name,
+		StartTime: v.StartTime.Time,
+		Ended:     v.Ended,
+	}, nil
+}
+
+func (s pgStore) SearchEvents(guildID discord.GuildID, query string, limit int) ([]acmregister.ScheduledEvent, error) {
+	rows, err := s.q.SearchEvents(s.ctx, postgres.SearchEventsParams{
+		GuildID:    int64(guildID),
+		Pattern:    containsPattern(query),
+		MaxResults: int32(limit),
+	})
+	if err != nil {
+		return nil, postgresErr(err)
+	}
+
+	events := make([]acmregister.ScheduledEvent, len(rows))
+	for i, row := range rows {
+		events[i] = acmregister.ScheduledEvent{
+			GuildID:   guildID,
+			EventID:   discord.EventID(row.EventID),
+			Name:      row.Name,
+			StartTime: row.StartTime.Time,
+			Ended:     row.Ended,
+		}
+	}
+
+	return events, nil
+}
+
+func (s pgStore) EventsBetween(guildID discord.GuildID, from, to time.Time) ([]acmregister.ScheduledEvent, error) {
+	rows, err := s.q.EventsBetween(s.ctx, postgres.EventsBetweenParams{
+		GuildID:  int64(guildID),
+		FromTime: pgtype.Timestamptz{Time: from, Valid: true},
+		ToTime:   pgtype.Timestamptz{Time: to, Valid: true},
+	})
+	if err != nil {
+		return nil, postgresErr(err)
+	}
+
+	events := make([]acmregister.ScheduledEvent, len(rows))
+	for i, row := range rows {
+		events[i] = acmregister.ScheduledEvent{
+			GuildID:   guildID,
+			EventID:   discord.EventID(row.EventID),
+			Name:      row.Name,
+			StartTime: row.StartTime.Time,
+			Ended:     row.Ended,
+		}
+	}
+
+	return events, nil
+}
+
+func (s pgStore) AddEventParticipants(guildID discord.GuildID, eventID discord.EventID, userIDs ...discord.UserID) error {
+	tx, err := s.db.Begin(s.ctx)
+	if err != nil {
+		return postgresErr(err)
+	}
+	defer tx.Rollback(s.ctx)
+
+	q := postgres.New(tx)
+
+	for _, userID := range userIDs {
+		if err := q.AddEventParticipant(s.ctx, postgres.AddEventParticipantParams{
+			GuildID: int64(guildID),
+			EventID: int64(eventID),
+			UserID:  int64(userID),
+		}); err != nil {
+			return postgresErr(err)
+		}
+	}
+
+	if err := tx.Commit(s.ctx); err != nil {
+		return postgresErr(err)
+	}
+
+	return nil
+}
+
+func (s pgStore) RemoveEventParticipant(guildID discord.GuildID, eventID discord.EventID, userID discord.UserID) error {
+	if err := s.q.RemoveEventParticipant(s.ctx, postgres.RemoveEventParticipantParams{
+		GuildID: int64(guildID),
+		EventID: int64(eventID),
+		UserID:  int64(userID),
+	}); err != nil {
+		return postgresErr(err)
+	}
+	return nil
+}
+
+func (s pgStore) EventParticipants(guildID discord.GuildID, eventID discord.EventID) ([]discord.UserID, error) {
+	rows, err := s.q.EventParticipants(s.ctx, postgres.EventParticipantsParams{
+		GuildID: int64(guildID),
+		EventID: int64(eventID),
+	})
+	if err != nil {
+		return nil, postgresErr(err)
+	}
+
+	userIDs := make([]discord.UserID, len(rows))
+	for i, userID := range rows {
+		userIDs[i] = discord.UserID(userID)
+	}
+
+	return userIDs, nil
 }
 
 func (s pgStore) GeneratePIN(guildID discord.GuildID, userID discord.UserID) (verifyemail.PIN, error) {
@@ -266,15 +915,92 @@ func (s pgStore) ValidatePIN(guildID discord.GuildID, userID discord.UserID, pin
 		Pin:     int16(pin),
 	})
 	if err != nil {
+		err = postgresErr(err)
+		if !errors.Is(err, acmregister.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	success := err == nil
+
+	// AddPINAttempt skips unknown guilds instead of failing the foreign key, so
+	// they still get ErrNotFound from above.
+	if err := s.q.AddPINAttempt(s.ctx, postgres.AddPINAttemptParams{
+		GuildID:     int64(guildID),
+		UserID:      int64(userID),
+		Success:     success,
+		AttemptedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}); err != nil {
 		return nil, postgresErr(err)
 	}
 
-	var metadata acmregister.MemberMetadata
-	if err := json.Unmarshal(b, &metadata); err != nil {
-		return nil, errors.Wrap(err, "member metadata JSON is corrupted")
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalMetadata(b)
+}
+
+func (s pgStore) RegistrationStats(guildID discord.GuildID, since time.Time) (*acmregister.RegistrationStats, error) {
+	stats := newRegistrationStats(since)
+	pgSince := pgtype.Timestamptz{Time: since, Valid: true}
+
+	members, err := s.q.CountMembers(s.ctx, int64(guildID))
+	if err != nil {
+		return nil, errors.Wrap(postgresErr(err), "cannot count members")
+	}
+	stats.Members = int(members)
+
+	weekly, err := s.q.CountMembersPerWeek(s.ctx, postgres.CountMembersPerWeekParams{
+		GuildID: int64(guildID),
+		Since:   pgSince,
+	})
+	if err != nil {
+		return nil, errors.Wrap(postgresErr(err), "cannot count weekly members")
+	}
+	for _, row := range weekly {
+		addWeekly(stats, int(row.Week), int(row.Members))
+	}
+
+	hosts, err := s.q.CountMembersByEmailHost(s.ctx, int64(guildID))
+	if err != nil {
+		return nil, errors.Wrap(postgresErr(err), "cannot count email hosts")
+	}
+	for _, row := range hosts {
+		stats.EmailHosts[row.Host] = int(row.Members)
+	}
+
+	pronouns, err := s.q.CountMembersByPronouns(s.ctx, int64(guildID))
+	if err != nil {
+		return nil, errors.Wrap(postgresErr(err), "cannot count pronouns")
+	}
+	for _, row := range pronouns {
+		stats.Pronouns[acmregister.Pronouns(row.Pronouns)] = int(row.Members)
+	}
+
+	submissions, err := s.q.CountPendingSubmissions(s.ctx, int64(guildID))
+	if err != nil {
+		return nil, errors.Wrap(postgresErr(err), "cannot count pending submissions")
+	}
+	stats.PendingSubmissions = int(submissions)
+
+	approvals, err := s.q.CountPendingApprovals(s.ctx, int64(guildID))
+	if err != nil {
+		return nil, errors.Wrap(postgresErr(err), "cannot count pending approvals")
+	}
+	stats.PendingApprovals = int(approvals)
+
+	attempts, err := s.q.CountPINAttempts(s.ctx, postgres.CountPINAttemptsParams{
+		GuildID: int64(guildID),
+		Since:   pgSince,
+	})
+	if err != nil {
+		return nil, errors.Wrap(postgresErr(err), "cannot count PIN attempts")
 	}
+	stats.PINAttempts = int(attempts.Attempts)
+	stats.PINSuccesses = int(attempts.Successes)
 
-	return &metadata, nil
+	return stats, nil
 }
 
 func postgresErr(err error) error {
@@ -285,3 +1011,35 @@ func postgresErr(err error) error {
 		return err
 	}
 }
+
+// pgMemberRow is the row type of all queries that list members.
+type pgMemberRow struct {
+	UserID       int64
+	Metadata     []byte
+	RegisteredAt pgtype.Timestamptz
+	RegisteredBy pgtype.Int8
+	DepartedAt   pgtype.Timestamptz
+}
+
+func pgMembers(guildID discord.GuildID, rows []pgMemberRow) ([]acmregister.Member, error) {
+	members := make([]acmregister.Member, len(rows))
+	for i, r := range rows {
+		metadata, err := unmarshalMemberMetadata(r.Metadata)
+		if err != nil {
+			return nil, errors.Wrapf(err, "member %d", r.UserID)
+		}
+		members[i] = acmregister.Member{
+			GuildID:      guildID,
+			UserID:       discord.UserID(r.UserID),
+			Metadata:     *metadata,
+			RegisteredBy: discord.UserID(r.RegisteredBy.Int64),
+		}
+		if r.RegisteredAt.Valid {
+			members[i].RegisteredAt = r.RegisteredAt.Time
+		}
+		if r.DepartedAt.Valid {
+			members[i].DepartedAt = r.DepartedAt.Time
+		}
+	}
+	return members, nil
+}
@@ -0,0 +1,1045 @@
+package stores
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/diamondburned/acmregister/acmregister"
+	"github.com/diamondburned/acmregister/acmregister/logger"
+	"github.com/diamondburned/acmregister/acmregister/verifyemail"
+	"github.com/diamondburned/acmregister/internal/stores/sqlite"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/pkg/errors"
+)
+
+type sqliteStore struct {
+	q   *sqlite.Queries
+	db  *sql.DB
+	ctx context.Context
+}
+
+// NewSQLite opens or creates an SQLite database at the given path. The path
+// may be ":memory:" for a database that only lives as long as the store.
+func NewSQLite(ctx context.Context, path string) (StoreCloser, error) {
+	db, err := sqlite.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "sql/sqlite")
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "sql/sqlite: cannot open database")
+	}
+
+	if err := sqlite.Migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "cannot migrate sqlite db")
+	}
+
+	s := sqliteStore{
+		q:   sqlite.New(db),
+		db:  db,
+		ctx: ctx,
+	}
+
+	return s, nil
+}
+
+func (s sqliteStore) Close() error {
+	return s.db.Close()
+}
+
+func (s sqliteStore) WithContext(ctx context.Context) acmregister.ContainsContext {
+	s.ctx = ctx
+	return s
+}
+
+func (s sqliteStore) InitGuild(guild acmregister.KnownGuild) error {
+	messages, err := json.Marshal(guild.Messages)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode guild messages as JSON")
+	}
+
+	formFields, err := marshalFormFields(guild.FormFields)
+	if err != nil {
+		return err
+	}
+
+	nickname, err := json.Marshal(guild.Nickname)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode guild nickname settings as JSON")
+	}
+
+	err = s.q.InitGuild(s.ctx, sqlite.InitGuildParams{
+		GuildID:           int64(guild.GuildID),
+		ChannelID:         int64(guild.ChannelID),
+		RoleID:            int64(guild.RoleID),
+		InitUserID:        int64(guild.InitUserID),
+		RegisteredMessage: guild.RegisteredMessage,
+		Messages:          string(messages),
+		RegisterMessageID: sql.NullInt64{Int64: int64(guild.RegisterMessageID), Valid: guild.RegisterMessageID.IsValid()},
+		FormFields:        string(formFields),
+		Nickname:          string(nickname),
+		ApprovalChannelID: sql.NullInt64{Int64: int64(guild.ApprovalChannelID), Valid: guild.ApprovalChannelID.IsValid()},
+		AuditChannelID:    sql.NullInt64{Int64: int64(guild.AuditChannelID), Valid: guild.AuditChannelID.IsValid()},
+	})
+	return sqliteErr(err)
+}
+
+func (s sqliteStore) GuildInfo(guildID discord.GuildID) (*acmregister.KnownGuild, error) {
+	v, err := s.q.GuildInfo(s.ctx, int64(guildID))
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+
+	var messages acmregister.GuildMessages
+	if err := json.Unmarshal([]byte(v.Messages), &messages); err != nil {
+		return nil, errors.Wrap(err, "cannot decode guild messages")
+	}
+
+	formFields, err := unmarshalFormFields([]byte(v.FormFields))
+	if err != nil {
+		return nil, err
+	}
+
+	var nickname acmregister.NicknameSettings
+	if err := json.Unmarshal([]byte(v.Nickname), &nickname); err != nil {
+		return nil, errors.Wrap(err, "cannot decode guild nickname settings")
+	}
+
+	return &acmregister.KnownGuild{
+		GuildID:           discord.GuildID(v.GuildID),
+		ChannelID:         discord.ChannelID(v.ChannelID),
+		RoleID:            discord.RoleID(v.RoleID),
+		InitUserID:        discord.UserID(v.InitUserID),
+		RegisteredMessage: v.RegisteredMessage,
+		AdminRoleID:       discord.RoleID(v.AdminRoleID.Int64),
+		RegisterMessageID: discord.MessageID(v.RegisterMessageID.Int64),
+		Messages:          messages,
+		FormFields:        formFields,
+		Nickname:          nickname,
+		ApprovalChannelID: discord.ChannelID(v.ApprovalChannelID.Int64),
+		AuditChannelID:    discord.ChannelID(v.AuditChannelID.Int64),
+	}, nil
+}
+
+func (s sqliteStore) GuildSetAdminRole(guildID discord.GuildID, roleID discord.RoleID) error {
+	n, err := s.q.SetGuildAdminRoleID(s.ctx, sqlite.SetGuildAdminRoleIDParams{
+		GuildID:     int64(guildID),
+		AdminRoleID: sql.NullInt64{Int64: int64(roleID), Valid: roleID.IsValid()},
+	})
+	if err != nil {
+		return sqliteErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s sqliteStore) GuildSetRole(guildID discord.GuildID, roleID discord.RoleID) error {
+	n, err := s.q.SetGuildRoleID(s.ctx, sqlite.SetGuildRoleIDParams{
+		GuildID: int64(guildID),
+		RoleID:  int64(roleID),
+	})
+	if err != nil {
+		return sqliteErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s sqliteStore) GuildSetRegisterMessage(guildID discord.GuildID, channelID discord.ChannelID, messageID discord.MessageID) error {
+	n, err := s.q.SetGuildRegisterMessage(s.ctx, sqlite.SetGuildRegisterMessageParams{
+		GuildID:           int64(guildID),
+		ChannelID:         int64(channelID),
+		RegisterMessageID: sql.NullInt64{Int64: int64(messageID), Valid: messageID.IsValid()},
+	})
+	if err != nil {
+		return sqliteErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s sqliteStore) GuildSetMessages(guildID discord.GuildID, registeredMessage string, msgs acmregister.GuildMessages) error {
+	messages, err := json.Marshal(msgs)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode guild messages as JSON")
+	}
+
+	n, err := s.q.SetGuildMessages(s.ctx, sqlite.SetGuildMessagesParams{
+		GuildID:           int64(guildID),
+		RegisteredMessage: registeredMessage,
+		Messages:          string(messages),
+	})
+	if err != nil {
+		return sqliteErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s sqliteStore) GuildSetFormFields(guildID discord.GuildID, fields []acmregister.FormField) error {
+	formFields, err := marshalFormFields(fields)
+	if err != nil {
+		return err
+	}
+
+	n, err := s.q.SetGuildFormFields(s.ctx, sqlite.SetGuildFormFieldsParams{
+		GuildID:    int64(guildID),
+		FormFields: string(formFields),
+	})
+	if err != nil {
+		return sqliteErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s sqliteStore) GuildSetNickname(guildID discord.GuildID, settings acmregister.NicknameSettings) error {
+	nickname, err := json.Marshal(settings)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode guild nickname settings as JSON")
+	}
+
+	n, err := s.q.SetGuildNickname(s.ctx, sqlite.SetGuildNicknameParams{
+		GuildID:  int64(guildID),
+		Nickname: string(nickname),
+	})
+	if err != nil {
+		return sqliteErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s sqliteStore) GuildSetApprovalChannel(guildID discord.GuildID, channelID discord.ChannelID) error {
+	n, err := s.q.SetGuildApprovalChannelID(s.ctx, sqlite.SetGuildApprovalChannelIDParams{
+		GuildID:           int64(guildID),
+		ApprovalChannelID: sql.NullInt64{Int64: int64(channelID), Valid: channelID.IsValid()},
+	})
+	if err != nil {
+		return sqliteErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s sqliteStore) GuildSetAuditChannel(guildID discord.GuildID, channelID discord.ChannelID) error {
+	n, err := s.q.SetGuildAuditChannelID(s.ctx, sqlite.SetGuildAuditChannelIDParams{
+		GuildID:        int64(guildID),
+		AuditChannelID: sql.NullInt64{Int64: int64(channelID), Valid: channelID.IsValid()},
+	})
+	if err != nil {
+		return sqliteErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s sqliteStore) DeleteGuild(guildID discord.GuildID) error {
+	n, err := s.q.DeleteGuild(s.ctx, int64(guildID))
+	if err != nil {
+		return sqliteErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s sqliteStore) MemberInfo(guildID discord.GuildID, userID discord.UserID) (*acmregister.MemberMetadata, error) {
+	b, err := s.q.MemberInfo(s.ctx, sqlite.MemberInfoParams{
+		GuildID: int64(guildID),
+		UserID:  int64(userID),
+	})
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+
+	return unmarshalMemberMetadata([]byte(b))
+}
+
+func (s sqliteStore) RegisterMember(m acmregister.Member) error {
+	metadata, err := json.Marshal(m.Metadata)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode member metadata as JSON")
+	}
+
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	if err != nil {
+		return sqliteErr(err)
+	}
+	defer tx.Rollback()
+
+	q := s.q.WithTx(tx)
+
+	if err := q.RegisterMember(s.ctx, sqlite.RegisterMemberParams{
+		GuildID:  int64(m.GuildID),
+		UserID:   int64(m.UserID),
+		Email:    string(m.Metadata.Email),
+		Metadata: string(metadata),
+		RegisteredAt: sql.NullInt64{
+			Int64: registeredAt(m).Unix(),
+			Valid: true,
+		},
+		RegisteredBy: sql.NullInt64{
+			Int64: int64(m.RegisteredBy),
+			Valid: m.RegisteredBy.IsValid(),
+		},
+	}); err != nil {
+		if sqlite.IsConstraintFailed(err) {
+			return acmregister.ErrMemberAlreadyExists
+		}
+		return sqliteErr(err)
+	}
+
+	if err := sqliteAddMemberEvent(s.ctx, q, registeredEvent(m)); err != nil {
+		return err
+	}
+
+	q.DeleteSubmission(s.ctx, sqlite.DeleteSubmissionParams{
+		GuildID: int64(m.GuildID),
+		UserID:  int64(m.UserID),
+	})
+
+	if err := tx.Commit(); err != nil {
+		return sqliteErr(err)
+	}
+
+	return nil
+}
+
+func (s sqliteStore) UpdateMemberMetadata(guildID discord.GuildID, userID discord.UserID, metadata acmregister.MemberMetadata) error {
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode member metadata as JSON")
+	}
+
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	if err != nil {
+		return sqliteErr(err)
+	}
+	defer tx.Rollback()
+
+	q := s.q.WithTx(tx)
+
+	n, err := q.UpdateMemberMetadata(s.ctx, sqlite.UpdateMemberMetadataParams{
+		GuildID:  int64(guildID),
+		UserID:   int64(userID),
+		Email:    string(metadata.Email),
+		Metadata: string(b),
+	})
+	if err != nil {
+		if sqlite.IsConstraintFailed(err) {
+			return acmregister.ErrMemberAlreadyExists
+		}
+		return sqliteErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+
+	if err := sqliteAddMemberEvent(s.ctx, q, acmregister.MemberEvent{
+		GuildID: guildID,
+		UserID:  userID,
+		Type:    acmregister.MemberEdited,
+		ActorID: userID,
+	}); err != nil {
+		return err
+	}
+
+	q.DeleteSubmission(s.ctx, sqlite.DeleteSubmissionParams{
+		GuildID: int64(guildID),
+		UserID:  int64(userID),
+	})
+
+	if err := tx.Commit(); err != nil {
+		return sqliteErr(err)
+	}
+
+	return nil
+}
+
+func (s sqliteStore) SetMemberDeparted(guildID discord.GuildID, userID discord.UserID, at time.Time) error {
+	n, err := s.q.SetMemberDepartedAt(s.ctx, sqlite.SetMemberDepartedAtParams{
+		GuildID:    int64(guildID),
+		UserID:     int64(userID),
+		DepartedAt: sql.NullInt64{Int64: at.Unix(), Valid: !at.IsZero()},
+	})
+	if err != nil {
+		return sqliteErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s sqliteStore) UnregisterMember(guildID discord.GuildID, userID, actorID discord.UserID) error {
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	if err != nil {
+		return sqliteErr(err)
+	}
+	defer tx.Rollback()
+
+	q := s.q.WithTx(tx)
+
+	n, err := q.UnregisterMember(s.ctx, sqlite.UnregisterMemberParams{
+		GuildID: int64(guildID),
+		UserID:  int64(userID),
+	})
+	if err != nil {
+		return sqliteErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+
+	if err := sqliteAddMemberEvent(s.ctx, q, acmregister.MemberEvent{
+		GuildID: guildID,
+		UserID:  userID,
+		Type:    acmregister.MemberUnregistered,
+		ActorID: actorID,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sqliteErr(err)
+	}
+
+	return nil
+}
+
+func (s sqliteStore) AddMemberEvent(ev acmregister.MemberEvent) error {
+	return sqliteAddMemberEvent(s.ctx, s.q, ev)
+}
+
+func (s sqliteStore) MemberEvents(guildID discord.GuildID, userID discord.UserID) ([]acmregister.MemberEvent, error) {
+	rows, err := s.q.MemberEvents(s.ctx, sqlite.MemberEventsParams{
+		GuildID: int64(guildID),
+		UserID:  int64(userID),
+	})
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+
+	events := make([]acmregister.MemberEvent, len(rows))
+	for i, row := range rows {
+		events[i] = acmregister.MemberEvent{
+			GuildID: guildID,
+			UserID:  userID,
+			Type:    acmregister.MemberEventType(row.Type),
+			ActorID: discord.UserID(row.ActorID.Int64),
+			Time:    time.Unix(row.CreatedAt, 0),
+		}
+	}
+
+	return events, nil
+}
+
+func sqliteAddMemberEvent(ctx context.Context, q *sqlite.Queries, ev acmregister.MemberEvent) error {
+	if err := q.AddMemberEvent(ctx, sqlite.AddMemberEventParams{
+		GuildID: int64(ev.GuildID),
+		UserID:  int64(ev.UserID),
+		Type:    string(ev.Type),
+		ActorID: sql.NullInt64{
+			Int64: int64(ev.ActorID),
+			Valid: ev.ActorID.IsValid(),
+		},
+		CreatedAt: eventTime(ev).Unix(),
+	}); err != nil {
+		return sqliteErr(err)
+	}
+	return nil
+}
+
+func (s sqliteStore) ListMembers(guildID discord.GuildID, cursor acmregister.MemberCursor, limit int) ([]acmregister.Member, error) {
+	var rows []sqliteMemberRow
+
+	if cursor.Before {
+		v, err := s.q.ListMembersBefore(s.ctx, sqlite.ListMembersBeforeParams{
+			GuildID: int64(guildID),
+			UserID:  int64(cursor.UserID),
+			Limit:   int64(limit),
+		})
+		if err != nil {
+			return nil, sqliteErr(err)
+		}
+		// Rows are in descending order, so flip them back.
+		for i := len(v) - 1; i >= 0; i-- {
+			rows = append(rows, sqliteMemberRow(v[i]))
+		}
+	} else {
+		v, err := s.q.ListMembersAfter(s.ctx, sqlite.ListMembersAfterParams{
+			GuildID: int64(guildID),
+			UserID:  int64(cursor.UserID),
+			Limit:   int64(limit),
+		})
+		if err != nil {
+			return nil, sqliteErr(err)
+		}
+		for _, r := range v {
+			rows = append(rows, sqliteMemberRow(r))
+		}
+	}
+
+	return sqliteMembers(guildID, rows)
+}
+
+func (s sqliteStore) SearchMembers(guildID discord.GuildID, query string, limit int) ([]acmregister.Member, error) {
+	v, err := s.q.SearchMembers(s.ctx, sqlite.SearchMembersParams{
+		GuildID:    int64(guildID),
+		Pattern:    containsPattern(query),
+		MaxResults: int64(limit),
+	})
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+
+	rows := make([]sqliteMemberRow, len(v))
+	for i, r := range v {
+		rows[i] = sqliteMemberRow(r)
+	}
+
+	return sqliteMembers(guildID, rows)
+}
+
+func (s sqliteStore) SaveSubmission(m acmregister.Member) error {
+	metadata, err := json.Marshal(m.Metadata)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode member metadata as JSON")
+	}
+
+	now := time.Now()
+
+	err = s.q.SaveSubmission(s.ctx, sqlite.SaveSubmissionParams{
+		GuildID:  int64(m.GuildID),
+		UserID:   int64(m.UserID),
+		Metadata: string(metadata),
+		ExpireAt: now.Add(acmregister.SubmissionSaveDuration).Unix(),
+	})
+	if err != nil {
+		return sqliteErr(err)
+	}
+
+	s.q.CleanupSubmissions(s.ctx, now.Unix())
+	return nil
+}
+
+func (s sqliteStore) RestoreSubmission(guildID discord.GuildID, userID discord.UserID) (*acmregister.MemberMetadata, error) {
+	b, err := s.q.RestoreSubmission(s.ctx, sqlite.RestoreSubmissionParams{
+		GuildID: int64(guildID),
+		UserID:  int64(userID),
+		Now:     time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+
+	return unmarshalMetadata([]byte(b))
+}
+
+func (s sqliteStore) SubmitApproval(a acmregister.Approval) error {
+	metadata, err := json.Marshal(a.Metadata)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode member metadata as JSON")
+	}
+
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	if err != nil {
+		return sqliteErr(err)
+	}
+	defer tx.Rollback()
+
+	q := s.q.WithTx(tx)
+
+	if err := q.SubmitApproval(s.ctx, sqlite.SubmitApprovalParams{
+		GuildID:     int64(a.GuildID),
+		UserID:      int64(a.UserID),
+		Metadata:    string(metadata),
+		SubmittedAt: submittedAt(a).Unix(),
+	}); err != nil {
+		return sqliteErr(err)
+	}
+
+	q.DeleteSubmission(s.ctx, sqlite.DeleteSubmissionParams{
+		GuildID: int64(a.GuildID),
+		UserID:  int64(a.UserID),
+	})
+
+	if err := tx.Commit(); err != nil {
+		return sqliteErr(err)
+	}
+
+	return nil
+}
+
+func (s sqliteStore) ApprovalInfo(guildID discord.GuildID, userID discord.UserID) (*acmregister.Approval, error) {
+	v, err := s.q.ApprovalInfo(s.ctx, sqlite.ApprovalInfoParams{
+		GuildID: int64(guildID),
+		UserID:  int64(userID),
+	})
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+
+	metadata, err := unmarshalMetadata([]byte(v.Metadata))
+	if err != nil {
+		return nil, err
+	}
+
+	approval := &acmregister.Approval{
+		GuildID:     guildID,
+		UserID:      userID,
+		Metadata:    *metadata,
+		SubmittedAt: time.Unix(v.SubmittedAt, 0),
+		DeniedBy:    discord.UserID(v.DeniedBy.Int64),
+		DenyReason:  v.DenyReason,
+	}
+	if v.DeniedAt.Valid {
+		approval.DeniedAt = time.Unix(v.DeniedAt.Int64, 0)
+	}
+
+	return approval, nil
+}
+
+func (s sqliteStore) ApproveMember(guildID discord.GuildID, userID, approvedBy discord.UserID) (*acmregister.MemberMetadata, error) {
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+	defer tx.Rollback()
+
+	q := s.q.WithTx(tx)
+
+	v, err := q.ApprovalInfo(s.ctx, sqlite.ApprovalInfoParams{
+		GuildID: int64(guildID),
+		UserID:  int64(userID),
+	})
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+
+	n, err := q.DeletePendingApproval(s.ctx, sqlite.DeletePendingApprovalParams{
+		GuildID: int64(guildID),
+		UserID:  int64(userID),
+	})
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+	if n == 0 {
+		// The approval has been denied.
+		return nil, acmregister.ErrNotFound
+	}
+
+	metadata, err := unmarshalMetadata([]byte(v.Metadata))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if err := q.RegisterMember(s.ctx, sqlite.RegisterMemberParams{
+		GuildID:      int64(guildID),
+		UserID:       int64(userID),
+		Email:        string(metadata.Email),
+		Metadata:     v.Metadata,
+		RegisteredAt: sql.NullInt64{Int64: now.Unix(), Valid: true},
+	}); err != nil {
+		if sqlite.IsConstraintFailed(err) {
+			return nil, acmregister.ErrMemberAlreadyExists
+		}
+		return nil, sqliteErr(err)
+	}
+
+	if err := sqliteAddMemberEvent(s.ctx, q, acmregister.MemberEvent{
+		GuildID: guildID,
+		UserID:  userID,
+		Type:    acmregister.MemberRegistered,
+		ActorID: approvedBy,
+		Time:    now,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, sqliteErr(err)
+	}
+
+	return metadata, nil
+}
+
+func (s sqliteStore) DenyMember(guildID discord.GuildID, userID, deniedBy discord.UserID, reason string) error {
+	n, err := s.q.DenyApproval(s.ctx, sqlite.DenyApprovalParams{
+		GuildID:    int64(guildID),
+		UserID:     int64(userID),
+		DeniedAt:   sql.NullInt64{Int64: time.Now().Unix(), Valid: true},
+		DeniedBy:   sql.NullInt64{Int64: int64(deniedBy), Valid: deniedBy.IsValid()},
+		DenyReason: reason,
+	})
+	if err != nil {
+		return sqliteErr(err)
+	}
+	if n == 0 {
+		return acmregister.ErrNotFound
+	}
+	return nil
+}
+
+func (s sqliteStore) CheckIn(a acmregister.Attendance) error {
+	if err := s.q.CheckIn(s.ctx, sqlite.CheckInParams{
+		GuildID:     int64(a.GuildID),
+		EventID:     int64(a.EventID),
+		UserID:      int64(a.UserID),
+		CheckedInAt: checkedInAt(a).Unix(),
+	}); err != nil {
+		if sqlite.IsConstraintFailed(err) {
+			return acmregister.ErrAlreadyCheckedIn
+		}
+		return sqliteErr(err)
+	}
+	return nil
+}
+
+func (s sqliteStore) EventAttendees(guildID discord.GuildID, eventID discord.EventID) ([]acmregister.Attendance, error) {
+	rows, err := s.q.EventAttendees(s.ctx, sqlite.EventAttendeesParams{
+		GuildID: int64(guildID),
+		EventID: int64(eventID),
+	})
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+
+	attendees := make([]acmregister.Attendance, len(rows))
+	for i, row := range rows {
+		attendees[i] = acmregister.Attendance{
+			GuildID:     guildID,
+			EventID:     eventID,
+			UserID:      discord.UserID(row.UserID),
+			CheckedInAt: time.Unix(row.CheckedInAt, 0),
+		}
+	}
+
+	return attendees, nil
+}
+
+func (s sqliteStore) SaveEvent(ev acmregister.ScheduledEvent) error {
+	if err := s.q.SaveEvent(s.ctx, sqlite.SaveEventParams{
+		GuildID:   int64(ev.GuildID),
+		EventID:   int64(ev.EventID),
+		Name:      ev.Name,
+		StartTime: ev.StartTime.Unix(),
+		Ended:     ev.Ended,
+	}); err != nil {
+		return sqliteErr(err)
+	}
+	return nil
+}
+
+func (s sqliteStore) EventInfo(guildID discord.GuildID, eventID discord.EventID) (*acmregister.ScheduledEvent, error) {
+	v, err := s.q.EventInfo(s.ctx, sqlite.EventInfoParams{
+		GuildID: int64(guildID),
+		EventID: int64(eventID),
+	})
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+
+	return &acmregister.ScheduledEvent{
+		GuildID:   guildID,
+		EventID:   eventID,
+		Name:      v.Name,
+		StartTime: time.Unix(v.StartTime, 0),
+		Ended:     v.Ended,
+	}, nil
+}
+
+func (s sqliteStore) SearchEvents(guildID discord.GuildID, query string, limit int) ([]acmregister.ScheduledEvent, error) {
+	rows, err := s.q.SearchEvents(s.ctx, sqlite.SearchEventsParams{
+		GuildID:    int64(guildID),
+		Pattern:    containsPattern(query),
+		MaxResults: int64(limit),
+	})
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+
+	events := make([]acmregister.ScheduledEvent, len(rows))
+	for i, row := range rows {
+		events[i] = acmregister.ScheduledEvent{
+			GuildID:   guildID,
+			EventID:   discord.EventID(row.EventID),
+			Name:      row.Name,
+			StartTime: time.Unix(row.StartTime, 0),
+			Ended:     row.Ended,
+		}
+	}
+
+	return events, nil
+}
+
+func (s sqliteStore) EventsBetween(guildID discord.GuildID, from, to time.Time) ([]acmregister.ScheduledEvent, error) {
+	rows, err := s.q.EventsBetween(s.ctx, sqlite.EventsBetweenParams{
+		GuildID:  int64(guildID),
+		FromTime: from.Unix(),
+		ToTime:   to.Unix(),
+	})
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+
+	events := make([]acmregister.ScheduledEvent, len(rows))
+	for i, row := range rows {
+		events[i] = acmregister.ScheduledEvent{
+			GuildID:   guildID,
+			EventID:   discord.EventID(row.EventID),
+			Name:      row.Name,
+			StartTime: time.Unix(row.StartTime, 0),
+			Ended:     row.Ended,
+		}
+	}
+
+	return events, nil
+}
+
+func (s sqliteStore) AddEventParticipants(guildID discord.GuildID, eventID discord.EventID, userIDs ...discord.UserID) error {
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	if err != nil {
+		return sqliteErr(err)
+	}
+	defer tx.Rollback()
+
+	q := s.q.WithTx(tx)
+
+	for _, userID := range userIDs {
+		if err := q.AddEventParticipant(s.ctx, sqlite.AddEventParticipantParams{
+			GuildID: int64(guildID),
+			EventID: int64(eventID),
+			UserID:  int64(userID),
+		}); err != nil {
+			return sqliteErr(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sqliteErr(err)
+	}
+
+	return nil
+}
+
+func (s sqliteStore) RemoveEventParticipant(guildID discord.GuildID, eventID discord.EventID, userID discord.UserID) error {
+	if err := s.q.RemoveEventParticipant(s.ctx, sqlite.RemoveEventParticipantParams{
+		GuildID: int64(guildID),
+		EventID: int64(eventID),
+		UserID:  int64(userID),
+	}); err != nil {
+		return sqliteErr(err)
+	}
+	return nil
+}
+
+func (s sqliteStore) EventParticipants(guildID discord.GuildID, eventID discord.EventID) ([]discord.UserID, error) {
+	rows, err := s.q.EventParticipants(s.ctx, sqlite.EventParticipantsParams{
+		GuildID: int64(guildID),
+		EventID: int64(eventID),
+	})
+	if err != nil {
+		return nil, sqliteErr(err)
+	}
+
+	userIDs := make([]discord.UserID, len(rows))
+	for i, userID := range rows {
+		userIDs[i] = discord.UserID(userID)
+	}
+
+	return userIDs, nil
+}
+
+func (s sqliteStore) GeneratePIN(guildID discord.GuildID, userID discord.UserID) (verifyemail.PIN, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, 15*time.Second)
+	defer cancel()
+
+	log := logger.FromContext(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return verifyemail.InvalidPIN, ctx.Err()
+		default:
+		}
+
+		pin := verifyemail.GeneratePIN()
+		err := s.q.InsertPIN(ctx, sqlite.InsertPINParams{
+			GuildID: int64(guildID),
+			UserID:  int64(userID),
+			Pin:     int64(pin),
+		})
+		if err == nil {
+			return pin, nil
+		}
+		if sqlite.IsConstraintFailed(err) {
+			log.Println("same PIN is already used for user and guild, retrying")
+			continue
+		}
+		return verifyemail.InvalidPIN, errors.Wrap(err, "cannot store PIN")
+	}
+}
+
+func (s sqliteStore) ValidatePIN(guildID discord.GuildID, userID discord.UserID, pin verifyemail.PIN) (*acmregister.MemberMetadata, error) {
+	b, err := s.q.ValidatePIN(s.ctx, sqlite.ValidatePINParams{
+		GuildID: int64(guildID),
+		UserID:  int64(userID),
+		Pin:     int64(pin),
+		Now:     time.Now().Unix(),
+	})
+	if err != nil {
+		err = sqliteErr(err)
+		if !errors.Is(err, acmregister.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	success := err == nil
+
+	// AddPINAttempt skips unknown guilds instead of failing the foreign key, so
+	// they still get ErrNotFound from above.
+	if err := s.q.AddPINAttempt(s.ctx, sqlite.AddPINAttemptParams{
+		GuildID:     int64(guildID),
+		UserID:      int64(userID),
+		Success:     success,
+		AttemptedAt: time.Now().Unix(),
+	}); err != nil {
+		return nil, sqliteErr(err)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalMetadata([]byte(b))
+}
+
+func (s sqliteStore) RegistrationStats(guildID discord.GuildID, since time.Time) (*acmregister.RegistrationStats, error) {
+	stats := newRegistrationStats(since)
+
+	members, err := s.q.CountMembers(s.ctx, int64(guildID))
+	if err != nil {
+		return nil, errors.Wrap(sqliteErr(err), "cannot count members")
+	}
+	stats.Members = int(members)
+
+	weekly, err := s.q.CountMembersPerWeek(s.ctx, sqlite.CountMembersPerWeekParams{
+		GuildID: int64(guildID),
+		Since:   sql.NullInt64{Int64: since.Unix(), Valid: true},
+	})
+	if err != nil {
+		return nil, errors.Wrap(sqliteErr(err), "cannot count weekly members")
+	}
+	for _, row := range weekly {
+		addWeekly(stats, int(row.Week), int(row.Members))
+	}
+
+	hosts, err := s.q.CountMembersByEmailHost(s.ctx, int64(guildID))
+	if err != nil {
+		return nil, errors.Wrap(sqliteErr(err), "cannot count email hosts")
+	}
+	for _, row := range hosts {
+		stats.EmailHosts[row.Host] = int(row.Members)
+	}
+
+	pronouns, err := s.q.CountMembersByPronouns(s.ctx, int64(guildID))
+	if err != nil {
+		return nil, errors.Wrap(sqliteErr(err), "cannot count pronouns")
+	}
+	for _, row := range pronouns {
+		stats.Pronouns[acmregister.Pronouns(row.Pronouns)] = int(row.Members)
+	}
+
+	submissions, err := s.q.CountPendingSubmissions(s.ctx, sqlite.CountPendingSubmissionsParams{
+		GuildID: int64(guildID),
+		Now:     time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(sqliteErr(err), "cannot count pending submissions")
+	}
+	stats.PendingSubmissions = int(submissions)
+
+	approvals, err := s.q.CountPendingApprovals(s.ctx, int64(guildID))
+	if err != nil {
+		return nil, errors.Wrap(sqliteErr(err), "cannot count pending approvals")
+	}
+	stats.PendingApprovals = int(approvals)
+
+	attempts, err := s.q.CountPINAttempts(s.ctx, sqlite.CountPINAttemptsParams{
+		GuildID: int64(guildID),
+		Since:   since.Unix(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(sqliteErr(err), "cannot count PIN attempts")
+	}
+	stats.PINAttempts = int(attempts.Attempts)
+	stats.PINSuccesses = int(attempts.Successes)
+
+	return stats, nil
+}
+
+func sqliteErr(err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return acmregister.ErrNotFound
+	default:
+		return err
+	}
+}
+
+// sqliteMemberRow is the row type of all queries that list members.
+type sqliteMemberRow struct {
+	UserID       int64
+	Metadata     string
+	RegisteredAt sql.NullInt64
+	RegisteredBy sql.NullInt64
+	DepartedAt   sql.NullInt64
+}
+
+func sqliteMembers(guildID discord.GuildID, rows []sqliteMemberRow) ([]acmregister.Member, error) {
+	members := make([]acmregister.Member, len(rows))
+	for i, r := range rows {
+		metadata, err := unmarshalMemberMetadata([]byte(r.Metadata))
+		if err != nil {
+			return nil, errors.Wrapf(err, "member %d", r.UserID)
+		}
+		members[i] = acmregister.Member{
+			GuildID:      guildID,
+			UserID:       discord.UserID(r.UserID),
+			Metadata:     *metadata,
+			RegisteredBy: discord.UserID(r.RegisteredBy.Int64),
+		}
+		if r.RegisteredAt.Valid {
+			members[i].RegisteredAt = time.Unix(r.RegisteredAt.Int64, 0)
+		}
+		if r.DepartedAt.Valid {
+			members[i].DepartedAt = time.Unix(r.DepartedAt.Int64, 0)
+		}
+	}
+	return members, nil
+}
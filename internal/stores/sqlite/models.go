@@ -0,0 +1,94 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.23.0
+
+package sqlite
+
+import (
+	"database/sql"
+)
+
+type EventAttendance struct {
+	GuildID     int64
+	EventID     int64
+	UserID      int64
+	CheckedInAt int64
+}
+
+type EventParticipant struct {
+	GuildID int64
+	EventID int64
+	UserID  int64
+}
+
+type KnownGuild struct {
+	GuildID           int64
+	ChannelID         int64
+	RoleID            int64
+	InitUserID        int64
+	RegisteredMessage string
+	AdminRoleID       sql.NullInt64
+	Messages          string
+	RegisterMessageID sql.NullInt64
+	FormFields        string
+	Nickname          string
+	ApprovalChannelID sql.NullInt64
+	AuditChannelID    sql.NullInt64
+}
+
+type Member struct {
+	GuildID      int64
+	UserID       int64
+	Email        string
+	Metadata     string
+	RegisteredAt sql.NullInt64
+	RegisteredBy sql.NullInt64
+	DepartedAt   sql.NullInt64
+}
+
+type MemberEvent struct {
+	ID        int64
+	GuildID   int64
+	UserID    int64
+	Type      string
+	ActorID   sql.NullInt64
+	CreatedAt int64
+}
+
+type PinAttempt struct {
+	GuildID     int64
+	UserID      int64
+	Success     bool
+	AttemptedAt int64
+}
+
+type PinCode struct {
+	GuildID int64
+	UserID  int64
+	Pin     int64
+}
+
+type RegistrationApproval struct {
+	GuildID     int64
+	UserID      int64
+	Metadata    string
+	SubmittedAt int64
+	DeniedAt    sql.NullInt64
+	DeniedBy    sql.NullInt64
+	DenyReason  string
+}
+
+type RegistrationSubmission struct {
+	GuildID  int64
+	UserID   int64
+	Metadata string
+	ExpireAt int64
+}
+
+type ScheduledEvent struct {
+	GuildID   int64
+	EventID   int64
+	Name      string
+	StartTime int64
+	Ended     bool
+}
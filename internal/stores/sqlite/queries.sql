@@ -0,0 +1,509 @@
+-- Language: sqlite
+--
+-- name: GuildInfo :one
+SELECT
+	*
+FROM
+	known_guilds
+WHERE
+	guild_id = ?
+LIMIT
+	1;
+
+-- name: DeleteGuild :execrows
+DELETE FROM
+	known_guilds
+WHERE
+	guild_id = ?;
+
+-- name: InitGuild :exec
+INSERT INTO
+	known_guilds (
+		guild_id,
+		channel_id,
+		init_user_id,
+		role_id,
+		registered_message,
+		messages,
+		register_message_id,
+		form_fields,
+		nickname,
+		approval_channel_id,
+		audit_channel_id
+	)
+VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: SetGuildAdminRoleID :execrows
+UPDATE
+	known_guilds
+SET
+	admin_role_id = ?
+WHERE
+	guild_id = ?;
+
+-- name: SetGuildRoleID :execrows
+UPDATE
+	known_guilds
+SET
+	role_id = ?
+WHERE
+	guild_id = ?;
+
+-- name: SetGuildMessages :execrows
+UPDATE
+	known_guilds
+SET
+	registered_message = ?,
+	messages = ?
+WHERE
+	guild_id = ?;
+
+-- name: SetGuildRegisterMessage :execrows
+UPDATE
+	known_guilds
+SET
+	channel_id = ?,
+	register_message_id = ?
+WHERE
+	guild_id = ?;
+
+-- name: SetGuildFormFields :execrows
+UPDATE
+	known_guilds
+SET
+	form_fields = ?
+WHERE
+	guild_id = ?;
+
+-- name: SetGuildNickname :execrows
+UPDATE
+	known_guilds
+SET
+	nickname = ?
+WHERE
+	guild_id = ?;
+
+-- name: SetGuildApprovalChannelID :execrows
+UPDATE
+	known_guilds
+SET
+	approval_channel_id = ?
+WHERE
+	guild_id = ?;
+
+-- name: SetGuildAuditChannelID :execrows
+UPDATE
+	known_guilds
+SET
+	audit_channel_id = ?
+WHERE
+	guild_id = ?;
+
+-- name: RegisterMember :exec
+INSERT INTO
+	members (guild_id, user_id, email, metadata, registered_at, registered_by)
+VALUES
+	(?, ?, ?, ?, ?, ?);
+
+-- name: SetMemberDepartedAt :execrows
+UPDATE
+	members
+SET
+	departed_at = ?
+WHERE
+	guild_id = ?
+	AND user_id = ?;
+
+-- name: UpdateMemberMetadata :execrows
+UPDATE
+	members
+SET
+	email = ?,
+	metadata = ?
+WHERE
+	guild_id = ?
+	AND user_id = ?;
+
+-- name: UnregisterMember :execrows
+DELETE FROM
+	members
+WHERE
+	guild_id = ?
+	AND user_id = ?;
+
+-- name: MemberInfo :one
+SELECT
+	metadata
+FROM
+	members
+WHERE
+	guild_id = ?
+	AND user_id = ?;
+
+-- name: SaveSubmission :exec
+INSERT INTO
+	registration_submissions (guild_id, user_id, metadata, expire_at)
+VALUES
+	(?, ?, ?, ?) ON CONFLICT (guild_id, user_id)
+DO
+UPDATE
+SET
+	metadata = excluded.metadata,
+	expire_at = excluded.expire_at;
+
+-- name: DeleteSubmission :exec
+DELETE FROM
+	registration_submissions
+WHERE
+	guild_id = ?
+	AND user_id = ?;
+
+-- name: RestoreSubmission :one
+SELECT
+	metadata
+FROM
+	registration_submissions
+WHERE
+	guild_id = ?
+	AND user_id = ?
+	AND expire_at >= sqlc.arg(now);
+
+-- name: CleanupSubmissions :exec
+DELETE FROM
+	registration_submissions
+WHERE
+	expire_at < sqlc.arg(now);
+
+-- name: InsertPIN :exec
+INSERT INTO
+	pin_codes (guild_id, user_id, pin)
+VALUES
+	(?, ?, ?) ON CONFLICT (guild_id, user_id)
+DO
+UPDATE
+SET
+	pin = excluded.pin;
+
+-- name: ValidatePIN :one
+SELECT
+	registration_submissions.metadata
+FROM
+	registration_submissions
+	JOIN pin_codes ON registration_submissions.guild_id = pin_codes.guild_id
+	AND registration_submissions.user_id = pin_codes.user_id
+WHERE
+	pin_codes.guild_id = ?
+	AND pin_codes.user_id = ?
+	AND pin_codes.pin = ?
+	AND registration_submissions.expire_at >= sqlc.arg(now);
+
+-- name: ListMembersAfter :many
+SELECT
+	user_id,
+	metadata,
+	registered_at,
+	registered_by,
+	departed_at
+FROM
+	members
+WHERE
+	guild_id = ?
+	AND user_id > ?
+ORDER BY
+	user_id ASC
+LIMIT
+	?;
+
+-- name: ListMembersBefore :many
+SELECT
+	user_id,
+	metadata,
+	registered_at,
+	registered_by,
+	departed_at
+FROM
+	members
+WHERE
+	guild_id = ?
+	AND user_id < ?
+ORDER BY
+	user_id DESC
+LIMIT
+	?;
+
+-- name: SearchMembers :many
+SELECT
+	user_id,
+	metadata,
+	registered_at,
+	registered_by,
+	departed_at
+FROM
+	members
+WHERE
+	guild_id = sqlc.arg(guild_id)
+	-- Match against "email\nfirst last", so that the full name can also be
+	-- searched.
+	AND (
+		email || char(10) || IFNULL(json_extract(metadata, '$.first_name'), '') || ' ' || IFNULL(json_extract(metadata, '$.last_name'), '')
+	) LIKE CAST(sqlc.arg(pattern) AS TEXT) ESCAPE '\'
+ORDER BY
+	user_id ASC
+LIMIT
+	sqlc.arg(max_results);
+
+-- name: SubmitApproval :exec
+INSERT INTO
+	registration_approvals (guild_id, user_id, metadata, submitted_at)
+VALUES
+	(?, ?, ?, ?) ON CONFLICT (guild_id, user_id)
+DO
+UPDATE
+SET
+	metadata = EXCLUDED.metadata,
+	submitted_at = EXCLUDED.submitted_at,
+	denied_at = NULL,
+	denied_by = NULL,
+	deny_reason = '';
+
+-- name: ApprovalInfo :one
+SELECT
+	metadata,
+	submitted_at,
+	denied_at,
+	denied_by,
+	deny_reason
+FROM
+	registration_approvals
+WHERE
+	guild_id = ?
+	AND user_id = ?;
+
+-- name: DeletePendingApproval :execrows
+DELETE FROM
+	registration_approvals
+WHERE
+	guild_id = ?
+	AND user_id = ?
+	AND denied_at IS NULL;
+
+-- name: DenyApproval :execrows
+UPDATE
+	registration_approvals
+SET
+	denied_at = ?,
+	denied_by = ?,
+	deny_reason = ?
+WHERE
+	guild_id = ?
+	AND user_id = ?
+	AND denied_at IS NULL;
+
+-- name: AddMemberEvent :exec
+INSERT INTO
+	member_events (guild_id, user_id, type, actor_id, created_at)
+VALUES
+	(?, ?, ?, ?, ?);
+
+-- name: MemberEvents :many
+SELECT
+	type,
+	actor_id,
+	created_at
+FROM
+	member_events
+WHERE
+	guild_id = ?
+	AND user_id = ?
+ORDER BY
+	created_at ASC,
+	id ASC;
+
+-- name: AddPINAttempt :exec
+-- Attempts in unknown guilds are not recorded.
+INSERT INTO
+	pin_attempts (guild_id, user_id, success, attempted_at)
+SELECT
+	known_guilds.guild_id,
+	sqlc.arg(user_id),
+	sqlc.arg(success),
+	sqlc.arg(attempted_at)
+FROM
+	known_guilds
+WHERE
+	known_guilds.guild_id = sqlc.arg(guild_id);
+
+-- name: CountMembers :one
+SELECT
+	COUNT(*)
+FROM
+	members
+WHERE
+	guild_id = ?;
+
+-- name: CountMembersPerWeek :many
+SELECT
+	CAST((registered_at - sqlc.arg(since)) / 604800 AS INTEGER) AS week,
+	COUNT(*) AS members
+FROM
+	members
+WHERE
+	guild_id = sqlc.arg(guild_id)
+	AND registered_at >= sqlc.arg(since)
+GROUP BY
+	week;
+
+-- name: CountMembersByEmailHost :many
+SELECT
+	CAST(LOWER(SUBSTR(email, INSTR(email, '@') + 1)) AS TEXT) AS host,
+	COUNT(*) AS members
+FROM
+	members
+WHERE
+	guild_id = ?
+GROUP BY
+	host;
+
+-- name: CountMembersByPronouns :many
+SELECT
+	CAST(IFNULL(json_extract(metadata, '$.pronouns'), '') AS TEXT) AS pronouns,
+	COUNT(*) AS members
+FROM
+	members
+WHERE
+	guild_id = ?
+GROUP BY
+	pronouns;
+
+-- name: CountPendingSubmissions :one
+SELECT
+	COUNT(*)
+FROM
+	registration_submissions
+WHERE
+	guild_id = ?
+	AND expire_at >= sqlc.arg(now);
+
+-- name: CountPendingApprovals :one
+SELECT
+	COUNT(*)
+FROM
+	registration_approvals
+WHERE
+	guild_id = ?
+	AND denied_at IS NULL;
+
+-- name: CountPINAttempts :one
+SELECT
+	COUNT(*) AS attempts,
+	COUNT(
+		CASE
+			WHEN success THEN 1
+		END
+	) AS successes
+FROM
+	pin_attempts
+WHERE
+	guild_id = ?
+	AND attempted_at >= sqlc.arg(since);
+
+-- name: CheckIn :exec
+INSERT INTO
+	event_attendance (guild_id, event_id, user_id, checked_in_at)
+VALUES
+	(?, ?, ?, ?);
+
+-- name: EventAttendees :many
+SELECT
+	user_id,
+	checked_in_at
+FROM
+	event_attendance
+WHERE
+	guild_id = ?
+	AND event_id = ?
+ORDER BY
+	checked_in_at ASC,
+	user_id ASC;
+
+-- name: SaveEvent :exec
+INSERT INTO
+	scheduled_events (guild_id, event_id, name, start_time, ended)
+VALUES
+	(?, ?, ?, ?, ?) ON CONFLICT (guild_id, event_id)
+DO
+UPDATE
+SET
+	name = EXCLUDED.name,
+	start_time = EXCLUDED.start_time,
+	ended = EXCLUDED.ended;
+
+-- name: EventInfo :one
+SELECT
+	name,
+	start_time,
+	ended
+FROM
+	scheduled_events
+WHERE
+	guild_id = ?
+	AND event_id = ?;
+
+-- name: SearchEvents :many
+SELECT
+	event_id,
+	name,
+	start_time,
+	ended
+FROM
+	scheduled_events
+WHERE
+	guild_id = sqlc.arg(guild_id)
+	AND name LIKE CAST(sqlc.arg(pattern) AS TEXT) ESCAPE '\'
+ORDER BY
+	start_time DESC,
+	event_id DESC
+LIMIT
+	sqlc.arg(max_results);
+
+-- name: EventsBetween :many
+SELECT
+	event_id,
+	name,
+	start_time,
+	ended
+FROM
+	scheduled_events
+WHERE
+	guild_id = sqlc.arg(guild_id)
+	AND start_time >= sqlc.arg(from_time)
+	AND start_time < sqlc.arg(to_time)
+ORDER BY
+	start_time ASC,
+	event_id ASC;
+
+-- name: AddEventParticipant :exec
+INSERT INTO
+	event_participants (guild_id, event_id, user_id)
+VALUES
+	(?, ?, ?) ON CONFLICT DO NOTHING;
+
+-- name: RemoveEventParticipant :exec
+DELETE FROM
+	event_participants
+WHERE
+	guild_id = ?
+	AND event_id = ?
+	AND user_id = ?;
+
+-- name: EventParticipants :many
+SELECT
+	user_id
+FROM
+	event_participants
+WHERE
+	guild_id = ?
+	AND event_id = ?
+ORDER BY
+	user_id ASC;
@@ -0,0 +1,1516 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.23.0
+// source: queries.sql
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+)
+
+const addEventParticipant = `-- name: AddEventParticipant :exec
+INSERT INTO
+	event_participants (guild_id, event_id, user_id)
+VALUES
+	(?, ?, ?) ON CONFLICT DO NOTHING
+`
+
+type AddEventParticipantParams struct {
+	GuildID int64
+	EventID int64
+	UserID  int64
+}
+
+func (q *Queries) AddEventParticipant(ctx context.Context, arg AddEventParticipantParams) error {
+	_, err := q.db.ExecContext(ctx, addEventParticipant, arg.GuildID, arg.EventID, arg.UserID)
+	return err
+}
+
+const addMemberEvent = `-- name: AddMemberEvent :exec
+INSERT INTO
+	member_events (guild_id, user_id, type, actor_id, created_at)
+VALUES
+	(?, ?, ?, ?, ?)
+`
+
+type AddMemberEventParams struct {
+	GuildID   int64
+	UserID    int64
+	Type      string
+	ActorID   sql.NullInt64
+	CreatedAt int64
+}
+
+func (q *Queries) AddMemberEvent(ctx context.Context, arg AddMemberEventParams) error {
+	_, err := q.db.ExecContext(ctx, addMemberEvent,
+		arg.GuildID,
+		arg.UserID,
+		arg.Type,
+		arg.ActorID,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const addPINAttempt = `-- name: AddPINAttempt :exec
+INSERT INTO
+	pin_attempts (guild_id, user_id, success, attempted_at)
+SELECT
+	known_guilds.guild_id,
+	?1,
+	?2,
+	?3
+FROM
+	known_guilds
+WHERE
+	known_guilds.guild_id = ?4
+`
+
+type AddPINAttemptParams struct {
+	UserID      int64
+	Success     bool
+	AttemptedAt int64
+	GuildID     int64
+}
+
+// Attempts in unknown guilds are not recorded.
+func (q *Queries) AddPINAttempt(ctx context.Context, arg AddPINAttemptParams) error {
+	_, err := q.db.ExecContext(ctx, addPINAttempt,
+		arg.UserID,
+		arg.Success,
+		arg.AttemptedAt,
+		arg.GuildID,
+	)
+	return err
+}
+
+const approvalInfo = `-- name: ApprovalInfo :one
+SELECT
+	metadata,
+	submitted_at,
+	denied_at,
+	denied_by,
+	deny_reason
+FROM
+	registration_approvals
+WHERE
+	guild_id = ?
+	AND user_id = ?
+`
+
+type ApprovalInfoParams struct {
+	GuildID int64
+	UserID  int64
+}
+
+type ApprovalInfoRow struct {
+	Metadata    string
+	SubmittedAt int64
+	DeniedAt    sql.NullInt64
+	DeniedBy    sql.NullInt64
+	DenyReason  string
+}
+
+func (q *Queries) ApprovalInfo(ctx context.Context, arg ApprovalInfoParams) (ApprovalInfoRow, error) {
+	row := q.db.QueryRowContext(ctx, approvalInfo, arg.GuildID, arg.UserID)
+	var i ApprovalInfoRow
+	err := row.Scan(
+		&i.Metadata,
+		&i.SubmittedAt,
+		&i.DeniedAt,
+		&i.DeniedBy,
+		&i.DenyReason,
+	)
+	return i, err
+}
+
+const checkIn = `-- name: CheckIn :exec
+INSERT INTO
+	event_attendance (guild_id, event_id, user_id, checked_in_at)
+VALUES
+	(?, ?, ?, ?)
+`
+
+type CheckInParams struct {
+	GuildID     int64
+	EventID     int64
+	UserID      int64
+	CheckedInAt int64
+}
+
+func (q *Queries) CheckIn(ctx context.Context, arg CheckInParams) error {
+	_, err := q.db.ExecContext(ctx, checkIn,
+		arg.GuildID,
+		arg.EventID,
+		arg.UserID,
+		arg.CheckedInAt,
+	)
+	return err
+}
+
+const cleanupSubmissions = `-- name: CleanupSubmissions :exec
+DELETE FROM
+	registration_submissions
+WHERE
+	expire_at < ?1
+`
+
+func (q *Queries) CleanupSubmissions(ctx context.Context, now int64) error {
+	_, err := q.db.ExecContext(ctx, cleanupSubmissions, now)
+	return err
+}
+
+const countMembers = `-- name: CountMembers :one
+SELECT
+	COUNT(*)
+FROM
+	members
+WHERE
+	guild_id = ?
+`
+
+func (q *Queries) CountMembers(ctx context.Context, guildID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countMembers, guildID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countMembersByEmailHost = `-- name: CountMembersByEmailHost :many
+SELECT
+	CAST(LOWER(SUBSTR(email, INSTR(email, '@') + 1)) AS TEXT) AS host,
+	COUNT(*) AS members
+FROM
+	members
+WHERE
+	guild_id = ?
+GROUP BY
+	host
+`
+
+type CountMembersByEmailHostRow struct {
+	Host    string
+	Members int64
+}
+
+func (q *Queries) CountMembersByEmailHost(ctx context.Context, guildID int64) ([]CountMembersByEmailHostRow, error) {
+	rows, err := q.db.QueryContext(ctx, countMembersByEmailHost, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountMembersByEmailHostRow
+	for rows.Next() {
+		var i CountMembersByEmailHostRow
+		if err := rows.Scan(&i.Host, &i.Members); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countMembersByPronouns = `-- name: CountMembersByPronouns :many
+SELECT
+	CAST(IFNULL(json_extract(metadata, '$.pronouns'), '') AS TEXT) AS pronouns,
+	COUNT(*) AS members
+FROM
+	members
+WHERE
+	guild_id = ?
+GROUP BY
+	pronouns
+`
+
+type CountMembersByPronounsRow struct {
+	Pronouns string
+	Members  int64
+}
+
+func (q *Queries) CountMembersByPronouns(ctx context.Context, guildID int64) ([]CountMembersByPronounsRow, error) {
+	rows, err := q.db.QueryContext(ctx, countMembersByPronouns, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountMembersByPronounsRow
+	for rows.Next() {
+		var i CountMembersByPronounsRow
+		if err := rows.Scan(&i.Pronouns, &i.Members); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countMembersPerWeek = `-- name: CountMembersPerWeek :many
+SELECT
+	CAST((registered_at - ?1) / 604800 AS INTEGER) AS week,
+	COUNT(*) AS members
+FROM
+	members
+WHERE
+	guild_id = ?2
+	AND registered_at >= ?1
+GROUP BY
+	week
+`
+
+type CountMembersPerWeekParams struct {
+	Since   sql.NullInt64
+	GuildID int64
+}
+
+type CountMembersPerWeekRow struct {
+	Week    int64
+	Members int64
+}
+
+func (q *Queries) CountMembersPerWeek(ctx context.Context, arg CountMembersPerWeekParams) ([]CountMembersPerWeekRow, error) {
+	rows, err := q.db.QueryContext(ctx, countMembersPerWeek, arg.Since, arg.GuildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountMembersPerWeekRow
+	for rows.Next() {
+		var i CountMembersPerWeekRow
+		if err := rows.Scan(&i.Week, &i.Members); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countPINAttempts = `-- name: CountPINAttempts :one
+SELECT
+	COUNT(*) AS attempts,
+	COUNT(
+		CASE
+			WHEN success THEN 1
+		END
+	) AS successes
+FROM
+	pin_attempts
+WHERE
+	guild_id = ?
+	AND attempted_at >= ?2
+`
+
+type CountPINAttemptsParams struct {
+	GuildID int64
+	Since   int64
+}
+
+type CountPINAttemptsRow struct {
+	Attempts  int64
+	Successes int64
+}
+
+func (q *Queries) CountPINAttempts(ctx context.Context, arg CountPINAttemptsParams) (CountPINAttemptsRow, error) {
+	row := q.db.QueryRowContext(ctx, countPINAttempts, arg.GuildID, arg.Since)
+	var i CountPINAttemptsRow
+	err := row.Scan(&i.Attempts, &i.Successes)
+	return i, err
+}
+
+const countPendingApprovals = `-- name: CountPendingApprovals :one
+SELECT
+	COUNT(*)
+FROM
+	registration_approvals
+WHERE
+	guild_id = ?
+	AND denied_at IS NULL
+`
+
+func (q *Queries) CountPendingApprovals(ctx context.Context, guildID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPendingApprovals, guildID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPendingSubmissions = `-- name: CountPendingSubmissions :one
+SELECT
+	COUNT(*)
+FROM
+	registration_submissions
+WHERE
+	guild_id = ?
+	AND expire_at >= ?2
+`
+
+type CountPendingSubmissionsParams struct {
+	GuildID int64
+	Now     int64
+}
+
+func (q *Queries) CountPendingSubmissions(ctx context.Context, arg CountPendingSubmissionsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPendingSubmissions, arg.GuildID, arg.Now)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteGuild = `-- name: DeleteGuild :execrows
+DELETE FROM
+	known_guilds
+WHERE
+	guild_id = ?
+`
+
+func (q *Queries) DeleteGuild(ctx context.Context, guildID int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteGuild, guildID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deletePendingApproval = `-- name: DeletePendingApproval :execrows
+DELETE FROM
+	registration_approvals
+WHERE
+	guild_id = ?
+	AND user_id = ?
+	AND denied_at IS NULL
+`
+
+type DeletePendingApprovalParams struct {
+	GuildID int64
+	UserID  int64
+}
+
+func (q *Queries) DeletePendingApproval(ctx context.Context, arg DeletePendingApprovalParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePendingApproval, arg.GuildID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteSubmission = `-- name: DeleteSubmission :exec
+DELETE FROM
+	registration_submissions
+WHERE
+	guild_id = ?
+	AND user_id = ?
+`
+
+type DeleteSubmissionParams struct {
+	GuildID int64
+	UserID  int64
+}
+
+func (q *Queries) DeleteSubmission(ctx context.Context, arg DeleteSubmissionParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSubmission, arg.GuildID, arg.UserID)
+	return err
+}
+
+const denyApproval = `-- name: DenyApproval :execrows
+UPDATE
+	registration_approvals
+SET
+	denied_at = ?,
+	denied_by = ?,
+	deny_reason = ?
+WHERE
+	guild_id = ?
+	AND user_id = ?
+	AND denied_at IS NULL
+`
+
+type DenyApprovalParams struct {
+	DeniedAt   sql.NullInt64
+	DeniedBy   sql.NullInt64
+	DenyReason string
+	GuildID    int64
+	UserID     int64
+}
+
+func (q *Queries) DenyApproval(ctx context.Context, arg DenyApprovalParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, denyApproval,
+		arg.DeniedAt,
+		arg.DeniedBy,
+		arg.DenyReason,
+		arg.GuildID,
+		arg.UserID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const eventAttendees = `-- name: EventAttendees :many
+SELECT
+	user_id,
+	checked_in_at
+FROM
+	event_attendance
+WHERE
+	guild_id = ?
+	AND event_id = ?
+ORDER BY
+	checked_in_at ASC,
+	user_id ASC
+`
+
+type EventAttendeesParams struct {
+	GuildID int64
+	EventID int64
+}
+
+type EventAttendeesRow struct {
+	UserID      int64
+	CheckedInAt int64
+}
+
+func (q *Queries) EventAttendees(ctx context.Context, arg EventAttendeesParams) ([]EventAttendeesRow, error) {
+	rows, err := q.db.QueryContext(ctx, eventAttendees, arg.GuildID, arg.EventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EventAttendeesRow
+	for rows.Next() {
+		var i EventAttendeesRow
+		if err := rows.Scan(&i.UserID, &i.CheckedInAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const eventInfo = `-- name: EventInfo :one
+SELECT
+	name,
+	start_time,
+	ended
+FROM
+	scheduled_events
+WHERE
+	guild_id = ?
+	AND event_id = ?
+`
+
+type EventInfoParams struct {
+	GuildID int64
+	EventID int64
+}
+
+type EventInfoRow struct {
+	Name      string
+	StartTime int64
+	Ended     bool
+}
+
+func (q *Queries) EventInfo(ctx context.Context, arg EventInfoParams) (EventInfoRow, error) {
+	row := q.db.QueryRowContext(ctx, eventInfo, arg.GuildID, arg.EventID)
+	var i EventInfoRow
+	err := row.Scan(&i.Name, &i.StartTime, &i.Ended)
+	return i, err
+}
+
+const eventParticipants = `-- name: EventParticipants :many
+SELECT
+	user_id
+FROM
+	event_participants
+WHERE
+	guild_id = ?
+	AND event_id = ?
+ORDER BY
+	user_id ASC
+`
+
+type EventParticipantsParams struct {
+	GuildID int64
+	EventID int64
+}
+
+func (q *Queries) EventParticipants(ctx context.Context, arg EventParticipantsParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, eventParticipants, arg.GuildID, arg.EventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var user_id int64
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const eventsBetween = `-- name: EventsBetween :many
+SELECT
+	event_id,
+	name,
+	start_time,
+	ended
+FROM
+	scheduled_events
+WHERE
+	guild_id = ?1
+	AND start_time >= ?2
+	AND start_time < ?3
+ORDER BY
+	start_time ASC,
+	event_id ASC
+`
+
+type EventsBetweenParams struct {
+	GuildID  int64
+	FromTime int64
+	ToTime   int64
+}
+
+type EventsBetweenRow struct {
+	EventID   int64
+	Name      string
+	StartTime int64
+	Ended     bool
+}
+
+func (q *Queries) EventsBetween(ctx context.Context, arg EventsBetweenParams) ([]EventsBetweenRow, error) {
+	rows, err := q.db.QueryContext(ctx, eventsBetween, arg.GuildID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EventsBetweenRow
+	for rows.Next() {
+		var i EventsBetweenRow
+		if err := rows.Scan(
+			&i.EventID,
+			&i.Name,
+			&i.StartTime,
+			&i.Ended,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const guildInfo = `-- name: GuildInfo :one
+SELECT
+	guild_id, channel_id, role_id, init_user_id, registered_message, admin_role_id, messages, register_message_id, form_fields, nickname, approval_channel_id, audit_channel_id
+FROM
+	known_guilds
+WHERE
+	guild_id = ?
+LIMIT
+	1
+`
+
+// Language: sqlite
+func (q *Queries) GuildInfo(ctx context.Context, guildID int64) (KnownGuild, error) {
+	row := q.db.QueryRowContext(ctx, guildInfo, guildID)
+	var i KnownGuild
+	err := row.Scan(
+		&i.GuildID,
+		&i.ChannelID,
+		&i.RoleID,
+		&i.InitUserID,
+		&i.RegisteredMessage,
+		&i.AdminRoleID,
+		&i.Messages,
+		&i.RegisterMessageID,
+		&i.FormFields,
+		&i.Nickname,
+		&i.ApprovalChannelID,
+		&i.AuditChannelID,
+	)
+	return i, err
+}
+
+const initGuild = `-- name: InitGuild :exec
+INSERT INTO
+	known_guilds (
+		guild_id,
+		channel_id,
+		init_user_id,
+		role_id,
+		registered_message,
+		messages,
+		register_message_id,
+		form_fields,
+		nickname,
+		approval_channel_id,
+		audit_channel_id
+	)
+VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type InitGuildParams struct {
+	GuildID           int64
+	ChannelID         int64
+	InitUserID        int64
+	RoleID            int64
+	RegisteredMessage string
+	Messages          string
+	RegisterMessageID sql.NullInt64
+	FormFields        string
+	Nickname          string
+	ApprovalChannelID sql.NullInt64
+	AuditChannelID    sql.NullInt64
+}
+
+func (q *Queries) InitGuild(ctx context.Context, arg InitGuildParams) error {
+	_, err := q.db.ExecContext(ctx, initGuild,
+		arg.GuildID,
+		arg.ChannelID,
+		arg.InitUserID,
+		arg.RoleID,
+		arg.RegisteredMessage,
+		arg.Messages,
+		arg.RegisterMessageID,
+		arg.FormFields,
+		arg.Nickname,
+		arg.ApprovalChannelID,
+		arg.AuditChannelID,
+	)
+	return err
+}
+
+const insertPIN = `-- name: InsertPIN :exec
+INSERT INTO
+	pin_codes (guild_id, user_id, pin)
+VALUES
+	(?, ?, ?) ON CONFLICT (guild_id, user_id)
+DO
+UPDATE
+SET
+	pin = excluded.pin
+`
+
+type InsertPINParams struct {
+	GuildID int64
+	UserID  int64
+	Pin     int64
+}
+
+func (q *Queries) InsertPIN(ctx context.Context, arg InsertPINParams) error {
+	_, err := q.db.ExecContext(ctx, insertPIN, arg.GuildID, arg.UserID, arg.Pin)
+	return err
+}
+
+const listMembersAfter = `-- name: ListMembersAfter :many
+SELECT
+	user_id,
+	metadata,
+	registered_at,
+	registered_by,
+	departed_at
+FROM
+	members
+WHERE
+	guild_id = ?
+	AND user_id > ?
+ORDER BY
+	user_id ASC
+LIMIT
+	?
+`
+
+type ListMembersAfterParams struct {
+	GuildID int64
+	UserID  int64
+	Limit   int64
+}
+
+type ListMembersAfterRow struct {
+	UserID       int64
+	Metadata     string
+	RegisteredAt sql.NullInt64
+	RegisteredBy sql.NullInt64
+	DepartedAt   sql.NullInt64
+}
+
+func (q *Queries) ListMembersAfter(ctx context.Context, arg ListMembersAfterParams) ([]ListMembersAfterRow, error) {
+	rows, err := q.db.QueryContext(ctx, listMembersAfter, arg.GuildID, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMembersAfterRow
+	for rows.Next() {
+		var i ListMembersAfterRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Metadata,
+			&i.RegisteredAt,
+			&i.RegisteredBy,
+			&i.DepartedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMembersBefore = `-- name: ListMembersBefore :many
+SELECT
+	user_id,
+	metadata,
+	registered_at,
+	registered_by,
+	departed_at
+FROM
+	members
+WHERE
+	guild_id = ?
+	AND user_id < ?
+ORDER BY
+	user_id DESC
+LIMIT
+	?
+`
+
+type ListMembersBeforeParams struct {
+	GuildID int64
+	UserID  int64
+	Limit   int64
+}
+
+type ListMembersBeforeRow struct {
+	UserID       int64
+	Metadata     string
+	RegisteredAt sql.NullInt64
+	RegisteredBy sql.NullInt64
+	DepartedAt   sql.NullInt64
+}
+
+func (q *Queries) ListMembersBefore(ctx context.Context, arg ListMembersBeforeParams) ([]ListMembersBeforeRow, error) {
+	rows, err := q.db.QueryContext(ctx, listMembersBefore, arg.GuildID, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMembersBeforeRow
+	for rows.Next() {
+		var i ListMembersBeforeRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Metadata,
+			&i.RegisteredAt,
+			&i.RegisteredBy,
+			&i.DepartedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const memberEvents = `-- name: MemberEvents :many
+SELECT
+	type,
+	actor_id,
+	created_at
+FROM
+	member_events
+WHERE
+	guild_id = ?
+	AND user_id = ?
+ORDER BY
+	created_at ASC,
+	id ASC
+`
+
+type MemberEventsParams struct {
+	GuildID int64
+	UserID  int64
+}
+
+type MemberEventsRow struct {
+	Type      string
+	ActorID   sql.NullInt64
+	CreatedAt int64
+}
+
+func (q *Queries) MemberEvents(ctx context.Context, arg MemberEventsParams) ([]MemberEventsRow, error) {
+	rows, err := q.db.QueryContext(ctx, memberEvents, arg.GuildID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MemberEventsRow
+	for rows.Next() {
+		var i MemberEventsRow
+		if err := rows.Scan(&i.Type, &i.ActorID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const memberInfo = `-- name: MemberInfo :one
+SELECT
+	metadata
+FROM
+	members
+WHERE
+	guild_id = ?
+	AND user_id = ?
+`
+
+type MemberInfoParams struct {
+	GuildID int64
+	UserID  int64
+}
+
+func (q *Queries) MemberInfo(ctx context.Context, arg MemberInfoParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, memberInfo, arg.GuildID, arg.UserID)
+	var metadata string
+	err := row.Scan(&metadata)
+	return metadata, err
+}
+
+const registerMember = `-- name: RegisterMember :exec
+INSERT INTO
+	members (guild_id, user_id, email, metadata, registered_at, registered_by)
+VALUES
+	(?, ?, ?, ?, ?, ?)
+`
+
+type RegisterMemberParams struct {
+	GuildID      int64
+	UserID       int64
+	Email        string
+	Metadata     string
+	RegisteredAt sql.NullInt64
+	RegisteredBy sql.NullInt64
+}
+
+func (q *Queries) RegisterMember(ctx context.Context, arg RegisterMemberParams) error {
+	_, err := q.db.ExecContext(ctx, registerMember,
+		arg.GuildID,
+		arg.UserID,
+		arg.Email,
+		arg.Metadata,
+		arg.RegisteredAt,
+		arg.RegisteredBy,
+	)
+	return err
+}
+
+const removeEventParticipant = `-- name: RemoveEventParticipant :exec
+DELETE FROM
+	event_participants
+WHERE
+	guild_id = ?
+	AND event_id = ?
+	AND user_id = ?
+`
+
+type RemoveEventParticipantParams struct {
+	GuildID int64
+	EventID int64
+	UserID  int64
+}
+
+func (q *Queries) RemoveEventParticipant(ctx context.Context, arg RemoveEventParticipantParams) error {
+	_, err := q.db.ExecContext(ctx, removeEventParticipant, arg.GuildID, arg.EventID, arg.UserID)
+	return err
+}
+
+const restoreSubmission = `-- name: RestoreSubmission :one
+SELECT
+	metadata
+FROM
+	registration_submissions
+WHERE
+	guild_id = ?
+	AND user_id = ?
+	AND expire_at >= ?3
+`
+
+type RestoreSubmissionParams struct {
+	GuildID int64
+	UserID  int64
+	Now     int64
+}
+
+func (q *Queries) RestoreSubmission(ctx context.Context, arg RestoreSubmissionParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, restoreSubmission, arg.GuildID, arg.UserID, arg.Now)
+	var metadata string
+	err := row.Scan(&metadata)
+	return metadata, err
+}
+
+const saveEvent = `-- name: SaveEvent :exec
+INSERT INTO
+	scheduled_events (guild_id, event_id, name, start_time, ended)
+VALUES
+	(?, ?, ?, ?, ?) ON CONFLICT (guild_id, event_id)
+DO
+UPDATE
+SET
+	name = EXCLUDED.name,
+	start_time = EXCLUDED.start_time,
+	ended = EXCLUDED.ended
+`
+
+type SaveEventParams struct {
+	GuildID   int64
+	EventID   int64
+	Name      string
+	StartTime int64
+	Ended     bool
+}
+
+func (q *Queries) SaveEvent(ctx context.Context, arg SaveEventParams) error {
+	_, err := q.db.ExecContext(ctx, saveEvent,
+		arg.GuildID,
+		arg.EventID,
+		arg.Name,
+		arg.StartTime,
+		arg.Ended,
+	)
+	return err
+}
+
+const saveSubmission = `-- name: SaveSubmission :exec
+INSERT INTO
+	registration_submissions (guild_id, user_id, metadata, expire_at)
+VALUES
+	(?, ?, ?, ?) ON CONFLICT (guild_id, user_id)
+DO
+UPDATE
+SET
+	metadata = excluded.metadata,
+	expire_at = excluded.expire_at
+`
+
+type SaveSubmissionParams struct {
+	GuildID  int64
+	UserID   int64
+	Metadata string
+	ExpireAt int64
+}
+
+func (q *Queries) SaveSubmission(ctx context.Context, arg SaveSubmissionParams) error {
+	_, err := q.db.ExecContext(ctx, saveSubmission,
+		arg.GuildID,
+		arg.UserID,
+		arg.Metadata,
+		arg.ExpireAt,
+	)
+	return err
+}
+
+const searchEvents = `-- name: SearchEvents :many
+SELECT
+	event_id,
+	name,
+	start_time,
+	ended
+FROM
+	scheduled_events
+WHERE
+	guild_id = ?1
+	AND name LIKE CAST(?2 AS TEXT) ESCAPE '\'
+ORDER BY
+	start_time DESC,
+	event_id DESC
+LIMIT
+	?3
+`
+
+type SearchEventsParams struct {
+	GuildID    int64
+	Pattern    string
+	MaxResults int64
+}
+
+type SearchEventsRow struct {
+	EventID   int64
+	Name      string
+	StartTime int64
+	Ended     bool
+}
+
+func (q *Queries) SearchEvents(ctx context.Context, arg SearchEventsParams) ([]SearchEventsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchEvents, arg.GuildID, arg.Pattern, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchEventsRow
+	for rows.Next() {
+		var i SearchEventsRow
+		if err := rows.Scan(
+			&i.EventID,
+			&i.Name,
+			&i.StartTime,
+			&i.Ended,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchMembers = `-- name: SearchMembers :many
+SELECT
+	user_id,
+	metadata,
+	registered_at,
+	registered_by,
+	departed_at
+FROM
+	members
+WHERE
+	guild_id = ?1
+	-- Match against "email\nfirst last", so that the full name can also be
+	-- searched.
+	AND (
+		email || char(10) || IFNULL(json_extract(metadata, '$.first_name'), '') || ' ' || IFNULL(json_extract(metadata, '$.last_name'), '')
+	) LIKE CAST(?2 AS TEXT) ESCAPE '\'
+ORDER BY
+	user_id ASC
+LIMIT
+	?3
+`
+
+type SearchMembersParams struct {
+	GuildID    int64
+	Pattern    string
+	MaxResults int64
+}
+
+type SearchMembersRow struct {
+	UserID       int64
+	Metadata     string
+	RegisteredAt sql.NullInt64
+	RegisteredBy sql.NullInt64
+	DepartedAt   sql.NullInt64
+}
+
+func (q *Queries) SearchMembers(ctx context.Context, arg SearchMembersParams) ([]SearchMembersRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchMembers, arg.GuildID, arg.Pattern, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchMembersRow
+	for rows.Next() {
+		var i SearchMembersRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Metadata,
+			&i.RegisteredAt,
+			&i.RegisteredBy,
+			&i.DepartedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setGuildAdminRoleID = `-- name: SetGuildAdminRoleID :execrows
+UPDATE
+	known_guilds
+SET
+	admin_role_id = ?
+WHERE
+	guild_id = ?
+`
+
+type SetGuildAdminRoleIDParams struct {
+	AdminRoleID sql.NullInt64
+	GuildID     int64
+}
+
+func (q *Queries) SetGuildAdminRoleID(ctx context.Context, arg SetGuildAdminRoleIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setGuildAdminRoleID, arg.AdminRoleID, arg.GuildID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setGuildApprovalChannelID = `-- name: SetGuildApprovalChannelID :execrows
+UPDATE
+	known_guilds
+SET
+	approval_channel_id = ?
+WHERE
+	guild_id = ?
+`
+
+type SetGuildApprovalChannelIDParams struct {
+	ApprovalChannelID sql.NullInt64
+	GuildID           int64
+}
+
+func (q *Queries) SetGuildApprovalChannelID(ctx context.Context, arg SetGuildApprovalChannelIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setGuildApprovalChannelID, arg.ApprovalChannelID, arg.GuildID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setGuildAuditChannelID = `-- name: SetGuildAuditChannelID :execrows
+UPDATE
+	known_guilds
+SET
+	audit_channel_id = ?
+WHERE
+	guild_id = ?
+`
+
+type SetGuildAuditChannelIDParams struct {
+	AuditChannelID sql.NullInt64
+	GuildID        int64
+}
+
+func (q *Queries) SetGuildAuditChannelID(ctx context.Context, arg SetGuildAuditChannelIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setGuildAuditChannelID, arg.AuditChannelID, arg.GuildID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setGuildFormFields = `-- name: SetGuildFormFields :execrows
+UPDATE
+	known_guilds
+SET
+	form_fields = ?
+WHERE
+	guild_id = ?
+`
+
+type SetGuildFormFieldsParams struct {
+	FormFields string
+	GuildID    int64
+}
+
+func (q *Queries) SetGuildFormFields(ctx context.Context, arg SetGuildFormFieldsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setGuildFormFields, arg.FormFields, arg.GuildID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setGuildMessages = `-- name: SetGuildMessages :execrows
+UPDATE
+	known_guilds
+SET
+	registered_message = ?,
+	messages = ?
+WHERE
+	guild_id = ?
+`
+
+type SetGuildMessagesParams struct {
+	RegisteredMessage string
+	Messages          string
+	GuildID           int64
+}
+
+func (q *Queries) SetGuildMessages(ctx context.Context, arg SetGuildMessagesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setGuildMessages, arg.RegisteredMessage, arg.Messages, arg.GuildID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setGuildNickname = `-- name: SetGuildNickname :execrows
+UPDATE
+	known_guilds
+SET
+	nickname = ?
+WHERE
+	guild_id = ?
+`
+
+type SetGuildNicknameParams struct {
+	Nickname string
+	GuildID  int64
+}
+
+func (q *Queries) SetGuildNickname(ctx context.Context, arg SetGuildNicknameParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setGuildNickname, arg.Nickname, arg.GuildID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setGuildRegisterMessage = `-- name: SetGuildRegisterMessage :execrows
+UPDATE
+	known_guilds
+SET
+	channel_id = ?,
+	register_message_id = ?
+WHERE
+	guild_id = ?
+`
+
+type SetGuildRegisterMessageParams struct {
+	ChannelID         int64
+	RegisterMessageID sql.NullInt64
+	GuildID           int64
+}
+
+func (q *Queries) SetGuildRegisterMessage(ctx context.Context, arg SetGuildRegisterMessageParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setGuildRegisterMessage, arg.ChannelID, arg.RegisterMessageID, arg.GuildID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setGuildRoleID = `-- name: SetGuildRoleID :execrows
+UPDATE
+	known_guilds
+SET
+	role_id = ?
+WHERE
+	guild_id = ?
+`
+
+type SetGuildRoleIDParams struct {
+	RoleID  int64
+	GuildID int64
+}
+
+func (q *Queries) SetGuildRoleID(ctx context.Context, arg SetGuildRoleIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setGuildRoleID, arg.RoleID, arg.GuildID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setMemberDepartedAt = `-- name: SetMemberDepartedAt :execrows
+UPDATE
+	members
+SET
+	departed_at = ?
+WHERE
+	guild_id = ?
+	AND user_id = ?
+`
+
+type SetMemberDepartedAtParams struct {
+	DepartedAt sql.NullInt64
+	GuildID    int64
+	UserID     int64
+}
+
+func (q *Queries) SetMemberDepartedAt(ctx context.Context, arg SetMemberDepartedAtParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setMemberDepartedAt, arg.DepartedAt, arg.GuildID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const submitApproval = `-- name: SubmitApproval :exec
+INSERT INTO
+	registration_approvals (guild_id, user_id, metadata, submitted_at)
+VALUES
+	(?, ?, ?, ?) ON CONFLICT (guild_id, user_id)
+DO
+UPDATE
+SET
+	metadata = EXCLUDED.metadata,
+	submitted_at = EXCLUDED.submitted_at,
+	denied_at = NULL,
+	denied_by = NULL,
+	deny_reason = ''
+`
+
+type SubmitApprovalParams struct {
+	GuildID     int64
+	UserID      int64
+	Metadata    string
+	SubmittedAt int64
+}
+
+func (q *Queries) SubmitApproval(ctx context.Context, arg SubmitApprovalParams) error {
+	_, err := q.db.ExecContext(ctx, submitApproval,
+		arg.GuildID,
+		arg.UserID,
+		arg.Metadata,
+		arg.SubmittedAt,
+	)
+	return err
+}
+
+const unregisterMember = `-- name: UnregisterMember :execrows
+DELETE FROM
+	members
+WHERE
+	guild_id = ?
+	AND user_id = ?
+`
+
+type UnregisterMemberParams struct {
+	GuildID int64
+	UserID  int64
+}
+
+func (q *Queries) UnregisterMember(ctx context.Context, arg UnregisterMemberParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unregisterMember, arg.GuildID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateMemberMetadata = `-- name: UpdateMemberMetadata :execrows
+UPDATE
+	members
+SET
+	email = ?,
+	metadata = ?
+WHERE
+	guild_id = ?
+	AND user_id = ?
+`
+
+type UpdateMemberMetadataParams struct {
+	Email    string
+	Metadata string
+	GuildID  int64
+	UserID   int64
+}
+
+func (q *Queries) UpdateMemberMetadata(ctx context.Context, arg UpdateMemberMetadataParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateMemberMetadata,
+		arg.Email,
+		arg.Metadata,
+		arg.GuildID,
+		arg.UserID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const validatePIN = `-- name: ValidatePIN :one
+SELECT
+	registration_submissions.metadata
+FROM
+	registration_submissions
+	JOIN pin_codes ON registration_submissions.guild_id = pin_codes.guild_id
+	AND registration_submissions.user_id = pin_codes.user_id
+WHERE
+	pin_codes.guild_id = ?
+	AND pin_codes.user_id = ?
+	AND pin_codes.pin = ?
+	AND registration_submissions.expire_at >= ?4
+`
+
+type ValidatePINParams struct {
+	GuildID int64
+	UserID  int64
+	Pin     int64
+	Now     int64
+}
+
+func (q *Queries) ValidatePIN(ctx context.Context, arg ValidatePINParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, validatePIN,
+		arg.GuildID,
+		arg.UserID,
+		arg.Pin,
+		arg.Now,
+	)
+	var metadata string
+	err := row.Scan(&metadata)
+	return metadata, err
+}
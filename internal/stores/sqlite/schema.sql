@@ -0,0 +1,224 @@
+-- Language: sqlite
+--
+CREATE TABLE
+	known_guilds (
+		guild_id INTEGER PRIMARY KEY,
+		channel_id INTEGER NOT NULL,
+		role_id INTEGER NOT NULL,
+		init_user_id INTEGER NOT NULL,
+		registered_message TEXT NOT NULL,
+		admin_role_id INTEGER
+	);
+
+CREATE TABLE
+	members (
+		guild_id INTEGER NOT NULL REFERENCES known_guilds(guild_id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL,
+		email TEXT NOT NULL,
+		metadata TEXT NOT NULL,
+		UNIQUE (guild_id, user_id),
+		UNIQUE (guild_id, email)
+	);
+
+CREATE TABLE
+	registration_submissions (
+		guild_id INTEGER NOT NULL REFERENCES known_guilds(guild_id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL,
+		metadata TEXT NOT NULL,
+		expire_at INTEGER NOT NULL, -- UNIX timestamp
+		UNIQUE (guild_id, user_id)
+	);
+
+CREATE TABLE
+	pin_codes (
+		guild_id INTEGER NOT NULL REFERENCES known_guilds(guild_id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL,
+		pin INTEGER NOT NULL,
+		UNIQUE (guild_id, user_id),
+		UNIQUE (guild_id, pin),
+		FOREIGN KEY (guild_id, user_id) REFERENCES registration_submissions(guild_id, user_id) ON DELETE CASCADE
+	);
+
+-- NEW VERSION
+-- Add the registered_at column to the members table. Older members don't have
+-- a known registration time.
+ALTER TABLE
+	members
+ADD COLUMN
+	registered_at INTEGER; -- UNIX timestamp
+
+-- NEW VERSION
+-- Add the registered_by column to the members table. It is NULL for members who
+-- registered themselves.
+ALTER TABLE
+	members
+ADD COLUMN
+	registered_by INTEGER;
+
+-- NEW VERSION
+-- Add the departed_at column to the members table. It is set when the member
+-- leaves the guild and cleared when they rejoin.
+ALTER TABLE
+	members
+ADD COLUMN
+	departed_at INTEGER; -- UNIX timestamp
+
+-- NEW VERSION
+-- Add the messages column to the known_guilds table. It holds the guild's
+-- customized messages; missing ones use the defaults.
+ALTER TABLE
+	known_guilds
+ADD COLUMN
+	messages TEXT NOT NULL DEFAULT '{}'; -- JSON
+
+-- NEW VERSION
+-- Add the register_message_id column to the known_guilds table. It is NULL for
+-- guilds that posted their register message before this was recorded.
+ALTER TABLE
+	known_guilds
+ADD COLUMN
+	register_message_id INTEGER;
+
+-- NEW VERSION
+-- Add the form_fields column to the known_guilds table. It holds the guild's
+-- custom registration form fields.
+ALTER TABLE
+	known_guilds
+ADD COLUMN
+	form_fields TEXT NOT NULL DEFAULT '[]'; -- JSON
+
+-- NEW VERSION
+-- Add the nickname column to the known_guilds table. It holds how the guild
+-- nicknames its registered members; an empty object uses the defaults.
+ALTER TABLE
+	known_guilds
+ADD COLUMN
+	nickname TEXT NOT NULL DEFAULT '{}'; -- JSON
+
+-- NEW VERSION
+-- Add the approval_channel_id column to the known_guilds table. Registrations
+-- need approval if it is set.
+ALTER TABLE
+	known_guilds
+ADD COLUMN
+	approval_channel_id INTEGER;
+
+-- Registrations waiting for approval. Denied registrations are kept until the
+-- member registers again.
+CREATE TABLE
+	registration_approvals (
+		guild_id INTEGER NOT NULL REFERENCES known_guilds(guild_id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL,
+		metadata TEXT NOT NULL, -- JSON
+		submitted_at INTEGER NOT NULL, -- UNIX timestamp
+		denied_at INTEGER, -- UNIX timestamp
+		denied_by INTEGER,
+		deny_reason TEXT NOT NULL DEFAULT '',
+		UNIQUE(guild_id, user_id)
+	);
+
+-- NEW VERSION
+-- Add the audit_channel_id column to the known_guilds table. Changes to the
+-- registration are logged to it if it is set.
+ALTER TABLE
+	known_guilds
+ADD COLUMN
+	audit_channel_id INTEGER;
+
+-- NEW VERSION
+-- The append-only history of registration events of each member. It is kept
+-- after the member is unregistered.
+CREATE TABLE
+	member_events (
+		id INTEGER PRIMARY KEY,
+		guild_id INTEGER NOT NULL REFERENCES known_guilds(guild_id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL,
+		type TEXT NOT NULL,
+		actor_id INTEGER,
+		created_at INTEGER NOT NULL -- UNIX timestamp
+	);
+
+CREATE INDEX
+	member_events_member ON member_events (guild_id, user_id);
+
+-- NEW VERSION
+-- Every attempt to verify a PIN, for computing the verification success rate.
+CREATE TABLE
+	pin_attempts (
+		guild_id INTEGER NOT NULL REFERENCES known_guilds(guild_id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL,
+		success BOOLEAN NOT NULL,
+		attempted_at INTEGER NOT NULL -- UNIX timestamp
+	);
+
+CREATE INDEX
+	pin_attempts_guild ON pin_attempts (guild_id, attempted_at);
+
+-- NEW VERSION
+-- Members that checked in to scheduled events.
+CREATE TABLE
+	event_attendance (
+		guild_id INTEGER NOT NULL REFERENCES known_guilds(guild_id) ON DELETE CASCADE,
+		event_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL,
+		checked_in_at INTEGER NOT NULL, -- UNIX timestamp
+		UNIQUE(guild_id, event_id, user_id)
+	);
+
+-- NEW VERSION
+-- Snapshots of scheduled events, which Discord forgets some time after they
+-- end.
+CREATE TABLE
+	scheduled_events (
+		guild_id INTEGER NOT NULL REFERENCES known_guilds(guild_id) ON DELETE CASCADE,
+		event_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		start_time INTEGER NOT NULL, -- UNIX timestamp
+		ended BOOLEAN NOT NULL,
+		UNIQUE(guild_id, event_id)
+	);
+
+-- Users interested in scheduled events. Users may be added before the event
+-- itself is saved, so this doesn't reference scheduled_events.
+CREATE TABLE
+	event_participants (
+		guild_id INTEGER NOT NULL REFERENCES known_guilds(guild_id) ON DELETE CASCADE,
+		event_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL,
+		UNIQUE(guild_id, event_id, user_id)
+	);
+
+-- NEW VERSION
+-- Keep the member history after the guild is deleted, so that clearing the
+-- registration doesn't lose it. SQLite can't drop a foreign key, so the table
+-- is rebuilt without it.
+CREATE TABLE
+	member_events_new (
+		id INTEGER PRIMARY KEY,
+		guild_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL,
+		type TEXT NOT NULL,
+		actor_id INTEGER,
+		created_at INTEGER NOT NULL -- UNIX timestamp
+	);
+
+INSERT INTO
+	member_events_new (id, guild_id, user_id, type, actor_id, created_at)
+SELECT
+	id,
+	guild_id,
+	user_id,
+	type,
+	actor_id,
+	created_at
+FROM
+	member_events;
+
+DROP TABLE
+	member_events;
+
+ALTER TABLE
+	member_events_new RENAME TO member_events;
+
+CREATE INDEX
+	member_events_member ON member_events (guild_id, user_id);
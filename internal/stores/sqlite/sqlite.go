@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	_ "embed"
+
+	"github.com/pkg/errors"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+//go:embed schema.sql
+var schemaSQL string
+
+// Versions returns the versions of the schema.
+func Versions() []string {
+	return strings.Split(schemaSQL, "-- NEW VERSION\n")
+}
+
+// pragmas are applied to every new connection.
+var pragmas = []string{
+	"foreign_keys(1)",
+	"busy_timeout(10000)",
+	"journal_mode(WAL)",
+}
+
+// Open opens an SQLite database at the given path. The path may be
+// ":memory:" for a temporary database.
+func Open(path string) (*sql.DB, error) {
+	q := url.Values{"_pragma": pragmas}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+q.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite only allows one writer at a time anyway, and an in-memory
+	// database is private to its own connection.
+	db.SetMaxOpenConns(1)
+
+	return db, nil
+}
+
+// Migrate migrates the given database to the latest migrations. It uses the
+// user_version pragma.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "cannot begin transaction")
+	}
+	defer tx.Rollback()
+
+	var v int
+	if err := tx.QueryRowContext(ctx, "PRAGMA user_version").Scan(&v); err != nil {
+		return errors.Wrap(err, "cannot get schema version")
+	}
+
+	versions := Versions()
+	if v >= len(versions) {
+		return nil
+	}
+
+	for i := v; i < len(versions); i++ {
+		_, err := tx.ExecContext(ctx, versions[i])
+		if err != nil {
+			return errors.Wrapf(err, "cannot apply migration %d (from 0th)", i)
+		}
+	}
+
+	// PRAGMA doesn't take parameters.
+	_, err = tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", len(versions)))
+	if err != nil {
+		return errors.Wrap(err, "cannot save schema version")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "cannot commit new migrations")
+	}
+
+	return nil
+}
+
+// IsConstraintFailed returns true if err is returned because of a unique
+// constraint violation.
+func IsConstraintFailed(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return true
+	default:
+		return false
+	}
+}
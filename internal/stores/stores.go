@@ -1,11 +1,15 @@
 package stores
 
 import (
+	"encoding/json"
 	"io"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/diamondburned/acmregister/acmregister"
 	"github.com/diamondburned/acmregister/acmregister/verifyemail"
+	"github.com/pkg/errors"
 )
 
 type StoreCloser interface {
@@ -20,3 +24,150 @@ func Must[T acmregister.Store](store T, err error) T {
 	}
 	return store
 }
+
+func unmarshalMetadata(b []byte) (*acmregister.MemberMetadata, error) {
+	var metadata acmregister.MemberMetadata
+	if err := json.Unmarshal(b, &metadata); err != nil {
+		return nil, errors.Wrap(err, "member metadata JSON is corrupted")
+	}
+	return &metadata, nil
+}
+
+// unmarshalMemberMetadata is like unmarshalMetadata, except it is used for
+// metadata stored in the members table.
+func unmarshalMemberMetadata(b []byte) (*acmregister.MemberMetadata, error) {
+	metadata, err := unmarshalMetadata(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata.IsZero() {
+		// We used to have a bug where [acmregister.Member] was used for
+		// marshaling, so we have to try and fix that.
+		var memberFix struct {
+			Metadata acmregister.MemberMetadata
+		}
+		if err := json.Unmarshal(b, &memberFix); err != nil {
+			return nil, errors.Wrap(err, "member metadata JSON is corrupted")
+		}
+		if memberFix.Metadata.IsZero() {
+			return nil, errors.New("member metadata is empty")
+		}
+		return &memberFix.Metadata, nil
+	}
+
+	return metadata, nil
+}
+
+var likeEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`%`, `\%`,
+	`_`, `\_`,
+)
+
+// containsPattern returns a LIKE pattern that matches any string containing
+// the given query. The escape character is a backslash.
+func containsPattern(query string) string {
+	return "%" + likeEscaper.Replace(query) + "%"
+}
+
+// registeredAt returns the time that the member should be recorded as
+// registered at.
+func registeredAt(m acmregister.Member) time.Time {
+	if m.RegisteredAt.IsZero() {
+		return time.Now()
+	}
+	return m.RegisteredAt
+}
+
+// submittedAt returns the time that the approval should be recorded as
+// submitted at.
+func submittedAt(a acmregister.Approval) time.Time {
+	if a.SubmittedAt.IsZero() {
+		return time.Now()
+	}
+	return a.SubmittedAt
+}
+
+// checkedInAt returns the time that the attendance should be recorded at.
+func checkedInAt(a acmregister.Attendance) time.Time {
+	if a.CheckedInAt.IsZero() {
+		return time.Now()
+	}
+	return a.CheckedInAt
+}
+
+// registeredEvent returns the event recorded for the member being registered.
+// The member is credited for registering themselves unless someone else
+// registered them.
+func registeredEvent(m acmregister.Member) acmregister.MemberEvent {
+	actorID := m.RegisteredBy
+	if !actorID.IsValid() {
+		actorID = m.UserID
+	}
+	return acmregister.MemberEvent{
+		GuildID: m.GuildID,
+		UserID:  m.UserID,
+		Type:    acmregister.MemberRegistered,
+		ActorID: actorID,
+		Time:    registeredAt(m),
+	}
+}
+
+// eventTime returns the time that the event should be recorded at.
+func eventTime(ev acmregister.MemberEvent) time.Time {
+	if ev.Time.IsZero() {
+		return time.Now()
+	}
+	return ev.Time
+}
+
+// week is the length of each week in RegistrationStats.Weekly.
+const week = 7 * 24 * time.Hour
+
+// newRegistrationStats returns empty statistics with a week for every week
+// since the given time.
+func newRegistrationStats(since time.Time) *acmregister.RegistrationStats {
+	weeks := 1
+	if d := time.Since(since); d > 0 {
+		weeks = int(d/week) + 1
+	}
+	return &acmregister.RegistrationStats{
+		Weekly:     make([]int, weeks),
+		EmailHosts: make(map[string]int),
+		Pronouns:   make(map[acmregister.Pronouns]int),
+	}
+}
+
+// addWeekly adds n registrations to the given week. Weeks out of range are
+// ignored.
+func addWeekly(stats *acmregister.RegistrationStats, week, n int) {
+	if week >= 0 && week < len(stats.Weekly) {
+		stats.Weekly[week] += n
+	}
+}
+
+// marshalFormFields encodes the form fields as a JSON array.
+func marshalFormFields(fields []acmregister.FormField) ([]byte, error) {
+	if len(fields) == 0 {
+		return []byte("[]"), nil
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot encode form fields as JSON")
+	}
+	return b, nil
+}
+
+// unmarshalFormFields decodes the JSON array of form fields. Nil is returned
+// if there are none.
+func unmarshalFormFields(b []byte) ([]acmregister.FormField, error) {
+	var fields []acmregister.FormField
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, errors.Wrap(err, "cannot decode form fields")
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
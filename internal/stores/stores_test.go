@@ -0,0 +1,49 @@
+package stores_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/diamondburned/acmregister/internal/stores"
+	"github.com/diamondburned/acmregister/internal/stores/storetest"
+)
+
+func TestMemory(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) stores.StoreCloser {
+		return stores.NewMemory()
+	})
+}
+
+func TestSQLite(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) stores.StoreCloser {
+		path := filepath.Join(t.TempDir(), "acmregister.db")
+
+		s, err := stores.NewSQLite(context.Background(), path)
+		if err != nil {
+			t.Fatal("cannot open SQLite:", err)
+		}
+
+		return s
+	})
+}
+
+// TestPostgreSQL runs the test suite against the database at
+// $POSTGRESQL_URL. Tests only create and delete their own guilds, so an
+// existing database can be used.
+func TestPostgreSQL(t *testing.T) {
+	url := os.Getenv("POSTGRESQL_URL")
+	if url == "" {
+		t.Skip("$POSTGRESQL_URL not set, skipping")
+	}
+
+	storetest.Run(t, func(t *testing.T) stores.StoreCloser {
+		s, err := stores.NewPostgreSQL(context.Background(), url)
+		if err != nil {
+			t.Fatal("cannot connect to PostgreSQL:", err)
+		}
+
+		return s
+	})
+}
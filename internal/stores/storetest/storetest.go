@@ -0,0 +1,1425 @@
+// Package storetest provides a conformance test suite for store drivers. Every
+// driver should pass it to prove that it behaves identically to the others.
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/acmregister/acmregister"
+	"github.com/diamondburned/acmregister/acmregister/verifyemail"
+	"github.com/diamondburned/acmregister/internal/stores"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/pkg/errors"
+)
+
+// TimeTraveler is implemented by stores that can pretend that time has
+// passed. The tests that rely on submissions expiring are skipped for stores
+// that don't implement it.
+type TimeTraveler interface {
+	// Advance makes the store behave as if d has passed for the given guild.
+	// Other guilds must be left alone, since the database may be shared.
+	Advance(guildID discord.GuildID, d time.Duration) error
+}
+
+// NewStoreFunc creates a new store for a single test. The store is closed
+// once the test is done.
+type NewStoreFunc func(t *testing.T) stores.StoreCloser
+
+// Run runs the whole test suite on the stores returned by newStore. Each test
+// works on its own guilds, so newStore may return stores that share the same
+// database.
+func Run(t *testing.T, newStore NewStoreFunc) {
+	tests := []struct {
+		name string
+		test func(*testing.T, stores.StoreCloser)
+	}{
+		{"KnownGuildStore", testKnownGuildStore},
+		{"MemberStore", testMemberStore},
+		{"ListMembers", testListMembers},
+		{"SearchMembers", testSearchMembers},
+		{"MemberDeparted", testMemberDeparted},
+		{"UpdateMemberMetadata", testUpdateMemberMetadata},
+		{"SubmissionStore", testSubmissionStore},
+		{"ApprovalStore", testApprovalStore},
+		{"MemberEvents", testMemberEvents},
+		{"AttendanceStore", testAttendanceStore},
+		{"EventStore", testEventStore},
+		{"RegistrationStats", testRegistrationStats},
+		{"PINStore", testPINStore},
+		{"DeleteGuildCascade", testDeleteGuildCascade},
+		{"WithContext", testWithContext},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := newStore(t)
+			t.Cleanup(func() { store.Close() })
+
+			test.test(t, store)
+		})
+	}
+}
+
+var lastID = time.Now().UnixNano()
+
+// newID returns a snowflake that hasn't been used in this process yet.
+func newID() discord.Snowflake {
+	return discord.Snowflake(atomic.AddInt64(&lastID, 1))
+}
+
+// initGuild initializes a new guild and deletes it once the test is done.
+func initGuild(t *testing.T, s stores.StoreCloser) acmregister.KnownGuild {
+	t.Helper()
+
+	guild := acmregister.KnownGuild{
+		GuildID:           discord.GuildID(newID()),
+		ChannelID:         discord.ChannelID(newID()),
+		RoleID:            discord.RoleID(newID()),
+		InitUserID:        discord.UserID(newID()),
+		RegisteredMessage: "You're all set!",
+		RegisterMessageID: discord.MessageID(newID()),
+		Messages: acmregister.GuildMessages{
+			RegisterButtonLabel: "Sign up",
+		},
+		FormFields: []acmregister.FormField{
+			{Name: "major", Label: "Major", Required: true, MaxLength: 60},
+		},
+		Nickname: acmregister.NicknameSettings{Disabled: true},
+	}
+
+	if err := s.InitGuild(guild); err != nil {
+		t.Fatal("cannot init guild:", err)
+	}
+
+	t.Cleanup(func() { s.DeleteGuild(guild.GuildID) })
+	return guild
+}
+
+func newMember(guildID discord.GuildID, email acmregister.Email) acmregister.Member {
+	return acmregister.Member{
+		GuildID: guildID,
+		UserID:  discord.UserID(newID()),
+		Metadata: acmregister.MemberMetadata{
+			Email:     email,
+			FirstName: "Ferris",
+			LastName:  "Crab",
+			Pronouns:  acmregister.TheyThem,
+		},
+		RegisteredAt: registeredAt,
+	}
+}
+
+// registeredAt is the registration time of members made by newMember. Stores
+// only need to keep it to the second.
+var registeredAt = time.Date(2022, time.August, 22, 10, 0, 0, 0, time.UTC)
+
+func testKnownGuildStore(t *testing.T, s stores.StoreCloser) {
+	guild := initGuild(t, s)
+
+	got, err := s.GuildInfo(guild.GuildID)
+	if err != nil {
+		t.Fatal("cannot get guild info:", err)
+	}
+	assertEq(t, "guild info", *got, guild)
+
+	if err := s.InitGuild(guild); err == nil {
+		t.Error("initializing the same guild twice succeeded")
+	}
+
+	adminRoleID := discord.RoleID(newID())
+	if err := s.GuildSetAdminRole(guild.GuildID, adminRoleID); err != nil {
+		t.Fatal("cannot set admin role:", err)
+	}
+
+	got, err = s.GuildInfo(guild.GuildID)
+	if err != nil {
+		t.Fatal("cannot get guild info:", err)
+	}
+	assertEq(t, "admin role", got.AdminRoleID, adminRoleID)
+
+	roleID := discord.RoleID(newID())
+	if err := s.GuildSetRole(guild.GuildID, roleID); err != nil {
+		t.Fatal("cannot set role:", err)
+	}
+
+	got, err = s.GuildInfo(guild.GuildID)
+	if err != nil {
+		t.Fatal("cannot get guild info:", err)
+	}
+	assertEq(t, "role", got.RoleID, roleID)
+	assertEq(t, "admin role after setting role", got.AdminRoleID, adminRoleID)
+
+	messages := acmregister.GuildMessages{
+		VerifyPINMessage:     "Check your inbox!",
+		VerifyPINButtonLabel: "Enter PIN",
+	}
+	if err := s.GuildSetMessages(guild.GuildID, "Welcome!", messages); err != nil {
+		t.Fatal("cannot set messages:", err)
+	}
+
+	got, err = s.GuildInfo(guild.GuildID)
+	if err != nil {
+		t.Fatal("cannot get guild info:", err)
+	}
+	assertEq(t, "registered message", got.RegisteredMessage, "Welcome!")
+	assertEq(t, "messages", got.Messages, messages)
+
+	channelID := discord.ChannelID(newID())
+	messageID := discord.MessageID(newID())
+	if err := s.GuildSetRegisterMessage(guild.GuildID, channelID, messageID); err != nil {
+		t.Fatal("cannot set register message:", err)
+	}
+
+	got, err = s.GuildInfo(guild.GuildID)
+	if err != nil {
+		t.Fatal("cannot get guild info:", err)
+	}
+	assertEq(t, "register channel", got.ChannelID, channelID)
+	assertEq(t, "register message", got.RegisterMessageID, messageID)
+
+	formFields := []acmregister.FormField{
+		{Name: "grad_year", Label: "Graduation Year", Pattern: `\d{4}`},
+		{Name: "student_id", Label: "Student ID", Required: true, MinLength: 9, MaxLength: 9},
+	}
+	if err := s.GuildSetFormFields(guild.GuildID, formFields); err != nil {
+		t.Fatal("cannot set form fields:", err)
+	}
+
+	got, err = s.GuildInfo(guild.GuildID)
+	if err != nil {
+		t.Fatal("cannot get guild info:", err)
+	}
+	assertEq(t, "form fields", got.FormFields, formFields)
+
+	if err := s.GuildSetFormFields(guild.GuildID, nil); err != nil {
+		t.Fatal("cannot clear form fields:", err)
+	}
+
+	got, err = s.GuildInfo(guild.GuildID)
+	if err != nil {
+		t.Fatal("cannot get guild info:", err)
+	}
+	assertEq(t, "cleared form fields", got.FormFields, nil)
+
+	nickname := acmregister.NicknameSettings{Template: "{first} {last_initial}."}
+	if err := s.GuildSetNickname(guild.GuildID, nickname); err != nil {
+		t.Fatal("cannot set nickname settings:", err)
+	}
+
+	got, err = s.GuildInfo(guild.GuildID)
+	if err != nil {
+		t.Fatal("cannot get guild info:", err)
+	}
+	assertEq(t, "nickname settings", got.Nickname, nickname)
+
+	approvalChannelID := discord.ChannelID(newID())
+	if err := s.GuildSetApprovalChannel(guild.GuildID, approvalChannelID); err != nil {
+		t.Fatal("cannot set approval channel:", err)
+	}
+
+	got, err = s.GuildInfo(guild.GuildID)
+	if err != nil {
+		t.Fatal("cannot get guild info:", err)
+	}
+	assertEq(t, "approval channel", got.ApprovalChannelID, approvalChannelID)
+
+	if err := s.GuildSetApprovalChannel(guild.GuildID, 0); err != nil {
+		t.Fatal("cannot clear approval channel:", err)
+	}
+
+	got, err = s.GuildInfo(guild.GuildID)
+	if err != nil {
+		t.Fatal("cannot get guild info:", err)
+	}
+	assertEq(t, "cleared approval channel", got.ApprovalChannelID, discord.ChannelID(0))
+
+	auditChannelID := discord.ChannelID(newID())
+	if err := s.GuildSetAuditChannel(guild.GuildID, auditChannelID); err != nil {
+		t.Fatal("cannot set audit channel:", err)
+	}
+
+	got, err = s.GuildInfo(guild.GuildID)
+	if err != nil {
+		t.Fatal("cannot get guild info:", err)
+	}
+	assertEq(t, "audit channel", got.AuditChannelID, auditChannelID)
+
+	unknownID := discord.GuildID(newID())
+
+	_, err = s.GuildInfo(unknownID)
+	assertErr(t, "unknown guild info", err, acmregister.ErrNotFound)
+
+	err = s.GuildSetAdminRole(unknownID, adminRoleID)
+	assertErr(t, "unknown guild admin role", err, acmregister.ErrNotFound)
+
+	err = s.GuildSetRole(unknownID, roleID)
+	assertErr(t, "unknown guild role", err, acmregister.ErrNotFound)
+
+	err = s.GuildSetMessages(unknownID, "Welcome!", messages)
+	assertErr(t, "unknown guild messages", err, acmregister.ErrNotFound)
+
+	err = s.GuildSetRegisterMessage(unknownID, channelID, messageID)
+	assertErr(t, "unknown guild register message", err, acmregister.ErrNotFound)
+
+	err = s.GuildSetFormFields(unknownID, formFields)
+	assertErr(t, "unknown guild form fields", err, acmregister.ErrNotFound)
+
+	err = s.GuildSetNickname(unknownID, nickname)
+	assertErr(t, "unknown guild nickname settings", err, acmregister.ErrNotFound)
+
+	err = s.GuildSetApprovalChannel(unknownID, approvalChannelID)
+	assertErr(t, "unknown guild approval channel", err, acmregister.ErrNotFound)
+
+	err = s.GuildSetAuditChannel(unknownID, auditChannelID)
+	assertErr(t, "unknown guild audit channel", err, acmregister.ErrNotFound)
+
+	err = s.DeleteGuild(unknownID)
+	assertErr(t, "unknown guild deletion", err, acmregister.ErrNotFound)
+
+	if err := s.DeleteGuild(guild.GuildID); err != nil {
+		t.Fatal("cannot delete guild:", err)
+	}
+
+	_, err = s.GuildInfo(guild.GuildID)
+	assertErr(t, "deleted guild info", err, acmregister.ErrNotFound)
+}
+
+func testMemberStore(t *testing.T, s stores.StoreCloser) {
+	guild := initGuild(t, s)
+	member := newMember(guild.GuildID, "ferris@csu.fullerton.edu")
+	member.Metadata.Extra = map[string]string{"major": "Computer Science"}
+
+	_, err := s.MemberInfo(guild.GuildID, member.UserID)
+	assertErr(t, "unregistered member info", err, acmregister.ErrNotFound)
+
+	if err := s.RegisterMember(member); err != nil {
+		t.Fatal("cannot register member:", err)
+	}
+
+	got, err := s.MemberInfo(guild.GuildID, member.UserID)
+	if err != nil {
+		t.Fatal("cannot get member info:", err)
+	}
+	assertEq(t, "member info", *got, member.Metadata)
+
+	err = s.RegisterMember(member)
+	assertErr(t, "registering the same user twice", err, acmregister.ErrMemberAlreadyExists)
+
+	sameEmail := newMember(guild.GuildID, member.Metadata.Email)
+	err = s.RegisterMember(sameEmail)
+	assertErr(t, "registering the same email twice", err, acmregister.ErrMemberAlreadyExists)
+
+	otherGuild := initGuild(t, s)
+	sameEmail.GuildID = otherGuild.GuildID
+	if err := s.RegisterMember(sameEmail); err != nil {
+		t.Error("cannot register the same email in another guild:", err)
+	}
+
+	if err := s.UnregisterMember(guild.GuildID, member.UserID, 0); err != nil {
+		t.Fatal("cannot unregister member:", err)
+	}
+
+	_, err = s.MemberInfo(guild.GuildID, member.UserID)
+	assertErr(t, "unregistered member info", err, acmregister.ErrNotFound)
+
+	err = s.UnregisterMember(guild.GuildID, member.UserID, 0)
+	assertErr(t, "unregistering twice", err, acmregister.ErrNotFound)
+
+	// The email is free again once its owner is gone.
+	if err := s.RegisterMember(member); err != nil {
+		t.Error("cannot register member again:", err)
+	}
+}
+
+func testListMembers(t *testing.T, s stores.StoreCloser) {
+	guild := initGuild(t, s)
+
+	members, err := s.ListMembers(guild.GuildID, acmregister.MemberCursor{}, 10)
+	if err != nil {
+		t.Fatal("cannot list members:", err)
+	}
+	assertEq(t, "number of members", len(members), 0)
+
+	registered := make([]acmregister.Member, 7)
+	for i := range registered {
+		registered[i] = newMember(guild.GuildID, acmregister.Email(fmt.Sprintf("ferris%d@csu.fullerton.edu", i)))
+		if i == 3 {
+			registered[i].RegisteredBy = discord.UserID(newID())
+		}
+		if err := s.RegisterMember(registered[i]); err != nil {
+			t.Fatal("cannot register member:", err)
+		}
+	}
+
+	// Members in other guilds must not show up.
+	otherGuild := initGuild(t, s)
+	if err := s.RegisterMember(newMember(otherGuild.GuildID, "crab@csu.fullerton.edu")); err != nil {
+		t.Fatal("cannot register member:", err)
+	}
+
+	var listed []acmregister.Member
+	var cursor acmregister.MemberCursor
+	for {
+		page, err := s.ListMembers(guild.GuildID, cursor, 3)
+		if err != nil {
+			t.Fatal("cannot list members:", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		if len(page) > 3 {
+			t.Fatalf("page has %d members, expected at most 3", len(page))
+		}
+		listed = append(listed, page...)
+		cursor.UserID = page[len(page)-1].UserID
+	}
+	assertMembers(t, "listed members", listed, registered)
+
+	// Go back from the end.
+	page, err := s.ListMembers(guild.GuildID, acmregister.MemberCursor{
+		UserID: registered[5].UserID,
+		Before: true,
+	}, 3)
+	if err != nil {
+		t.Fatal("cannot list members before:", err)
+	}
+	assertMembers(t, "members before", page, registered[2:5])
+
+	page, err = s.ListMembers(guild.GuildID, acmregister.MemberCursor{
+		UserID: registered[1].UserID,
+		Before: true,
+	}, 3)
+	if err != nil {
+		t.Fatal("cannot list members before:", err)
+	}
+	assertMembers(t, "members before", page, registered[:1])
+
+	// Members without a registration time are registered now.
+	now := newMember(guild.GuildID, "ferris-now@csu.fullerton.edu")
+	now.RegisteredAt = time.Time{}
+	if err := s.RegisterMember(now); err != nil {
+		t.Fatal("cannot register member:", err)
+	}
+
+	page, err = s.ListMembers(guild.GuildID, acmregister.MemberCursor{UserID: registered[6].UserID}, 1)
+	if err != nil {
+		t.Fatal("cannot list members after:", err)
+	}
+	if len(page) != 1 || page[0].UserID != now.UserID {
+		t.Fatalf("unexpected members after the last one: %+v", page)
+	}
+	if d := time.Since(page[0].RegisteredAt); d < -time.Minute || d > time.Minute {
+		t.Errorf("member registered at %v, expected about now", page[0].RegisteredAt)
+	}
+}
+
+func testSearchMembers(t *testing.T, s stores.StoreCloser) {
+	guild := initGuild(t, s)
+
+	ferris := newMember(guild.GuildID, "ferris@csu.fullerton.edu")
+	gopher := newMember(guild.GuildID, "gopher@fullerton.edu")
+	gopher.Metadata.FirstName = "Gordon"
+	gopher.Metadata.LastName = "Gopher"
+	percent := newMember(guild.GuildID, "100%_real@fullerton.edu")
+	percent.Metadata.FirstName = "Real"
+	percent.Metadata.LastName = ""
+
+	for _, m := range []acmregister.Member{ferris, gopher, percent} {
+		if err := s.RegisterMember(m); err != nil {
+			t.Fatal("cannot register member:", err)
+		}
+	}
+
+	otherGuild := initGuild(t, s)
+	if err := s.RegisterMember(newMember(otherGuild.GuildID, "ferris2@csu.fullerton.edu")); err != nil {
+		t.Fatal("cannot register member:", err)
+	}
+
+	tests := []struct {
+		query    string
+		expected []acmregister.Member
+	}{
+		{"ferris@", []acmregister.Member{ferris}},
+		{"FULLERTON.EDU", []acmregister.Member{ferris, gopher, percent}},
+		{"gordon", []acmregister.Member{gopher}},
+		{"gopher", []acmregister.Member{gopher}},
+		{"ris cr", []acmregister.Member{ferris}},
+		{"%", []acmregister.Member{percent}},
+		{"0%_", []acmregister.Member{percent}},
+		{"_", []acmregister.Member{percent}},
+		{"nobody", nil},
+	}
+
+	for _, test := range tests {
+		found, err := s.SearchMembers(guild.GuildID, test.query, 10)
+		if err != nil {
+			t.Fatalf("cannot search %q: %v", test.query, err)
+		}
+		if len(found) == 0 && len(test.expected) == 0 {
+			continue
+		}
+		assertMembers(t, fmt.Sprintf("members matching %q", test.query), found, test.expected)
+	}
+
+	found, err := s.SearchMembers(guild.GuildID, "fullerton", 2)
+	if err != nil {
+		t.Fatal("cannot search:", err)
+	}
+	assertMembers(t, "limited search results", found, []acmregister.Member{ferris, gopher})
+}
+
+func testMemberDeparted(t *testing.T, s stores.StoreCloser) {
+	guild := initGuild(t, s)
+
+	member := newMember(guild.GuildID, "ferris@csu.fullerton.edu")
+	if err := s.RegisterMember(member); err != nil {
+		t.Fatal("cannot register member:", err)
+	}
+
+	departedAt := registeredAt.Add(24 * time.Hour)
+	if err := s.SetMemberDeparted(guild.GuildID, member.UserID, departedAt); err != nil {
+		t.Fatal("cannot mark member as departed:", err)
+	}
+
+	departed := member
+	departed.DepartedAt = departedAt
+
+	listed, err := s.ListMembers(guild.GuildID, acmregister.MemberCursor{}, 10)
+	if err != nil {
+		t.Fatal("cannot list members:", err)
+	}
+	assertMembers(t, "departed members", listed, []acmregister.Member{departed})
+
+	// Departed members are still registered.
+	_, err = s.MemberInfo(guild.GuildID, member.UserID)
+	if err != nil {
+		t.Error("cannot get departed member info:", err)
+	}
+
+	if err := s.SetMemberDeparted(guild.GuildID, member.UserID, time.Time{}); err != nil {
+		t.Fatal("cannot mark member as rejoined:", err)
+	}
+
+	listed, err = s.ListMembers(guild.GuildID, acmregister.MemberCursor{}, 10)
+	if err != nil {
+		t.Fatal("cannot list members:", err)
+	}
+	assertMembers(t, "rejoined members", listed, []acmregister.Member{member})
+
+	err = s.SetMemberDeparted(guild.GuildID, discord.UserID(newID()), departedAt)
+	assertErr(t, "marking unknown member", err, acmregister.ErrNotFound)
+}
+
+func testUpdateMemberMetadata(t *testing.T, s stores.StoreCloser) {
+	guild := initGuild(t, s)
+
+	member := newMember(guild.GuildID, "ferris@csu.fullerton.edu")
+	if err := s.RegisterMember(member); err != nil {
+		t.Fatal("cannot register member:", err)
+	}
+
+	other := newMember(guild.GuildID, "crab@csu.fullerton.edu")
+	if err := s.RegisterMember(other); err != nil {
+		t.Fatal("cannot register other member:", err)
+	}
+
+	updated := member.Metadata
+	updated.Email = "ferris2@csu.fullerton.edu"
+	updated.Pronouns = acmregister.AnyPronouns
+	updated.Extra = map[string]string{"major": "Computer Science"}
+
+	// Saved submissions are discarded once the update goes through.
+	if err := s.SaveSubmission(acmregister.Member{
+		GuildID:  guild.GuildID,
+		UserID:   member.UserID,
+		Metadata: updated,
+	}); err != nil {
+		t.Fatal("cannot save submission:", err)
+	}
+
+	if err := s.UpdateMemberMetadata(guild.GuildID, member.UserID, updated); err != nil {
+		t.Fatal("cannot update member metadata:", err)
+	}
+
+	got, err := s.MemberInfo(guild.GuildID, member.UserID)
+	if err != nil {
+		t.Fatal("cannot get member info:", err)
+	}
+	assertEq(t, "updated member info", *got, updated)
+
+	_, err = s.RestoreSubmission(guild.GuildID, member.UserID)
+	assertErr(t, "submission after update", err, acmregister.ErrNotFound)
+
+	// The old email is free again, but the other member's isn't.
+	if err := s.RegisterMember(newMember(guild.GuildID, member.Metadata.Email)); err != nil {
+		t.Error("cannot register the old email:", err)
+	}
+
+	taken := updated
+	taken.Email = other.Metadata.Email
+	err = s.UpdateMemberMetadata(guild.GuildID, member.UserID, taken)
+	assertErr(t, "updating to a taken email", err, acmregister.ErrMemberAlreadyExists)
+
+	got, err = s.MemberInfo(guild.GuildID, member.UserID)
+	if err != nil {
+		t.Fatal("cannot get member info:", err)
+	}
+	assertEq(t, "member info after failed update", *got, updated)
+
+	err = s.UpdateMemberMetadata(guild.GuildID, discord.UserID(newID()), updated)
+	assertErr(t, "updating unknown member", err, acmregister.ErrNotFound)
+}
+
+func testApprovalStore(t *testing.T, s stores.StoreCloser) {
+	guild := initGuild(t, s)
+
+	member := newMember(guild.GuildID, "ferris@csu.fullerton.edu")
+	approval := acmregister.Approval{
+		GuildID:     guild.GuildID,
+		UserID:      member.UserID,
+		Metadata:    member.Metadata,
+		SubmittedAt: registeredAt,
+	}
+
+	// Submitting for approval discards the saved submission.
+	if err := s.SaveSubmission(member); err != nil {
+		t.Fatal("cannot save submission:", err)
+	}
+
+	if err := s.SubmitApproval(approval); err != nil {
+		t.Fatal("cannot submit approval:", err)
+	}
+
+	_, err := s.RestoreSubmission(guild.GuildID, member.UserID)
+	assertErr(t, "submission after submitting approval", err, acmregister.ErrNotFound)
+
+	got, err := s.ApprovalInfo(guild.GuildID, member.UserID)
+	if err != nil {
+		t.Fatal("cannot get approval info:", err)
+	}
+	// Stores only need to keep the same instant.
+	if !got.SubmittedAt.Equal(approval.SubmittedAt) {
+		t.Errorf("unexpected submission time %v, expected %v", got.SubmittedAt, approval.SubmittedAt)
+	}
+	got.SubmittedAt = approval.SubmittedAt
+	assertEq(t, "approval info", *got, approval)
+
+	_, err = s.MemberInfo(guild.GuildID, member.UserID)
+	assertErr(t, "member info before approval", err, acmregister.ErrNotFound)
+
+	metadata, err := s.ApproveMember(guild.GuildID, member.UserID, guild.InitUserID)
+	if err != nil {
+		t.Fatal("cannot approve member:", err)
+	}
+	assertEq(t, "approved metadata", *metadata, member.Metadata)
+
+	gotMember, err := s.MemberInfo(guild.GuildID, member.UserID)
+	if err != nil {
+		t.Fatal("cannot get approved member info:", err)
+	}
+	assertEq(t, "approved member info", *gotMember, member.Metadata)
+
+	_, err = s.ApprovalInfo(guild.GuildID, member.UserID)
+	assertErr(t, "approval info after approval", err, acmregister.ErrNotFound)
+
+	_, err = s.ApproveMember(guild.GuildID, member.UserID, guild.InitUserID)
+	assertErr(t, "approving twice", err, acmregister.ErrNotFound)
+
+	// Denied approvals are kept with their reason, and can't be approved.
+	denied := newMember(guild.GuildID, "crab@csu.fullerton.edu")
+	if err := s.SubmitApproval(acmregister.Approval{
+		GuildID:  guild.GuildID,
+		UserID:   denied.UserID,
+		Metadata: denied.Metadata,
+	}); err != nil {
+		t.Fatal("cannot submit approval:", err)
+	}
+
+	adminID := discord.UserID(newID())
+	if err := s.DenyMember(guild.GuildID, denied.UserID, adminID, "not a student"); err != nil {
+		t.Fatal("cannot deny member:", err)
+	}
+
+	got, err = s.ApprovalInfo(guild.GuildID, denied.UserID)
+	if err != nil {
+		t.Fatal("cannot get denied approval info:", err)
+	}
+	if got.IsPending() {
+		t.Error("denied approval is still pending")
+	}
+	if got.SubmittedAt.IsZero() {
+		t.Error("approval submitted without a time has no submission time")
+	}
+	assertEq(t, "denied by", got.DeniedBy, adminID)
+	assertEq(t, "deny reason", got.DenyReason, "not a student")
+
+	err = s.DenyMember(guild.GuildID, denied.UserID, adminID, "again")
+	assertErr(t, "denying twice", err, acmregister.ErrNotFound)
+
+	_, err = s.ApproveMember(guild.GuildID, denied.UserID, guild.InitUserID)
+	assertErr(t, "approving a denied member", err, acmregister.ErrNotFound)
+
+	// Submitting again makes the approval pending again.
+	if err := s.SubmitApproval(acmregister.Approval{
+		GuildID:  guild.GuildID,
+		UserID:   denied.UserID,
+		Metadata: denied.Metadata,
+	}); err != nil {
+		t.Fatal("cannot submit approval again:", err)
+	}
+
+	got, err = s.ApprovalInfo(guild.GuildID, denied.UserID)
+	if err != nil {
+		t.Fatal("cannot get resubmitted approval info:", err)
+	}
+	if !got.IsPending() {
+		t.Error("resubmitted approval is not pending")
+	}
+	assertEq(t, "resubmitted deny reason", got.DenyReason, "")
+
+	// Approvals can't take the email of a registered member.
+	taken := newMember(guild.GuildID, member.Metadata.Email)
+	if err := s.SubmitApproval(acmregister.Approval{
+		GuildID:  guild.GuildID,
+		UserID:   taken.UserID,
+		Metadata: taken.Metadata,
+	}); err != nil {
+		t.Fatal("cannot submit approval:", err)
+	}
+
+	_, err = s.ApproveMember(guild.GuildID, taken.UserID, guild.InitUserID)
+	assertErr(t, "approving a taken email", err, acmregister.ErrMemberAlreadyExists)
+
+	unknownID := discord.UserID(newID())
+
+	_, err = s.ApprovalInfo(guild.GuildID, unknownID)
+	assertErr(t, "unknown approval info", err, acmregister.ErrNotFound)
+
+	err = s.DenyMember(guild.GuildID, unknownID, adminID, "unknown")
+	assertErr(t, "denying unknown approval", err, acmregister.ErrNotFound)
+}
+
+func testMemberEvents(t *testing.T, s stores.StoreCloser) {
+	guild := initGuild(t, s)
+	member := newMember(guild.GuildID, "ferris@csu.fullerton.edu")
+
+	events, err := s.MemberEvents(guild.GuildID, member.UserID)
+	if err != nil {
+		t.Fatal("cannot get events of unknown member:", err)
+	}
+	assertEq(t, "events of unknown member", len(events), 0)
+
+	if err := s.RegisterMember(member); err != nil {
+		t.Fatal("cannot register member:", err)
+	}
+
+	edited := member.Metadata
+	edited.FirstName = "Corro"
+	if err := s.UpdateMemberMetadata(guild.GuildID, member.UserID, edited); err != nil {
+		t.Fatal("cannot update member metadata:", err)
+	}
+
+	if err := s.AddMemberEvent(acmregister.MemberEvent{
+		GuildID: guild.GuildID,
+		UserID:  member.UserID,
+		Type:    acmregister.MemberVerified,
+		ActorID: member.UserID,
+	}); err != nil {
+		t.Fatal("cannot add verified event:", err)
+	}
+
+	adminID := discord.UserID(newID())
+	if err := s.UnregisterMember(guild.GuildID, member.UserID, adminID); err != nil {
+		t.Fatal("cannot unregister member:", err)
+	}
+
+	// Failed changes don't leave events behind.
+	s.UnregisterMember(guild.GuildID, member.UserID, adminID)
+
+	// Someone else's history is not included.
+	other := newMember(guild.GuildID, "crab@csu.fullerton.edu")
+	other.RegisteredBy = adminID
+	if err := s.RegisterMember(other); err != nil {
+		t.Fatal("cannot register other member:", err)
+	}
+
+	events, err = s.MemberEvents(guild.GuildID, member.UserID)
+	if err != nil {
+		t.Fatal("cannot get member events:", err)
+	}
+
+	type event struct {
+		Type    acmregister.MemberEventType
+		ActorID discord.UserID
+	}
+
+	got := make([]event, len(events))
+	for i, ev := range events {
+		got[i] = event{ev.Type, ev.ActorID}
+		if ev.GuildID != guild.GuildID || ev.UserID != member.UserID {
+			t.Errorf("event %d belongs to %v/%v", i, ev.GuildID, ev.UserID)
+		}
+		if ev.Time.IsZero() {
+			t.Errorf("event %d has no time", i)
+		}
+	}
+
+	assertEq(t, "member events", got, []event{
+		{acmregister.MemberRegistered, member.UserID},
+		{acmregister.MemberEdited, member.UserID},
+		{acmregister.MemberVerified, member.UserID},
+		{acmregister.MemberUnregistered, adminID},
+	})
+
+	// Stores only need to keep the same second.
+	if !events[0].Time.Truncate(time.Second).Equal(member.RegisteredAt.Truncate(time.Second)) {
+		t.Errorf("registered at %v, expected %v", events[0].Time, member.RegisteredAt)
+	}
+
+	events, err = s.MemberEvents(guild.GuildID, other.UserID)
+	if err != nil {
+		t.Fatal("cannot get other member events:", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("other member has %d events, expected 1", len(events))
+	}
+	assertEq(t, "registered by", events[0].ActorID, adminID)
+
+	// Approving credits the approver.
+	approved := newMember(guild.GuildID, "approved@csu.fullerton.edu")
+	if err := s.SubmitApproval(acmregister.Approval{
+		GuildID:  guild.GuildID,
+		UserID:   approved.UserID,
+		Metadata: approved.Metadata,
+	}); err != nil {
+		t.Fatal("cannot submit approval:", err)
+	}
+	if _, err := s.ApproveMember(guild.GuildID, approved.UserID, adminID); err != nil {
+		t.Fatal("cannot approve member:", err)
+	}
+
+	events, err = s.MemberEvents(guild.GuildID, approved.UserID)
+	if err != nil {
+		t.Fatal("cannot get approved member events:", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("approved member has %d events, expected 1", len(events))
+	}
+	assertEq(t, "approved event", event{events[0].Type, events[0].ActorID}, event{acmregister.MemberRegistered, adminID})
+}
+
+func testAttendanceStore(t *testing.T, s stores.StoreCloser) {
+	guild := initGuild(t, s)
+	eventID := discord.EventID(newID())
+
+	attendees, err := s.EventAttendees(guild.GuildID, eventID)
+	if err != nil {
+		t.Fatal("cannot get attendees of an empty event:", err)
+	}
+	assertEq(t, "attendees of an empty event", len(attendees), 0)
+
+	late := acmregister.Attendance{
+		GuildID:     guild.GuildID,
+		EventID:     eventID,
+		UserID:      discord.UserID(newID()),
+		CheckedInAt: registeredAt.Add(time.Hour),
+	}
+	early := acmregister.Attendance{
+		GuildID:     guild.GuildID,
+		EventID:     eventID,
+		UserID:      discord.UserID(newID()),
+		CheckedInAt: registeredAt,
+	}
+
+	for _, a := range []acmregister.Attendance{late, early} {
+		if err := s.CheckIn(a); err != nil {
+			t.Fatal("cannot check in:", err)
+		}
+	}
+
+	again := early
+	again.CheckedInAt = time.Time{}
+	err = s.CheckIn(again)
+	assertErr(t, "checking in twice", err, acmregister.ErrAlreadyCheckedIn)
+
+	// The same member may check in to other events.
+	otherEvent := early
+	otherEvent.EventID = discord.EventID(newID())
+	otherEvent.CheckedInAt = time.Time{}
+	if err := s.CheckIn(otherEvent); err != nil {
+		t.Fatal("cannot check in to another event:", err)
+	}
+
+	attendees, err = s.EventAttendees(guild.GuildID, eventID)
+	if err != nil {
+		t.Fatal("cannot get attendees:", err)
+	}
+	if len(attendees) != 2 {
+		t.Fatalf("got %d attendees, expected 2", len(attendees))
+	}
+	for i, expected := range []acmregister.Attendance{early, late} {
+		// Stores only need to keep the same instant.
+		if !attendees[i].CheckedInAt.Equal(expected.CheckedInAt) {
+			t.Errorf("attendee %d checked in at %v, expected %v", i, attendees[i].CheckedInAt, expected.CheckedInAt)
+		}
+		attendees[i].CheckedInAt = expected.CheckedInAt
+	}
+	assertEq(t, "attendees", attendees, []acmregister.Attendance{early, late})
+
+	attendees, err = s.EventAttendees(guild.GuildID, otherEvent.EventID)
+	if err != nil {
+		t.Fatal("cannot get attendees of the other event:", err)
+	}
+	if len(attendees) != 1 || attendees[0].UserID != otherEvent.UserID {
+		t.Errorf("unexpected attendees of the other event: %v", attendees)
+	} else if attendees[0].CheckedInAt.IsZero() {
+		t.Error("attendance checked in without a time has no time")
+	}
+
+	otherGuild := initGuild(t, s)
+	attendees, err = s.EventAttendees(otherGuild.GuildID, eventID)
+	if err != nil {
+		t.Fatal("cannot get attendees in another guild:", err)
+	}
+	assertEq(t, "attendees in another guild", len(attendees), 0)
+}
+
+func testEventStore(t *testing.T, s stores.StoreCloser) {
+	guild := initGuild(t, s)
+
+	event := acmregister.ScheduledEvent{
+		GuildID:   guild.GuildID,
+		EventID:   discord.EventID(newID()),
+		Name:      "Rust Workshop",
+		StartTime: registeredAt,
+	}
+
+	_, err := s.EventInfo(guild.GuildID, event.EventID)
+	assertErr(t, "unknown event info", err, acmregister.ErrNotFound)
+
+	if err := s.SaveEvent(event); err != nil {
+		t.Fatal("cannot save event:", err)
+	}
+
+	// Saving again replaces the snapshot.
+	event.Ended = true
+	if err := s.SaveEvent(event); err != nil {
+		t.Fatal("cannot save event again:", err)
+	}
+
+	got, err := s.EventInfo(guild.GuildID, event.EventID)
+	if err != nil {
+		t.Fatal("cannot get event info:", err)
+	}
+	// Stores only need to keep the same instant.
+	if !got.StartTime.Equal(event.StartTime) {
+		t.Errorf("unexpected start time %v, expected %v", got.StartTime, event.StartTime)
+	}
+	got.StartTime = event.StartTime
+	assertEq(t, "event info", *got, event)
+
+	later := acmregister.ScheduledEvent{
+		GuildID:   guild.GuildID,
+		EventID:   discord.EventID(newID()),
+		Name:      "Go Workshop",
+		StartTime: registeredAt.Add(7 * 24 * time.Hour),
+	}
+	meeting := acmregister.ScheduledEvent{
+		GuildID:   guild.GuildID,
+		EventID:   discord.EventID(newID()),
+		Name:      "General Meeting",
+		StartTime: registeredAt.Add(time.Hour),
+	}
+	for _, ev := range []acmregister.ScheduledEvent{later, meeting} {
+		if err := s.SaveEvent(ev); err != nil {
+			t.Fatal("cannot save event:", err)
+		}
+	}
+
+	searchIDs := func(query string, limit int) []discord.EventID {
+		t.Helper()
+
+		events, err := s.SearchEvents(guild.GuildID, query, limit)
+		if err != nil {
+			t.Fatalf("cannot search events for %q: %v", query, err)
+		}
+
+		ids := make([]discord.EventID, len(events))
+		for i, ev := range events {
+			ids[i] = ev.EventID
+		}
+		return ids
+	}
+
+	assertEq(t, "searching workshops", searchIDs("WORKSHOP", 10), []discord.EventID{later.EventID, event.EventID})
+	assertEq(t, "searching everything", searchIDs("", 10), []discord.EventID{later.EventID, meeting.EventID, event.EventID})
+	assertEq(t, "searching with a limit", searchIDs("", 1), []discord.EventID{later.EventID})
+	assertEq(t, "searching wildcards", len(searchIDs("%", 10)), 0)
+
+	betweenIDs := func(from, to time.Time) []discord.EventID {
+		t.Helper()
+
+		events, err := s.EventsBetween(guild.GuildID, from, to)
+		if err != nil {
+			t.Fatalf("cannot list events between %v and %v: %v", from, to, err)
+		}
+
+		ids := make([]discord.EventID, len(events))
+		for i, ev := range events {
+			ids[i] = ev.EventID
+		}
+		return ids
+	}
+
+	// The end of the range is excluded.
+	assertEq(t, "events in the first week",
+		betweenIDs(registeredAt, later.StartTime),
+		[]discord.EventID{event.EventID, meeting.EventID})
+	assertEq(t, "events in both weeks",
+		betweenIDs(registeredAt.Add(time.Hour), later.StartTime.Add(time.Second)),
+		[]discord.EventID{meeting.EventID, later.EventID})
+	assertEq(t, "events before the first", len(betweenIDs(registeredAt.Add(-time.Hour), registeredAt)), 0)
+
+	// Participants can be added before the event is saved.
+	unsaved := discord.EventID(newID())
+	alice := discord.UserID(newID())
+	bob := discord.UserID(newID())
+
+	if err := s.AddEventParticipants(guild.GuildID, unsaved, bob, alice); err != nil {
+		t.Fatal("cannot add participants:", err)
+	}
+	if err := s.AddEventParticipants(guild.GuildID, unsaved, alice); err != nil {
+		t.Fatal("cannot add a participant twice:", err)
+	}
+	if err := s.AddEventParticipants(guild.GuildID, event.EventID, alice); err != nil {
+		t.Fatal("cannot add participant to another event:", err)
+	}
+
+	participants, err := s.EventParticipants(guild.GuildID, unsaved)
+	if err != nil {
+		t.Fatal("cannot get participants:", err)
+	}
+	assertEq(t, "participants", participants, []discord.UserID{alice, bob})
+
+	if err := s.RemoveEventParticipant(guild.GuildID, unsaved, alice); err != nil {
+		t.Fatal("cannot remove participant:", err)
+	}
+	if err := s.RemoveEventParticipant(guild.GuildID, unsaved, alice); err != nil {
+		t.Fatal("cannot remove a participant twice:", err)
+	}
+
+	participants, err = s.EventParticipants(guild.GuildID, unsaved)
+	if err != nil {
+		t.Fatal("cannot get participants after removal:", err)
+	}
+	assertEq(t, "participants after removal", participants, []discord.UserID{bob})
+
+	participants, err = s.EventParticipants(guild.GuildID, event.EventID)
+	if err != nil {
+		t.Fatal("cannot get participants of another event:", err)
+	}
+	assertEq(t, "participants of another event", participants, []discord.UserID{alice})
+
+	otherGuild := initGuild(t, s)
+	_, err = s.EventInfo(otherGuild.GuildID, event.EventID)
+	assertErr(t, "event info in another guild", err, acmregister.ErrNotFound)
+
+	participants, err = s.EventParticipants(otherGuild.GuildID, unsaved)
+	if err != nil {
+		t.Fatal("cannot get participants in another guild:", err)
+	}
+	assertEq(t, "participants in another guild", len(participants), 0)
+}
+
+func testRegistrationStats(t *testing.T, s stores.StoreCloser) {
+	const week = 7 * 24 * time.Hour
+
+	guild := initGuild(t, s)
+	since := time.Now().Add(-2 * week)
+
+	thisWeek := newMember(guild.GuildID, "ferris@csu.fullerton.edu")
+	thisWeek.RegisteredAt = since.Add(time.Hour)
+
+	nextWeek := newMember(guild.GuildID, "crab@CSU.Fullerton.edu")
+	nextWeek.RegisteredAt = since.Add(week + time.Hour)
+	nextWeek.Metadata.Pronouns = acmregister.HeHim
+
+	// Members registered before the given time are still counted in the
+	// totals.
+	longAgo := newMember(guild.GuildID, "corro@gmail.com")
+	longAgo.Metadata.Pronouns = acmregister.HiddenPronouns
+
+	for _, m := range []acmregister.Member{thisWeek, nextWeek, longAgo} {
+		if err := s.RegisterMember(m); err != nil {
+			t.Fatal("cannot register member:", err)
+		}
+	}
+
+	verifying := newMember(guild.GuildID, "verifying@csu.fullerton.edu")
+	if err := s.SaveSubmission(verifying); err != nil {
+		t.Fatal("cannot save submission:", err)
+	}
+
+	pin, err := s.GeneratePIN(guild.GuildID, verifying.UserID)
+	if err != nil {
+		t.Fatal("cannot generate PIN:", err)
+	}
+
+	s.ValidatePIN(guild.GuildID, verifying.UserID, otherPIN(pin))
+	if _, err := s.ValidatePIN(guild.GuildID, verifying.UserID, pin); err != nil {
+		t.Fatal("cannot validate PIN:", err)
+	}
+
+	// Attempts in unknown guilds aren't recorded, since pin_attempts references
+	// known_guilds, so they must not fail with a foreign key error.
+	_, err = s.ValidatePIN(discord.GuildID(newID()), verifying.UserID, pin)
+	assertErr(t, "validating a PIN in an unknown guild", err, acmregister.ErrNotFound)
+
+	approval := newMember(guild.GuildID, "approval@csu.fullerton.edu")
+	if err := s.SubmitApproval(acmregister.Approval{
+		GuildID:  guild.GuildID,
+		UserID:   approval.UserID,
+		Metadata: approval.Metadata,
+	}); err != nil {
+		t.Fatal("cannot submit approval:", err)
+	}
+
+	stats, err := s.RegistrationStats(guild.GuildID, since)
+	if err != nil {
+		t.Fatal("cannot get registration stats:", err)
+	}
+
+	assertEq(t, "registration stats", *stats, acmregister.RegistrationStats{
+		Members: 3,
+		Weekly:  []int{1, 1, 0},
+		EmailHosts: map[string]int{
+			"csu.fullerton.edu": 2,
+			"gmail.com":         1,
+		},
+		Pronouns: map[acmregister.Pronouns]int{
+			acmregister.TheyThem:       1,
+			acmregister.HeHim:          1,
+			acmregister.HiddenPronouns: 1,
+		},
+		PendingSubmissions: 1,
+		PendingApprovals:   1,
+		PINAttempts:        2,
+		PINSuccesses:       1,
+	})
+
+	// PIN attempts before the given time are not counted.
+	stats, err = s.RegistrationStats(guild.GuildID, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal("cannot get future registration stats:", err)
+	}
+	assertEq(t, "future weekly registrations", stats.Weekly, []int{0})
+	assertEq(t, "future PIN attempts", stats.PINAttempts, 0)
+
+	otherGuild := initGuild(t, s)
+	stats, err = s.RegistrationStats(otherGuild.GuildID, since)
+	if err != nil {
+		t.Fatal("cannot get other guild's registration stats:", err)
+	}
+	assertEq(t, "other guild's members", stats.Members, 0)
+	assertEq(t, "other guild's PIN attempts", stats.PINAttempts, 0)
+}
+
+func testSubmissionStore(t *testing.T, s stores.StoreCloser) {
+	guild := initGuild(t, s)
+	member := newMember(guild.GuildID, "ferris@csu.fullerton.edu")
+
+	_, err := s.RestoreSubmission(guild.GuildID, member.UserID)
+	assertErr(t, "restoring nothing", err, acmregister.ErrNotFound)
+
+	if err := s.SaveSubmission(member); err != nil {
+		t.Fatal("cannot save submission:", err)
+	}
+
+	got, err := s.RestoreSubmission(guild.GuildID, member.UserID)
+	if err != nil {
+		t.Fatal("cannot restore submission:", err)
+	}
+	assertEq(t, "submission", *got, member.Metadata)
+
+	// Submissions don't have to be valid.
+	member.Metadata.Email = "not an email"
+	member.Metadata.Pronouns = "???"
+
+	if err := s.SaveSubmission(member); err != nil {
+		t.Fatal("cannot overwrite submission:", err)
+	}
+
+	got, err = s.RestoreSubmission(guild.GuildID, member.UserID)
+	if err != nil {
+		t.Fatal("cannot restore overwritten submission:", err)
+	}
+	assertEq(t, "overwritten submission", *got, member.Metadata)
+
+	// Registering consumes the submission.
+	member.Metadata.Email = "ferris@csu.fullerton.edu"
+	if err := s.RegisterMember(member); err != nil {
+		t.Fatal("cannot register member:", err)
+	}
+
+	_, err = s.RestoreSubmission(guild.GuildID, member.UserID)
+	assertErr(t, "restoring a registered submission", err, acmregister.ErrNotFound)
+
+	traveler, ok := s.(TimeTraveler)
+	if !ok {
+		t.Skip("store cannot time travel, skipping expiry tests")
+	}
+
+	expiring := newMember(guild.GuildID, "crab@csu.fullerton.edu")
+	if err := s.SaveSubmission(expiring); err != nil {
+		t.Fatal("cannot save submission:", err)
+	}
+
+	if err := traveler.Advance(guild.GuildID, acmregister.SubmissionSaveDuration-time.Minute); err != nil {
+		t.Fatal("cannot time travel:", err)
+	}
+
+	if _, err := s.RestoreSubmission(guild.GuildID, expiring.UserID); err != nil {
+		t.Fatal("submission expired too early:", err)
+	}
+
+	if err := traveler.Advance(guild.GuildID, 2*time.Minute); err != nil {
+		t.Fatal("cannot time travel:", err)
+	}
+
+	_, err = s.RestoreSubmission(guild.GuildID, expiring.UserID)
+	assertErr(t, "restoring an expired submission", err, acmregister.ErrNotFound)
+}
+
+func testPINStore(t *testing.T, s stores.StoreCloser) {
+	guild := initGuild(t, s)
+	member := newMember(guild.GuildID, "ferris@csu.fullerton.edu")
+
+	if err := s.SaveSubmission(member); err != nil {
+		t.Fatal("cannot save submission:", err)
+	}
+
+	pin, err := s.GeneratePIN(guild.GuildID, member.UserID)
+	if err != nil {
+		t.Fatal("cannot generate PIN:", err)
+	}
+
+	got, err := s.ValidatePIN(guild.GuildID, member.UserID, pin)
+	if err != nil {
+		t.Fatal("cannot validate PIN:", err)
+	}
+	assertEq(t, "validated submission", *got, member.Metadata)
+
+	_, err = s.ValidatePIN(guild.GuildID, member.UserID, otherPIN(pin))
+	assertErr(t, "validating the wrong PIN", err, acmregister.ErrNotFound)
+
+	_, err = s.ValidatePIN(guild.GuildID, discord.UserID(newID()), pin)
+	assertErr(t, "validating someone else's PIN", err, acmregister.ErrNotFound)
+
+	otherGuild := initGuild(t, s)
+	_, err = s.ValidatePIN(otherGuild.GuildID, member.UserID, pin)
+	assertErr(t, "validating the PIN in another guild", err, acmregister.ErrNotFound)
+
+	// PINs must be unique within a guild.
+	pins := map[verifyemail.PIN]discord.UserID{pin: member.UserID}
+	for i := 0; i < 50; i++ {
+		other := newMember(guild.GuildID, "")
+		if err := s.SaveSubmission(other); err != nil {
+			t.Fatal("cannot save submission:", err)
+		}
+
+		pin, err := s.GeneratePIN(guild.GuildID, other.UserID)
+		if err != nil {
+			t.Fatal("cannot generate PIN:", err)
+		}
+
+		if userID, ok := pins[pin]; ok {
+			t.Fatalf("PIN %s given to both %d and %d", pin, userID, other.UserID)
+		}
+		pins[pin] = other.UserID
+	}
+
+	// Registering consumes the submission, so the PIN can't be used anymore.
+	if err := s.RegisterMember(member); err != nil {
+		t.Fatal("cannot register member:", err)
+	}
+
+	_, err = s.ValidatePIN(guild.GuildID, member.UserID, pin)
+	assertErr(t, "validating a consumed PIN", err, acmregister.ErrNotFound)
+
+	traveler, ok := s.(TimeTraveler)
+	if !ok {
+		t.Skip("store cannot time travel, skipping expiry tests")
+	}
+
+	expiring := newMember(guild.GuildID, "crab@csu.fullerton.edu")
+	if err := s.SaveSubmission(expiring); err != nil {
+		t.Fatal("cannot save submission:", err)
+	}
+
+	pin, err = s.GeneratePIN(guild.GuildID, expiring.UserID)
+	if err != nil {
+		t.Fatal("cannot generate PIN:", err)
+	}
+
+	if err := traveler.Advance(guild.GuildID, acmregister.SubmissionSaveDuration+time.Minute); err != nil {
+		t.Fatal("cannot time travel:", err)
+	}
+
+	_, err = s.ValidatePIN(guild.GuildID, expiring.UserID, pin)
+	assertErr(t, "validating the PIN of an expired submission", err, acmregister.ErrNotFound)
+}
+
+func testDeleteGuildCascade(t *testing.T, s stores.StoreCloser) {
+	guild := initGuild(t, s)
+
+	member := newMember(guild.GuildID, "ferris@csu.fullerton.edu")
+	if err := s.RegisterMember(member); err != nil {
+		t.Fatal("cannot register member:", err)
+	}
+
+	submitter := newMember(guild.GuildID, "crab@csu.fullerton.edu")
+	if err := s.SaveSubmission(submitter); err != nil {
+		t.Fatal("cannot save submission:", err)
+	}
+
+	pin, err := s.GeneratePIN(guild.GuildID, submitter.UserID)
+	if err != nil {
+		t.Fatal("cannot generate PIN:", err)
+	}
+
+	eventID := discord.EventID(newID())
+	if err := s.CheckIn(acmregister.Attendance{
+		GuildID: guild.GuildID,
+		EventID: eventID,
+		UserID:  member.UserID,
+	}); err != nil {
+		t.Fatal("cannot check in:", err)
+	}
+
+	if err := s.SaveEvent(acmregister.ScheduledEvent{
+		GuildID:   guild.GuildID,
+		EventID:   eventID,
+		Name:      "Rust Workshop",
+		StartTime: registeredAt,
+	}); err != nil {
+		t.Fatal("cannot save event:", err)
+	}
+
+	if err := s.AddEventParticipants(guild.GuildID, eventID, member.UserID); err != nil {
+		t.Fatal("cannot add participant:", err)
+	}
+
+	if err := s.DeleteGuild(guild.GuildID); err != nil {
+		t.Fatal("cannot delete guild:", err)
+	}
+
+	// Bring the guild back to make sure nothing lingers.
+	if err := s.InitGuild(guild); err != nil {
+		t.Fatal("cannot init guild again:", err)
+	}
+
+	_, err = s.MemberInfo(guild.GuildID, member.UserID)
+	assertErr(t, "member info after deletion", err, acmregister.ErrNotFound)
+
+	_, err = s.RestoreSubmission(guild.GuildID, submitter.UserID)
+	assertErr(t, "submission after deletion", err, acmregister.ErrNotFound)
+
+	_, err = s.ValidatePIN(guild.GuildID, submitter.UserID, pin)
+	assertErr(t, "PIN after deletion", err, acmregister.ErrNotFound)
+
+	events, err := s.MemberEvents(guild.GuildID, member.UserID)
+	if err != nil {
+		t.Fatal("cannot get member events after deletion:", err)
+	}
+	// The member history is the exception: it must survive clearing the
+	// registration.
+	if len(events) != 1 {
+		t.Fatalf("expected 1 member event after deletion, got %d", len(events))
+	}
+	assertEq(t, "member event after deletion", events[0].Type, acmregister.MemberRegistered)
+
+	attendees, err := s.EventAttendees(guild.GuildID, eventID)
+	if err != nil {
+		t.Fatal("cannot get attendees after deletion:", err)
+	}
+	assertEq(t, "attendees after deletion", len(attendees), 0)
+
+	_, err = s.EventInfo(guild.GuildID, eventID)
+	assertErr(t, "event after deletion", err, acmregister.ErrNotFound)
+
+	participants, err := s.EventParticipants(guild.GuildID, eventID)
+	if err != nil {
+		t.Fatal("cannot get participants after deletion:", err)
+	}
+	assertEq(t, "participants after deletion", len(participants), 0)
+
+	// The same members can register again.
+	if err := s.RegisterMember(member); err != nil {
+		t.Error("cannot register member again:", err)
+	}
+}
+
+func testWithContext(t *testing.T, s stores.StoreCloser) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	guild := initGuild(t, s)
+
+	store := s.WithContext(ctx).(acmregister.Store)
+	if _, err := store.GuildInfo(guild.GuildID); err != nil {
+		t.Fatal("cannot get guild info with context:", err)
+	}
+
+	pinStore := s.WithContext(ctx).(verifyemail.PINStore)
+	_, err := pinStore.ValidatePIN(guild.GuildID, discord.UserID(newID()), 1234)
+	assertErr(t, "validating with context", err, acmregister.ErrNotFound)
+}
+
+func otherPIN(pin verifyemail.PIN) verifyemail.PIN {
+	if pin == 9999 {
+		return 1
+	}
+	return pin + 1
+}
+
+func assertEq[T any](t *testing.T, what string, got, expected T) {
+	t.Helper()
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected %s\n"+
+			"expected: %+v\n"+
+			"actual:   %+v",
+			what, expected, got)
+	}
+}
+
+// assertMembers is like assertEq, except the registration times only have to be
+// the same instant.
+func assertMembers(t *testing.T, what string, got, expected []acmregister.Member) {
+	t.Helper()
+	assertEq(t, what, utcMembers(got), utcMembers(expected))
+}
+
+func utcMembers(members []acmregister.Member) []acmregister.Member {
+	if members == nil {
+		return nil
+	}
+	utc := make([]acmregister.Member, len(members))
+	for i, m := range members {
+		m.RegisteredAt = m.RegisteredAt.UTC()
+		m.DepartedAt = m.DepartedAt.UTC()
+		utc[i] = m
+	}
+	return utc
+}
+
+func assertErr(t *testing.T, what string, err, expected error) {
+	t.Helper()
+	if !errors.Is(err, expected) {
+		t.Errorf("%s: expected error %q, got %v", what, expected, err)
+	}
+}
@@ -64,6 +64,14 @@ func main() {
 
 		ses.AddIntents(h.Intents())
 		ses.AddInteractionHandler(h)
+		ses.AddHandler(h.HandleMemberRemove)
+		ses.AddHandler(h.HandleMemberAdd)
+		ses.AddHandler(h.HandleGuildCreate)
+		ses.AddHandler(h.HandleScheduledEventCreate)
+		ses.AddHandler(h.HandleScheduledEventUpdate)
+		ses.AddHandler(h.HandleScheduledEventDelete)
+		ses.AddHandler(h.HandleScheduledEventUserAdd)
+		ses.AddHandler(h.HandleScheduledEventUserRemove)
 
 		start = func() {
 			log.Println("connecting to the Discord gateway...")